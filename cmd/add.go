@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/logger"
+	"modrinth-mod-updater/modrinth"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// addCmd represents the add command
+var addCmd = &cobra.Command{
+	Use:   "add <slug|id|url>...",
+	Short: "Follow projects and install them",
+	Long: `Follow one or more Modrinth projects and install a compatible version right away.
+
+Projects can be given as slug, project ID or Modrinth URL. Without MODRINTH_API_KEY the
+projects are added to a local follow list, which update uses in place of Modrinth follows.
+
+Example: modrinth-mod-updater add sodium lithium
+Example: modrinth-mod-updater add https://modrinth.com/mod/iris`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		addProjects(args)
+	},
+}
+
+// removeCmd represents the remove command
+var removeCmd = &cobra.Command{
+	Use:   "remove <slug>",
+	Short: "Unfollow a project and remove its file",
+	Long: `Unfollow a project, remove its installed file and forget it in the database.
+
+The file is moved to the versions directory when KEEP_OLD_VERSIONS is enabled and deleted otherwise.
+A warning is printed when other installed mods require the project.
+
+Example: modrinth-mod-updater remove sodium`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		removeProject(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+	rootCmd.AddCommand(removeCmd)
+}
+
+func addProjects(refs []string) {
+	cfg, client := bootstrap(".")
+
+	var downloadedCount, updatedCount atomic.Int64
+	for _, arg := range refs {
+		ref, err := parseProjectRef(arg)
+		if err != nil {
+			logger.Log.Errorw("Invalid project reference", zap.String("ref", arg), zap.Error(err))
+			fmt.Printf("  ✗ %s: %v\n", arg, err)
+			continue
+		}
+		project, err := client.GetProject(ref)
+		if err != nil {
+			logger.Log.Errorw("Failed to get project", zap.String("ref", ref), zap.Error(err))
+			fmt.Printf("  ✗ %s: project not found\n", arg)
+			continue
+		}
+		if !isSupportedProjectType(project.ProjectType) {
+			fmt.Printf("  ✗ %s: unsupported project type %s\n", project.Slug, project.ProjectType)
+			continue
+		}
+
+		if err := followProject(&cfg, client, project); err != nil {
+			logger.Log.Errorw("Failed to follow project", zap.String("slug", project.Slug), zap.Error(err))
+			fmt.Printf("  ✗ %s: failed to follow: %v\n", project.Slug, err)
+			continue
+		}
+		fmt.Printf("Following %s\n", project.Title)
+
+		processProject(*project, &cfg, client, false, printProgress, &downloadedCount, &updatedCount)
+	}
+
+	fmt.Printf("Installed %d new projects, updated %d.\n", downloadedCount.Load(), updatedCount.Load())
+}
+
+// printProgress prints update progress messages for commands that run without the TUI.
+func printProgress(msg UpdateProgressMsg) {
+	switch msg.Type {
+	case "download_success":
+		fmt.Printf("  ✓ %s %s\n", msg.ProjectName, msg.Version)
+	case "error":
+		fmt.Printf("  ✗ %s: %s\n", msg.ProjectName, msg.Message)
+	}
+}
+
+func removeProject(slug string) {
+	cfg, client := bootstrap(".")
+
+	var mod db.Mod
+	if err := db.DB.Where("project_slug = ?", slug).First(&mod).Error; err != nil {
+		logger.Log.Fatalw("Mod not found in database", zap.String("slug", slug), zap.Error(err))
+	}
+	log := logger.Log.With(zap.String("project_slug", slug))
+
+	for _, dependent := range installedDependents(client, mod) {
+		log.Warnw("Removing a project other installed mods depend on", zap.String("dependent", dependent))
+		fmt.Printf("  ! %s depends on %s\n", dependent, slug)
+	}
+
+	if isModrinthMod(mod) && mod.ProjectID != "" {
+		if err := unfollowProject(&cfg, client, mod.ProjectID); err != nil {
+			log.Fatalw("Failed to unfollow project", zap.Error(err))
+		}
+	}
+
+	if mod.FileName != "" {
+		archiveAndCleanupOld(mod, filepath.Dir(mod.InstallPath), &cfg, log)
+		if mod.ProjectType == "datapack" {
+			removeDatapackCopies(&cfg, mod, log)
+		}
+	}
+
+	if err := db.DB.Unscoped().Delete(&mod).Error; err != nil {
+		log.Fatalw("Failed to delete database record", zap.Error(err))
+	}
+	fmt.Printf("Removed %s\n", mod.Title)
+}
+
+// installedDependents returns the slugs of installed mods whose installed version requires mod.
+func installedDependents(client *modrinth.Client, mod db.Mod) []string {
+	var mods []db.Mod
+	if err := db.DB.Where("project_slug <> ?", mod.ProjectSlug).Find(&mods).Error; err != nil || mod.ProjectID == "" {
+		return nil
+	}
+	versions, err := fetchVersionsByID(client, mods)
+	if err != nil {
+		logger.Log.Warnw("Failed to check dependencies", zap.Error(err))
+		return nil
+	}
+
+	var dependents []string
+	for _, m := range mods {
+		for _, dep := range versions[m.VersionID].Dependencies {
+			if dep.DependencyType == "required" && dep.ProjectID == mod.ProjectID {
+				dependents = append(dependents, m.ProjectSlug)
+				break
+			}
+		}
+	}
+	return dependents
+}
+
+// removeDatapackCopies removes a datapack from the worlds it was copied to.
+func removeDatapackCopies(cfg *config.Config, mod db.Mod, log *zap.SugaredLogger) {
+	for _, dir := range cfg.DatapackDirs() {
+		path := filepath.Join(dir, mod.FileName)
+		if path == mod.InstallPath {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Warnw("Failed to remove datapack copy", zap.String("path", path), zap.Error(err))
+		}
+	}
+}
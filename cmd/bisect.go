@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/logger"
+	"modrinth-mod-updater/modrinth"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// bisectCmd groups the commands of a bisect session
+var bisectCmd = &cobra.Command{
+	Use:   "bisect",
+	Short: "Find the mod causing a crash by disabling half of the mods at a time",
+	Long: `Find the mod causing a problem by testing smaller and smaller sets of mods.
+
+bisect start disables all tracked mods except half of them, keeping the required dependencies of the
+enabled mods active. Start the game, then run bisect good if the problem is gone or bisect bad if
+it still happens. Each answer halves the suspects until the culprit is found. bisect reset ends the
+session and restores which mods were enabled before it started.
+
+Example: modrinth-mod-updater bisect start
+Example: modrinth-mod-updater bisect bad
+Example: modrinth-mod-updater bisect reset`,
+}
+
+// bisectStartCmd represents the bisect start command
+var bisectStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start a bisect session with the enabled mods as suspects",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		startBisect()
+	},
+}
+
+// bisectGoodCmd represents the bisect good command
+var bisectGoodCmd = &cobra.Command{
+	Use:   "good",
+	Short: "Mark the current step as working: the culprit is disabled",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		markBisectStep(false)
+	},
+}
+
+// bisectBadCmd represents the bisect bad command
+var bisectBadCmd = &cobra.Command{
+	Use:   "bad",
+	Short: "Mark the current step as broken: the culprit is enabled",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		markBisectStep(true)
+	},
+}
+
+// bisectResetCmd represents the bisect reset command
+var bisectResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "End the bisect session and restore the enabled mods",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		resetBisect()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bisectCmd)
+	bisectCmd.AddCommand(bisectStartCmd)
+	bisectCmd.AddCommand(bisectGoodCmd)
+	bisectCmd.AddCommand(bisectBadCmd)
+	bisectCmd.AddCommand(bisectResetCmd)
+}
+
+func startBisect() {
+	cfg, client := bootstrapLocal(".")
+
+	var session db.BisectSession
+	if err := db.DB.First(&session).Error; err == nil {
+		logger.Log.Fatalw("A bisect session is already running, run 'bisect reset' first")
+	}
+
+	var mods []db.Mod
+	if err := db.DB.Where("project_type IN ?", []string{"mod", "plugin"}).Order("project_slug").Find(&mods).Error; err != nil {
+		logger.Log.Fatalw("Failed to load mods from database", zap.Error(err))
+	}
+	requires := bisectRequirements(client, mods)
+
+	var suspects []string
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&db.BisectSession{Step: 1}).Error; err != nil {
+			return err
+		}
+		for _, mod := range mods {
+			row := db.BisectMod{
+				ProjectSlug: mod.ProjectSlug,
+				Requires:    strings.Join(requires[mod.ProjectSlug], ","),
+				WasDisabled: mod.Disabled,
+				Suspect:     !mod.Disabled,
+			}
+			if row.Suspect {
+				suspects = append(suspects, mod.ProjectSlug)
+			}
+			if err := tx.Create(&row).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Log.Fatalw("Failed to save bisect session", zap.Error(err))
+	}
+	if len(suspects) == 0 {
+		endBisect(&cfg)
+		logger.Log.Fatalw("No enabled mods to bisect")
+	}
+
+	fmt.Printf("Bisecting %d enabled mods.\n", len(suspects))
+	advanceBisect(&cfg)
+}
+
+// bisectRequirements returns the slugs of the tracked mods each mod's installed version requires. Mods from
+// other providers have no known requirements.
+func bisectRequirements(client *modrinth.Client, mods []db.Mod) map[string][]string {
+	slugs := make(map[string]string, len(mods))
+	for _, mod := range mods {
+		if mod.ProjectID != "" {
+			slugs[mod.ProjectID] = mod.ProjectSlug
+		}
+	}
+	versions, err := fetchVersionsByID(client, mods)
+	if err != nil {
+		logger.Log.Warnw("Failed to get installed versions, dependencies will not be kept enabled", zap.Error(err))
+		return nil
+	}
+
+	requires := make(map[string][]string)
+	for _, mod := range mods {
+		for _, dep := range versions[mod.VersionID].Dependencies {
+			if slug, ok := slugs[dep.ProjectID]; ok && dep.DependencyType == "required" && slug != mod.ProjectSlug {
+				requires[mod.ProjectSlug] = append(requires[mod.ProjectSlug], slug)
+			}
+		}
+	}
+	return requires
+}
+
+func markBisectStep(bad bool) {
+	cfg, _ := bootstrapLocal(".")
+
+	session, rows := loadBisect()
+	if _, ok := nextBisectStep(bisectSuspects(rows), bisectRequires(rows)); !ok {
+		// The session is finished, show the result again.
+		advanceBisect(&cfg)
+		return
+	}
+	active := make(map[string]bool)
+	for _, row := range rows {
+		if row.Active {
+			active[row.ProjectSlug] = true
+		}
+	}
+	remaining := narrowSuspects(bisectSuspects(rows), active, bad)
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			if err := tx.Model(&row).Update("suspect", slices.Contains(remaining, row.ProjectSlug)).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&session).Update("step", session.Step+1).Error
+	})
+	if err != nil {
+		logger.Log.Fatalw("Failed to save bisect step", zap.Error(err))
+	}
+	advanceBisect(&cfg)
+}
+
+// advanceBisect enables the mods of the next step, or reports the result when the suspects cannot be
+// narrowed down any further.
+func advanceBisect(cfg *config.Config) {
+	session, rows := loadBisect()
+	suspects := bisectSuspects(rows)
+	active, ok := nextBisectStep(suspects, bisectRequires(rows))
+	switch {
+	case len(suspects) == 0:
+		fmt.Println("No suspects left: the problem does not come from a single mod, or an answer was wrong.")
+		fmt.Println("Run 'bisect reset' to restore your mods.")
+		return
+	case len(suspects) == 1:
+		fmt.Printf("Found the culprit: %s\n", bisectTitle(suspects[0]))
+		fmt.Println("Run 'bisect reset' to restore your mods.")
+		return
+	case !ok:
+		fmt.Println("The suspects depend on each other and cannot be split further. The problem is in this set:")
+		for _, slug := range suspects {
+			fmt.Printf("  - %s\n", bisectTitle(slug))
+		}
+		fmt.Println("Run 'bisect reset' to restore your mods.")
+		return
+	}
+
+	if err := applyBisectStep(cfg, rows, active); err != nil {
+		logger.Log.Fatalw("Failed to enable the mods of the next step", zap.Error(err))
+	}
+	fmt.Printf("Step %d: %d suspects left, %d mods enabled.\n", session.Step, len(suspects), len(active))
+	fmt.Println("Start the game, then run 'bisect good' if the problem is gone or 'bisect bad' if it still happens.")
+}
+
+// applyBisectStep enables the active mods of a step and disables the other mods of the session.
+func applyBisectStep(cfg *config.Config, rows []db.BisectMod, active map[string]bool) error {
+	for _, row := range rows {
+		var mod db.Mod
+		if err := db.DB.Where("project_slug = ?", row.ProjectSlug).First(&mod).Error; err != nil {
+			logger.Log.Warnw("Mod is no longer tracked, skipping it", zap.String("slug", row.ProjectSlug))
+			continue
+		}
+		if err := setModDisabled(cfg, &mod, !active[row.ProjectSlug], logger.Log.With(zap.String("project_slug", row.ProjectSlug))); err != nil {
+			return fmt.Errorf("%s: %w", row.ProjectSlug, err)
+		}
+		if err := db.DB.Model(&row).Update("active", active[row.ProjectSlug]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resetBisect() {
+	cfg, _ := bootstrapLocal(".")
+
+	endBisect(&cfg)
+	fmt.Println("Bisect session ended, mods restored.")
+}
+
+// endBisect restores the disabled state of the session's mods from before the bisect and deletes the session.
+func endBisect(cfg *config.Config) {
+	_, rows := loadBisect()
+	for _, row := range rows {
+		var mod db.Mod
+		if err := db.DB.Where("project_slug = ?", row.ProjectSlug).First(&mod).Error; err != nil {
+			continue
+		}
+		if err := setModDisabled(cfg, &mod, row.WasDisabled, logger.Log.With(zap.String("project_slug", row.ProjectSlug))); err != nil {
+			logger.Log.Errorw("Failed to restore mod", zap.String("slug", row.ProjectSlug), zap.Error(err))
+			fmt.Printf("  ✗ %s: %v\n", mod.Title, err)
+		}
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("1 = 1").Delete(&db.BisectMod{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("1 = 1").Delete(&db.BisectSession{}).Error
+	})
+	if err != nil {
+		logger.Log.Fatalw("Failed to delete bisect session", zap.Error(err))
+	}
+}
+
+// loadBisect returns the running session and its mods.
+func loadBisect() (db.BisectSession, []db.BisectMod) {
+	var session db.BisectSession
+	if err := db.DB.First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Log.Fatalw("No bisect session is running, run 'bisect start' first")
+		}
+		logger.Log.Fatalw("Failed to load bisect session", zap.Error(err))
+	}
+	var rows []db.BisectMod
+	if err := db.DB.Order("project_slug").Find(&rows).Error; err != nil {
+		logger.Log.Fatalw("Failed to load bisect session", zap.Error(err))
+	}
+	return session, rows
+}
+
+func bisectSuspects(rows []db.BisectMod) []string {
+	var suspects []string
+	for _, row := range rows {
+		if row.Suspect {
+			suspects = append(suspects, row.ProjectSlug)
+		}
+	}
+	return suspects
+}
+
+// bisectRequires returns the recorded requirements of the session's mods, keyed by slug.
+func bisectRequires(rows []db.BisectMod) map[string][]string {
+	requires := make(map[string][]string, len(rows))
+	for _, row := range rows {
+		if row.Requires != "" {
+			requires[row.ProjectSlug] = strings.Split(row.Requires, ",")
+		}
+	}
+	return requires
+}
+
+func bisectTitle(slug string) string {
+	var mod db.Mod
+	if err := db.DB.Where("project_slug = ?", slug).First(&mod).Error; err != nil || mod.Title == "" {
+		return slug
+	}
+	return fmt.Sprintf("%s (%s)", mod.Title, slug)
+}
+
+// nextBisectStep picks the mods to enable next: half of the suspects and everything they require. It
+// reports false when the suspects cannot be split because either half pulls in all of them.
+func nextBisectStep(suspects []string, requires map[string][]string) (map[string]bool, bool) {
+	if len(suspects) < 2 {
+		return nil, false
+	}
+	half := (len(suspects) + 1) / 2
+	for _, testing := range [][]string{suspects[:half], suspects[half:]} {
+		active := requiredClosure(testing, requires)
+		if slices.ContainsFunc(suspects, func(slug string) bool { return !active[slug] }) {
+			return active, true
+		}
+	}
+	return nil, false
+}
+
+// requiredClosure returns the given mods together with their direct and indirect requirements.
+func requiredClosure(slugs []string, requires map[string][]string) map[string]bool {
+	active := make(map[string]bool)
+	queue := slices.Clone(slugs)
+	for len(queue) > 0 {
+		slug := queue[0]
+		queue = queue[1:]
+		if active[slug] {
+			continue
+		}
+		active[slug] = true
+		queue = append(queue, requires[slug]...)
+	}
+	return active
+}
+
+// narrowSuspects keeps the suspects that were enabled when the problem happened, or the ones that were
+// disabled when it did not.
+func narrowSuspects(suspects []string, active map[string]bool, bad bool) []string {
+	var remaining []string
+	for _, slug := range suspects {
+		if active[slug] == bad {
+			remaining = append(remaining, slug)
+		}
+	}
+	return remaining
+}
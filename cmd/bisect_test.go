@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func activeSlugs(active map[string]bool) []string {
+	var slugs []string
+	for slug := range active {
+		slugs = append(slugs, slug)
+	}
+	slices.Sort(slugs)
+	return slugs
+}
+
+func TestNextBisectStep(t *testing.T) {
+	tests := []struct {
+		name     string
+		suspects []string
+		requires map[string][]string
+		want     []string
+		wantOK   bool
+	}{
+		{"halves", []string{"a", "b", "c", "d"}, nil, []string{"a", "b"}, true},
+		{"odd count", []string{"a", "b", "c"}, nil, []string{"a", "b"}, true},
+		{"keeps requirements", []string{"a", "b", "c", "d"}, map[string][]string{"a": {"fabric-api"}}, []string{"a", "b", "fabric-api"}, true},
+		{"indirect requirements", []string{"a", "b", "c"}, map[string][]string{"a": {"lib"}, "lib": {"core"}}, []string{"a", "b", "core", "lib"}, true},
+		{"other half when first pulls in all", []string{"a", "b", "c"}, map[string][]string{"a": {"c"}}, []string{"c"}, true},
+		{"inseparable", []string{"a", "b"}, map[string][]string{"a": {"b"}, "b": {"a"}}, nil, false},
+		{"single suspect", []string{"a"}, nil, nil, false},
+	}
+
+	for _, tt := range tests {
+		active, ok := nextBisectStep(tt.suspects, tt.requires)
+		if got := activeSlugs(active); ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: nextBisectStep() = %v, %t, want %v, %t", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestNarrowSuspects(t *testing.T) {
+	suspects := []string{"a", "b", "c", "d"}
+	active := map[string]bool{"a": true, "b": true, "fabric-api": true}
+
+	if got := narrowSuspects(suspects, active, true); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("narrowSuspects(bad) = %v, want [a b]", got)
+	}
+	if got := narrowSuspects(suspects, active, false); !reflect.DeepEqual(got, []string{"c", "d"}) {
+		t.Errorf("narrowSuspects(good) = %v, want [c d]", got)
+	}
+}
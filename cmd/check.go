@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/jarmeta"
+	"modrinth-mod-updater/logger"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// Kinds of constraint problems.
+const (
+	constraintMissing  = "missing"  // A required mod is not installed
+	constraintVersion  = "version"  // A required mod is installed in a version outside the accepted ranges
+	constraintConflict = "conflict" // A mod the jar breaks is installed
+	constraintLoader   = "loader"   // The jar has no metadata for the configured loader
+)
+
+// constraintProblem is a dependency or incompatibility declared in a jar that the enabled mods violate.
+type constraintProblem struct {
+	Mod        string   // Title of the mod declaring the constraint
+	Kind       string   // One of the constraint* kinds
+	Dependency string   // Mod ID the constraint is about, or the jar's loader for constraintLoader
+	Versions   []string // Accepted (or, for conflicts, incompatible) version ranges
+	Found      string   // Installed versions, the conflicting mod, or the disabled mod providing a missing one
+}
+
+func (p constraintProblem) String() string {
+	constraint := p.Dependency
+	if len(p.Versions) > 0 {
+		constraint += " " + strings.Join(p.Versions, " or ")
+	}
+	switch p.Kind {
+	case constraintMissing:
+		if p.Found != "" {
+			return fmt.Sprintf("%s requires %s, which is disabled (%s)", p.Mod, constraint, p.Found)
+		}
+		return fmt.Sprintf("%s requires %s, which is not installed", p.Mod, constraint)
+	case constraintVersion:
+		return fmt.Sprintf("%s requires %s, found %s", p.Mod, constraint, p.Found)
+	case constraintConflict:
+		return fmt.Sprintf("%s is incompatible with %s, found %s", p.Mod, constraint, p.Found)
+	default:
+		return fmt.Sprintf("%s is a %s mod and has no metadata for the configured loader", p.Mod, p.Dependency)
+	}
+}
+
+// providedMod is a mod ID provided by an installed mod, the game or the loader.
+type providedMod struct {
+	Slug    string // Empty for the game and the loader
+	Title   string
+	Version string // Empty when unknown, which satisfies every version range
+}
+
+func (p providedMod) String() string {
+	if p.Version == "" {
+		return p.Title
+	}
+	return p.Title + " " + p.Version
+}
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check the dependencies and incompatibilities declared by installed mods",
+	Long: `Read the depends and breaks declared in the metadata of every enabled mod jar (fabric.mod.json,
+quilt.mod.json, mods.toml or neoforge.mods.toml) and report the constraints the installed mods do not
+satisfy: required mods that are missing or disabled, versions outside the accepted ranges, installed
+mods a jar is incompatible with, and jars built for another loader.
+
+Version ranges use the syntax of the declaring loader: Fabric/Quilt version predicates or Maven ranges
+for Forge/NeoForge. Minecraft is checked against MINECRAFT_VERSION and the loader against
+LOADER_VERSION when it is set. Mods only loaded on the other side (see MINECRAFT_INSTALLATION_TYPE)
+are ignored, and mods bundled inside other jars count as installed.
+
+Exits with status 1 when a constraint is not satisfied. Set CHECK_AFTER_UPDATE or pass update --check
+to run the check after every update.
+
+Example: modrinth-mod-updater check`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		cfg, _ := bootstrap(".")
+		if !checkInstalledMods(&cfg) {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+}
+
+// checkInstalledMods checks the constraints of the tracked mods and prints the problems. It reports
+// whether every constraint is satisfied.
+func checkInstalledMods(cfg *config.Config) bool {
+	var mods []db.Mod
+	if err := db.DB.Find(&mods).Error; err != nil {
+		logger.Log.Fatalw("Failed to load mods from database", zap.Error(err))
+	}
+	refreshJarMetadata(cfg, mods)
+
+	problems := checkConstraints(mods, cfg)
+	loaderVersion := cfg.LoaderVersion
+	if loaderVersion == "" {
+		loaderVersion = "(LOADER_VERSION not set)"
+	}
+	fmt.Printf("Checked mods against Minecraft %s and %s %s\n", cfg.MinecraftVersion, cfg.MinecraftLoader, loaderVersion)
+	if len(problems) == 0 {
+		fmt.Println("  ✓ All dependencies are satisfied")
+		return true
+	}
+	for _, p := range problems {
+		fmt.Printf("  ✗ %s\n", p)
+	}
+	fmt.Printf("%d problem(s) found.\n", len(problems))
+	return false
+}
+
+// refreshJarMetadata reads the jar metadata of mods recorded before it was stored, and saves it.
+func refreshJarMetadata(cfg *config.Config, mods []db.Mod) {
+	for i := range mods {
+		if mods[i].JarLoader != "" || applyJarMetadata(&mods[i], cfg.MinecraftLoader) == nil {
+			continue
+		}
+		if err := db.DB.Save(&mods[i]).Error; err != nil {
+			logger.Log.Warnw("Failed to save jar metadata", zap.String("slug", mods[i].ProjectSlug), zap.Error(err))
+		}
+	}
+}
+
+// checkConstraints evaluates the depends and breaks declared by the enabled mods against each other, the
+// configured game version and the loader. Problems are sorted by mod title.
+func checkConstraints(mods []db.Mod, cfg *config.Config) []constraintProblem {
+	loader := strings.ToLower(cfg.MinecraftLoader)
+	provided := builtinMods(cfg)
+	disabled := make(map[string][]providedMod)
+
+	var loaded []db.Mod
+	var problems []constraintProblem
+	for _, mod := range mods {
+		if mod.JarModID == "" || !loadsOnSide(mod.JarEnvironment, cfg.MinecraftInstallationType) {
+			continue
+		}
+		if mod.Disabled {
+			addProvidedMods(disabled, mod)
+			continue
+		}
+		if !loaderCompatible(mod.JarLoader, loader) {
+			problems = append(problems, constraintProblem{Mod: mod.Title, Kind: constraintLoader, Dependency: mod.JarLoader})
+			continue
+		}
+		loaded = append(loaded, mod)
+		addProvidedMods(provided, mod)
+	}
+
+	for _, mod := range loaded {
+		problems = append(problems, checkDepends(mod, provided, disabled)...)
+		problems = append(problems, checkBreaks(mod, provided)...)
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Mod != problems[j].Mod {
+			return strings.ToLower(problems[i].Mod) < strings.ToLower(problems[j].Mod)
+		}
+		return problems[i].Dependency < problems[j].Dependency
+	})
+	return problems
+}
+
+// builtinMods returns the mod IDs the game and the configured loader provide. Java and the mod IDs a
+// loader only provides for compatibility, like fabricloader on Quilt, have unknown versions.
+func builtinMods(cfg *config.Config) map[string][]providedMod {
+	loader := strings.ToLower(cfg.MinecraftLoader)
+	provided := map[string][]providedMod{
+		"minecraft": {{Title: "Minecraft", Version: cfg.MinecraftVersion}},
+		"java":      {{Title: "Java"}},
+	}
+	add := func(id, version string) {
+		provided[id] = append(provided[id], providedMod{Title: loader, Version: version})
+	}
+	switch loader {
+	case jarmeta.Fabric:
+		add("fabricloader", cfg.LoaderVersion)
+	case jarmeta.Quilt:
+		add("quilt_loader", cfg.LoaderVersion)
+		add("fabricloader", "")
+	case jarmeta.Forge:
+		add("forge", cfg.LoaderVersion)
+	case jarmeta.NeoForge:
+		add("neoforge", cfg.LoaderVersion)
+		add("forge", "")
+	}
+	return provided
+}
+
+// addProvidedMods records the mod ID of a mod's jar and the IDs it provides.
+func addProvidedMods(provided map[string][]providedMod, mod db.Mod) {
+	provided[mod.JarModID] = append(provided[mod.JarModID], providedMod{Slug: mod.ProjectSlug, Title: mod.Title, Version: mod.JarVersion})
+	for _, p := range mod.JarProvides {
+		if p.ID == mod.JarModID {
+			continue
+		}
+		provided[p.ID] = append(provided[p.ID], providedMod{Slug: mod.ProjectSlug, Title: mod.Title, Version: p.Version})
+	}
+}
+
+// loadsOnSide reports whether the loader loads a jar with the given environment on the installation type.
+func loadsOnSide(environment, installationType string) bool {
+	switch strings.ToLower(installationType) {
+	case "client":
+		return environment != jarmeta.EnvironmentServer
+	case "server":
+		return environment != jarmeta.EnvironmentClient
+	default:
+		return true
+	}
+}
+
+// loaderCompatible reports whether the configured loader loads jars whose metadata was read for jarLoader.
+func loaderCompatible(jarLoader, loader string) bool {
+	return jarLoader == loader ||
+		(loader == jarmeta.Quilt && jarLoader == jarmeta.Fabric) ||
+		(loader == jarmeta.NeoForge && jarLoader == jarmeta.Forge)
+}
+
+func checkDepends(mod db.Mod, provided, disabled map[string][]providedMod) []constraintProblem {
+	var problems []constraintProblem
+	for _, dep := range mod.JarDepends {
+		candidates := provided[dep.ID]
+		if len(candidates) == 0 {
+			problem := constraintProblem{Mod: mod.Title, Kind: constraintMissing, Dependency: dep.ID, Versions: dep.Versions}
+			if d := disabled[dep.ID]; len(d) > 0 {
+				problem.Found = d[0].Title
+			}
+			problems = append(problems, problem)
+			continue
+		}
+		satisfied := false
+		found := make([]string, len(candidates))
+		for i, c := range candidates {
+			satisfied = satisfied || satisfiesDependency(dep, mod.JarLoader, c.Version)
+			found[i] = c.String()
+		}
+		if !satisfied {
+			problems = append(problems, constraintProblem{
+				Mod: mod.Title, Kind: constraintVersion, Dependency: dep.ID, Versions: dep.Versions, Found: strings.Join(found, ", "),
+			})
+		}
+	}
+	return problems
+}
+
+func checkBreaks(mod db.Mod, provided map[string][]providedMod) []constraintProblem {
+	var problems []constraintProblem
+	for _, dep := range mod.JarBreaks {
+		for _, c := range provided[dep.ID] {
+			if c.Slug == mod.ProjectSlug || !breaksVersion(dep, mod.JarLoader, c.Version) {
+				continue
+			}
+			problems = append(problems, constraintProblem{
+				Mod: mod.Title, Kind: constraintConflict, Dependency: dep.ID, Versions: dep.Versions, Found: c.String(),
+			})
+		}
+	}
+	return problems
+}
+
+// satisfiesDependency reports whether a provided version satisfies a dependency. Unknown versions and
+// versions that cannot be compared with the ranges count as satisfying, so only certain violations are reported.
+func satisfiesDependency(dep jarmeta.Dependency, loader, version string) bool {
+	if version == "" {
+		return true
+	}
+	ok, err := dep.Matches(loader, version)
+	return ok || err != nil
+}
+
+// breaksVersion reports whether a provided version is one a breaks entry is incompatible with. As for
+// dependencies, unknown and incomparable versions are given the benefit of the doubt.
+func breaksVersion(dep jarmeta.Dependency, loader, version string) bool {
+	if len(dep.Versions) == 0 {
+		return true
+	}
+	if version == "" {
+		return false
+	}
+	ok, _ := dep.Matches(loader, version)
+	return ok
+}
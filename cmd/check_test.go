@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/jarmeta"
+)
+
+func jarMod(slug, modID, version string, depends, breaks []jarmeta.Dependency) db.Mod {
+	return db.Mod{
+		ProjectSlug:    slug,
+		Title:          slug,
+		JarModID:       modID,
+		JarLoader:      jarmeta.Fabric,
+		JarVersion:     version,
+		JarEnvironment: jarmeta.EnvironmentBoth,
+		JarDepends:     depends,
+		JarBreaks:      breaks,
+	}
+}
+
+func TestCheckConstraints(t *testing.T) {
+	cfg := &config.Config{MinecraftVersion: "1.21.4", MinecraftLoader: "fabric", LoaderVersion: "0.16.9", MinecraftInstallationType: "server"}
+
+	fabricAPI := jarMod("fabric-api", "fabric-api", "0.110.0", nil, nil)
+	fabricAPI.JarProvides = []jarmeta.Provided{{ID: "fabric-api-base", Version: "0.4.50"}}
+	clientOnly := jarMod("sodium", "sodium", "0.6.0", []jarmeta.Dependency{{ID: "missing-lib"}}, nil)
+	clientOnly.JarEnvironment = jarmeta.EnvironmentClient
+	disabled := jarMod("cloth-config", "cloth-config", "17.0.0", nil, nil)
+	disabled.Disabled = true
+	forgeMod := jarMod("jei", "jei", "19.0.0", nil, nil)
+	forgeMod.JarLoader = jarmeta.Forge
+
+	mods := []db.Mod{
+		fabricAPI,
+		clientOnly,
+		disabled,
+		forgeMod,
+		jarMod("lithium", "lithium", "0.14.0", []jarmeta.Dependency{
+			{ID: "minecraft", Versions: []string{"1.21.x"}},
+			{ID: "fabricloader", Versions: []string{">=0.16.0"}},
+			{ID: "fabric-api-base"},
+			{ID: "java", Versions: []string{">=21"}},
+		}, []jarmeta.Dependency{{ID: "lithium", Versions: []string{"<0.1"}}}),
+		jarMod("ferritecore", "ferritecore", "7.0.0", []jarmeta.Dependency{
+			{ID: "minecraft", Versions: []string{"1.20.1"}},
+			{ID: "fabricloader", Versions: []string{">=0.17"}},
+			{ID: "cloth-config"},
+			{ID: "modmenu"},
+		}, []jarmeta.Dependency{{ID: "fabric-api", Versions: []string{"<0.100"}}, {ID: "lithium"}}),
+	}
+
+	want := []constraintProblem{
+		{Mod: "ferritecore", Kind: constraintMissing, Dependency: "cloth-config", Found: "cloth-config"},
+		{Mod: "ferritecore", Kind: constraintVersion, Dependency: "fabricloader", Versions: []string{">=0.17"}, Found: "fabric 0.16.9"},
+		{Mod: "ferritecore", Kind: constraintConflict, Dependency: "lithium", Found: "lithium 0.14.0"},
+		{Mod: "ferritecore", Kind: constraintVersion, Dependency: "minecraft", Versions: []string{"1.20.1"}, Found: "Minecraft 1.21.4"},
+		{Mod: "ferritecore", Kind: constraintMissing, Dependency: "modmenu"},
+		{Mod: "jei", Kind: constraintLoader, Dependency: jarmeta.Forge},
+	}
+	if got := checkConstraints(mods, cfg); !reflect.DeepEqual(got, want) {
+		t.Errorf("checkConstraints() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCheckConstraintsForge(t *testing.T) {
+	cfg := &config.Config{MinecraftVersion: "1.21.1", MinecraftLoader: "neoforge", MinecraftInstallationType: "client"}
+
+	create := jarMod("create", "create", "6.0.0", []jarmeta.Dependency{
+		{ID: "neoforge", Versions: []string{"[21.1,)"}},
+		{ID: "minecraft", Versions: []string{"[1.21,1.21.2)"}},
+		{ID: "forge", Versions: []string{"[47,)"}},
+	}, nil)
+	create.JarLoader = jarmeta.NeoForge
+	oldMod := jarMod("old", "old", "1.0", []jarmeta.Dependency{{ID: "minecraft", Versions: []string{"[1.20.1,1.20.2)"}}}, nil)
+	oldMod.JarLoader = jarmeta.Forge
+
+	want := []constraintProblem{
+		{Mod: "old", Kind: constraintVersion, Dependency: "minecraft", Versions: []string{"[1.20.1,1.20.2)"}, Found: "Minecraft 1.21.1"},
+	}
+	if got := checkConstraints([]db.Mod{create, oldMod}, cfg); !reflect.DeepEqual(got, want) {
+		t.Errorf("checkConstraints() = %+v, want %+v", got, want)
+	}
+}
+
+func TestConstraintProblemString(t *testing.T) {
+	tests := []struct {
+		problem constraintProblem
+		want    string
+	}{
+		{
+			constraintProblem{Mod: "Iris", Kind: constraintMissing, Dependency: "sodium", Versions: []string{"0.5.x", "0.6.x"}},
+			"Iris requires sodium 0.5.x or 0.6.x, which is not installed",
+		},
+		{
+			constraintProblem{Mod: "Iris", Kind: constraintMissing, Dependency: "sodium", Found: "Sodium"},
+			"Iris requires sodium, which is disabled (Sodium)",
+		},
+		{
+			constraintProblem{Mod: "Iris", Kind: constraintVersion, Dependency: "minecraft", Versions: []string{"1.21"}, Found: "Minecraft 1.20.1"},
+			"Iris requires minecraft 1.21, found Minecraft 1.20.1",
+		},
+		{
+			constraintProblem{Mod: "Sodium", Kind: constraintConflict, Dependency: "optifabric", Found: "OptiFabric 1.14"},
+			"Sodium is incompatible with optifabric, found OptiFabric 1.14",
+		},
+		{
+			constraintProblem{Mod: "JEI", Kind: constraintLoader, Dependency: "forge"},
+			"JEI is a forge mod and has no metadata for the configured loader",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.problem.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -1,31 +1,55 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"modrinth-mod-updater/config"
 	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/jarmeta"
 	"modrinth-mod-updater/logger"
 	"modrinth-mod-updater/modrinth"
 
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // bootstrap handles shared initialization logic for commands.
 func bootstrap(path string) (config.Config, *modrinth.Client) {
-	cfg, err := config.LoadConfig(path)
-	if err != nil {
-		logger.Log.Fatalw("Failed to load configuration", zap.Error(err))
-	}
+	cfg, client := bootstrapClient(path)
 
 	db.InitDatabase(cfg.DatabasePath)
 	logger.Log.Infow("Database initialized", zap.String("path", cfg.DatabasePath))
 
 	if cfg.ModrinthAPIKey == "" {
-		logger.Log.Fatal("Error: MODRINTH_API_KEY must be set.")
+		logger.Log.Warn("MODRINTH_API_KEY is not set, using the local follow list only.")
+	}
+
+	if cfg.MinecraftVersionFallback != "" {
+		if err := resolveVersionFallbacks(&cfg, client); err != nil {
+			logger.Log.Fatalw("Failed to resolve MINECRAFT_VERSION_FALLBACK", zap.Error(err))
+		}
+	}
+
+	if err := importInstalledMods(newProviders(&cfg, client), &cfg); err != nil {
+		logger.Log.Warnw("Failed to import installed mods", zap.Error(err))
+	}
+
+	return cfg, client
+}
+
+// bootstrapClient loads the configuration and creates a Modrinth client without opening the database
+// or requiring an API key, for commands that only read public data.
+func bootstrapClient(path string) (config.Config, *modrinth.Client) {
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		logger.Log.Fatalw("Failed to load configuration", zap.Error(err))
 	}
 	if cfg.MinecraftVersion == "" || cfg.MinecraftLoader == "" {
 		logger.Log.Fatal("Error: MINECRAFT_VERSION and MINECRAFT_LOADER must be set.")
@@ -35,12 +59,75 @@ func bootstrap(path string) (config.Config, *modrinth.Client) {
 	if err != nil {
 		logger.Log.Fatalw("Failed to create Modrinth client", zap.Error(err))
 	}
+	return cfg, client
+}
 
-	if err := importInstalledMods(client, cfg.MinecraftDir); err != nil {
-		logger.Log.Warnw("Failed to import installed mods", zap.Error(err))
+// bootstrapLocal loads the configuration, opens the database and creates a Modrinth client without
+// scanning the instance for new files, for commands that only report on the tracked mods.
+func bootstrapLocal(path string) (config.Config, *modrinth.Client) {
+	cfg, client := bootstrapClient(path)
+	db.InitDatabase(cfg.DatabasePath)
+	return cfg, client
+}
+
+// resolveVersionFallbacks expands the configured fallback expression against Modrinth's game version list.
+func resolveVersionFallbacks(cfg *config.Config, client *modrinth.Client) error {
+	known, err := client.GetGameVersions()
+	if err != nil {
+		return err
+	}
+	resolved, err := modrinth.ResolveGameVersions(cfg.MinecraftVersionFallback, known)
+	if err != nil {
+		return err
 	}
 
-	return cfg, client
+	cfg.GameVersionFallbacks = nil
+	for _, v := range resolved {
+		if v != cfg.MinecraftVersion {
+			cfg.GameVersionFallbacks = append(cfg.GameVersionFallbacks, v)
+		}
+	}
+	logger.Log.Infow("Resolved fallback game versions",
+		zap.String("expression", cfg.MinecraftVersionFallback),
+		zap.Strings("versions", cfg.GameVersionFallbacks),
+	)
+	return nil
+}
+
+// fetchCompatibleVersions returns the compatible versions of a project, preferring builds for the exact
+// configured Minecraft version. Fallback game versions are only queried when the exact version has no
+// builds and the project has opted in through VERSION_FALLBACK_PROJECTS.
+func fetchCompatibleVersions(client *modrinth.Client, cfg *config.Config, slug, projectType string) ([]modrinth.Version, error) {
+	loaders := projectLoaders(cfg, projectType)
+	versions, err := client.GetProjectVersions(slug, []string{cfg.MinecraftVersion}, loaders)
+	if err != nil || len(versions) > 0 {
+		return versions, err
+	}
+	if len(cfg.GameVersionFallbacks) == 0 || !cfg.AllowsVersionFallback(slug) {
+		return versions, nil
+	}
+	return client.GetProjectVersions(slug, cfg.GameVersionFallbacks, loaders)
+}
+
+// projectLoaders returns the loaders a project's versions must support for the given project type.
+// Resource packs, and shaders without a configured SHADER_LOADER, are not filtered by loader.
+func projectLoaders(cfg *config.Config, projectType string) []string {
+	switch projectType {
+	case "mod":
+		return []string{cfg.MinecraftLoader}
+	case "shader":
+		if cfg.ShaderLoader != "" {
+			return []string{cfg.ShaderLoader}
+		}
+	case "datapack":
+		return []string{"datapack"}
+	case "plugin":
+		if compat, ok := pluginLoaderCompat[cfg.MinecraftLoader]; ok {
+			return compat
+		}
+		return []string{cfg.MinecraftLoader}
+	}
+	return nil
 }
 
 // getTargetSubDir returns the appropriate subdirectory for a project type.
@@ -52,11 +139,121 @@ func getTargetSubDir(projectType string) string {
 		return "shaderpacks"
 	case "resourcepack":
 		return "resourcepacks"
+	case "datapack":
+		return filepath.Join("world", "datapacks")
+	case "plugin":
+		return "plugins"
 	default:
 		return "mods"
 	}
 }
 
+// projectTypeForPath derives the installed project type from the directory a file lives in,
+// falling back to the type reported by Modrinth.
+func projectTypeForPath(path, fallback string) string {
+	switch filepath.Base(filepath.Dir(path)) {
+	case "mods":
+		return "mod"
+	case "shaderpacks":
+		return "shader"
+	case "resourcepacks":
+		return "resourcepack"
+	case "datapacks":
+		return "datapack"
+	case "plugins":
+		return "plugin"
+	default:
+		return fallback
+	}
+}
+
+// supportedProjectTypes lists the project types the updater knows how to install.
+var supportedProjectTypes = []string{"mod", "shader", "resourcepack", "datapack", "plugin"}
+
+// pluginLoaderCompat lists the plugin loaders each plugin platform can run, e.g. Purpur runs Paper plugins.
+var pluginLoaderCompat = map[string][]string{
+	"purpur":     {"purpur", "paper", "spigot", "bukkit"},
+	"paper":      {"paper", "spigot", "bukkit"},
+	"spigot":     {"spigot", "bukkit"},
+	"velocity":   {"velocity"},
+	"bungeecord": {"bungeecord", "waterfall"},
+}
+
+// isSupportedProjectType reports whether projects of the given type can be installed.
+func isSupportedProjectType(projectType string) bool {
+	return slices.Contains(supportedProjectTypes, projectType)
+}
+
+// effectiveProjectType returns the type a project is installed as. On plugin platforms, mods and
+// plugins are installed as plugins. Mods that publish a datapack variant are installed as datapacks
+// when listed in DATAPACK_PROJECTS or when they have no build for the configured loader.
+func effectiveProjectType(p modrinth.Project, cfg *config.Config) string {
+	if cfg.IsPluginPlatform() && (p.ProjectType == "mod" || p.ProjectType == "plugin") {
+		return "plugin"
+	}
+	if p.ProjectType != "mod" || !slices.Contains(p.Loaders, "datapack") {
+		return p.ProjectType
+	}
+	if cfg.PrefersDatapack(p.Slug) || !slices.Contains(p.Loaders, cfg.MinecraftLoader) {
+		return "datapack"
+	}
+	return p.ProjectType
+}
+
+// projectBaseDir returns the directory a project of the given type is installed into.
+// Datapacks are installed into the first configured world; see syncDatapackCopies for the others.
+func projectBaseDir(cfg *config.Config, projectType string) string {
+	if projectType == "datapack" {
+		if dirs := cfg.DatapackDirs(); len(dirs) > 0 {
+			return dirs[0]
+		}
+	}
+	return filepath.Join(cfg.MinecraftDir, getTargetSubDir(projectType))
+}
+
+// managedDirs returns every directory the updater installs files into.
+func managedDirs(cfg *config.Config) []string {
+	if cfg.IsPluginPlatform() {
+		return append([]string{filepath.Join(cfg.MinecraftDir, "plugins")}, cfg.DatapackDirs()...)
+	}
+	dirs := []string{
+		filepath.Join(cfg.MinecraftDir, "mods"),
+		filepath.Join(cfg.MinecraftDir, "shaderpacks"),
+		filepath.Join(cfg.MinecraftDir, "resourcepacks"),
+	}
+	return append(dirs, cfg.DatapackDirs()...)
+}
+
+// syncDatapackCopies mirrors a freshly installed datapack into every additional configured world,
+// removing the previous file of the project from those worlds.
+func syncDatapackCopies(cfg *config.Config, installPath, oldFileName string, goroutineLogger *zap.SugaredLogger) {
+	dirs := cfg.DatapackDirs()
+	if len(dirs) < 2 {
+		return
+	}
+
+	content, err := os.ReadFile(installPath)
+	if err != nil {
+		goroutineLogger.Warnw("Failed to read datapack for copying to other worlds", zap.String("file", installPath), zap.Error(err))
+		return
+	}
+
+	for _, dir := range dirs[1:] {
+		if oldFileName != "" && oldFileName != filepath.Base(installPath) {
+			if err := os.Remove(filepath.Join(dir, oldFileName)); err != nil && !os.IsNotExist(err) {
+				goroutineLogger.Warnw("Failed to remove old datapack", zap.String("dir", dir), zap.Error(err))
+			}
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			goroutineLogger.Warnw("Failed to create datapacks directory", zap.String("dir", dir), zap.Error(err))
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, filepath.Base(installPath)), content, 0644); err != nil {
+			goroutineLogger.Warnw("Failed to copy datapack to world", zap.String("dir", dir), zap.Error(err))
+		}
+	}
+}
+
 // findPrimaryFile locates the primary file in a Modrinth version, or the first file if no primary is marked.
 func findPrimaryFile(v modrinth.Version) *modrinth.File {
 	for i := range v.Files {
@@ -71,7 +268,11 @@ func findPrimaryFile(v modrinth.Version) *modrinth.File {
 }
 
 // projectSupportsInstallationType checks if a project supports the configured installation type.
+// Plugins only run on servers and proxies, so their client/server side metadata is not consulted.
 func projectSupportsInstallationType(p modrinth.Project, installationType string) bool {
+	if p.ProjectType == "plugin" {
+		return strings.ToLower(installationType) != "client"
+	}
 	switch strings.ToLower(installationType) {
 	case "client":
 		return p.ClientSide == "required" || p.ClientSide == "optional"
@@ -84,9 +285,36 @@ func projectSupportsInstallationType(p modrinth.Project, installationType string
 	}
 }
 
+// disabledSuffix is appended to the file name of a disabled mod, so that the game skips it.
+const disabledSuffix = ".disabled"
+
+// installedFileName returns the name of a mod's file on disk, which carries disabledSuffix while the mod is disabled.
+func installedFileName(mod db.Mod) string {
+	if mod.Disabled {
+		return mod.FileName + disabledSuffix
+	}
+	return mod.FileName
+}
+
+// installedFilePath returns the path of a mod's file on disk.
+func installedFilePath(mod db.Mod) string {
+	if mod.Disabled {
+		return mod.InstallPath + disabledSuffix
+	}
+	return mod.InstallPath
+}
+
+// keepDisabled renames a freshly installed file of a disabled mod, so that updating the mod does not enable it.
+func keepDisabled(mod db.Mod, path string) error {
+	if !mod.Disabled {
+		return nil
+	}
+	return os.Rename(path, path+disabledSuffix)
+}
+
 // archiveAndCleanupOld handles moving old mod versions to the archive or deleting them.
 func archiveAndCleanupOld(existingMod db.Mod, projectBaseDir string, cfg *config.Config, goroutineLogger *zap.SugaredLogger) {
-	oldFilePath := filepath.Join(projectBaseDir, existingMod.FileName)
+	oldFilePath := filepath.Join(projectBaseDir, installedFileName(existingMod))
 	archivePath := ""
 
 	if cfg.KeepOldVersions {
@@ -112,8 +340,124 @@ func archiveAndCleanupOld(existingMod db.Mod, projectBaseDir string, cfg *config
 		VersionID:     existingMod.VersionID,
 		VersionNumber: existingMod.VersionNumber,
 		FileName:      existingMod.FileName,
+		FileHash:      existingMod.FileHash,
 		ArchivePath:   archivePath,
 	}).Error; err != nil {
 		goroutineLogger.Warnw("Failed to save mod version history to database", zap.Error(err))
 	}
 }
+
+// recordInstalledVersion stores a file installed outside the update pipeline (e.g. from a modpack) in the
+// database. A previously installed file of the same project is archived or removed first.
+func recordInstalledVersion(cfg *config.Config, project *modrinth.Project, version *modrinth.Version, installPath string, goroutineLogger *zap.SugaredLogger) error {
+	fileName := filepath.Base(installPath)
+
+	var mod db.Mod
+	err := findModrinthMod(project.Slug, &mod)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err == nil && mod.FileName != fileName {
+		archiveAndCleanupOld(mod, filepath.Dir(mod.InstallPath), cfg, goroutineLogger)
+	}
+	if err := keepDisabled(mod, installPath); err != nil {
+		return err
+	}
+
+	applyInstalledVersion(cfg, &mod, project, version, installPath)
+	return db.DB.Save(&mod).Error
+}
+
+// recordPackVersion records a Modrinth file installed from a pack and follows its project, since update
+// only manages followed projects. A failed follow is logged, the file stays installed and recorded.
+func recordPackVersion(cfg *config.Config, client *modrinth.Client, project *modrinth.Project, version *modrinth.Version, installPath string, log *zap.SugaredLogger) error {
+	if err := recordInstalledVersion(cfg, project, version, installPath, log); err != nil {
+		return err
+	}
+	if err := followProject(cfg, client, project); err != nil {
+		log.Warnw("Failed to follow pack project, update will not manage it until it is followed",
+			zap.String("slug", project.Slug), zap.Error(err))
+	}
+	return nil
+}
+
+// applyInstalledVersion copies the project and version details of an installed file, and the metadata in
+// the file itself, onto a mod record.
+func applyInstalledVersion(cfg *config.Config, mod *db.Mod, project *modrinth.Project, version *modrinth.Version, installPath string) {
+	updatedTime, _ := time.Parse(time.RFC3339Nano, project.Updated)
+	mod.ProjectSlug = project.Slug
+	mod.ProjectID = project.ID
+	mod.Title = project.Title
+	mod.ProjectType = projectTypeForPath(installPath, project.ProjectType)
+	mod.ClientSide = project.ClientSide
+	mod.ServerSide = project.ServerSide
+	mod.IconURL = project.IconURL
+	mod.Color = project.Color
+	mod.Updated = updatedTime
+	mod.VersionID = version.ID
+	mod.VersionNumber = version.VersionNumber
+	mod.FileName = filepath.Base(installPath)
+	mod.InstallPath = installPath
+	applyFileMetadata(mod, cfg.MinecraftLoader)
+}
+
+// applyFileMetadata records the hash of a mod's file and the loader metadata in it, see applyJarMetadata.
+func applyFileMetadata(mod *db.Mod, loader string) *jarmeta.Metadata {
+	hash, err := calculateSHA1(installedFilePath(*mod))
+	if err != nil {
+		logger.Log.Debugw("Failed to hash installed file", zap.String("file", mod.FileName), zap.Error(err))
+	}
+	mod.FileHash = hash
+	return applyJarMetadata(mod, loader)
+}
+
+// applyJarMetadata records the loader metadata in a mod's jar, preferring the configured loader's metadata
+// for multi-loader jars, and returns it. It is cleared for other files and jars without metadata.
+func applyJarMetadata(mod *db.Mod, loader string) *jarmeta.Metadata {
+	mod.JarModID, mod.JarLoader, mod.JarVersion, mod.JarEnvironment = "", "", "", ""
+	mod.JarDepends, mod.JarBreaks, mod.JarProvides = nil, nil, nil
+	if !strings.EqualFold(filepath.Ext(mod.FileName), ".jar") {
+		return nil
+	}
+
+	metas, err := jarmeta.Read(installedFilePath(*mod))
+	if err != nil {
+		if !errors.Is(err, jarmeta.ErrNoMetadata) {
+			logger.Log.Debugw("Failed to read jar metadata", zap.String("file", mod.FileName), zap.Error(err))
+		}
+		return nil
+	}
+	meta := jarmeta.Select(metas, loader)
+	mod.JarModID, mod.JarLoader, mod.JarVersion, mod.JarEnvironment = meta.ID, meta.Loader, meta.Version, meta.Environment
+	mod.JarDepends, mod.JarBreaks, mod.JarProvides = meta.Depends, meta.Breaks, meta.Provides
+	return meta
+}
+
+// copyFile copies a file through a temporary file next to the destination, so the destination is never
+// left half written. Missing parent directories of the destination are created.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
@@ -1,9 +1,16 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
 	"modrinth-mod-updater/modrinth"
+
+	"go.uber.org/zap"
 )
 
 func TestGetTargetSubDir(t *testing.T) {
@@ -14,6 +21,8 @@ func TestGetTargetSubDir(t *testing.T) {
 		{"mod", "mods"},
 		{"shader", "shaderpacks"},
 		{"resourcepack", "resourcepacks"},
+		{"datapack", filepath.Join("world", "datapacks")},
+		{"plugin", "plugins"},
 		{"something-else", "mods"}, // default case
 		{"", "mods"},               // empty case
 	}
@@ -128,6 +137,18 @@ func TestProjectSupportsInstallationType(t *testing.T) {
 			"both",
 			true,
 		},
+		{
+			"plugin on server ignores sides",
+			modrinth.Project{ProjectType: "plugin", ClientSide: "unsupported", ServerSide: "unsupported"},
+			"server",
+			true,
+		},
+		{
+			"plugin on client",
+			modrinth.Project{ProjectType: "plugin", ClientSide: "required", ServerSide: "required"},
+			"client",
+			false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -139,3 +160,127 @@ func TestProjectSupportsInstallationType(t *testing.T) {
 		})
 	}
 }
+
+func TestProjectLoaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		projectType string
+		cfg         config.Config
+		expected    []string
+	}{
+		{"mod uses minecraft loader", "mod", config.Config{MinecraftLoader: "fabric", ShaderLoader: "iris"}, []string{"fabric"}},
+		{"shader uses shader loader", "shader", config.Config{MinecraftLoader: "fabric", ShaderLoader: "iris"}, []string{"iris"}},
+		{"shader without shader loader", "shader", config.Config{MinecraftLoader: "fabric"}, nil},
+		{"resourcepack is unfiltered", "resourcepack", config.Config{MinecraftLoader: "fabric", ShaderLoader: "iris"}, nil},
+		{"datapack", "datapack", config.Config{MinecraftLoader: "fabric"}, []string{"datapack"}},
+		{"purpur runs paper plugins", "plugin", config.Config{MinecraftLoader: "purpur"}, []string{"purpur", "paper", "spigot", "bukkit"}},
+		{"velocity plugins", "plugin", config.Config{MinecraftLoader: "velocity"}, []string{"velocity"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := projectLoaders(&tt.cfg, tt.projectType)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("projectLoaders(%q) = %v, want %v", tt.projectType, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEffectiveProjectType(t *testing.T) {
+	cfg := config.Config{MinecraftLoader: "fabric", DatapackProjects: []string{"terralith"}}
+
+	tests := []struct {
+		name     string
+		project  modrinth.Project
+		expected string
+	}{
+		{"plain mod", modrinth.Project{Slug: "sodium", ProjectType: "mod", Loaders: []string{"fabric"}}, "mod"},
+		{"datapack project", modrinth.Project{Slug: "dp", ProjectType: "datapack", Loaders: []string{"datapack"}}, "datapack"},
+		{"mod with loader and datapack variant", modrinth.Project{Slug: "other", ProjectType: "mod", Loaders: []string{"fabric", "datapack"}}, "mod"},
+		{"mod preferring datapack variant", modrinth.Project{Slug: "terralith", ProjectType: "mod", Loaders: []string{"fabric", "datapack"}}, "datapack"},
+		{"mod without build for loader", modrinth.Project{Slug: "forge-only", ProjectType: "mod", Loaders: []string{"forge", "datapack"}}, "datapack"},
+		{"shader unaffected", modrinth.Project{Slug: "shader", ProjectType: "shader", Loaders: []string{"iris"}}, "shader"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := effectiveProjectType(tt.project, &cfg); result != tt.expected {
+				t.Errorf("effectiveProjectType() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+
+	t.Run("mods are plugins on plugin platforms", func(t *testing.T) {
+		paperCfg := config.Config{MinecraftLoader: "paper"}
+		p := modrinth.Project{Slug: "luckperms", ProjectType: "mod", Loaders: []string{"paper", "fabric"}}
+		if result := effectiveProjectType(p, &paperCfg); result != "plugin" {
+			t.Errorf("effectiveProjectType() = %q, want plugin", result)
+		}
+	})
+}
+
+func TestSyncDatapackCopies(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.Config{MinecraftDir: tmpDir, DatapackWorlds: []string{"world", "world_creative"}}
+	dirs := cfg.DatapackDirs()
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create datapacks directory: %v", err)
+		}
+	}
+
+	oldCopy := filepath.Join(dirs[1], "pack-1.0.zip")
+	if err := os.WriteFile(oldCopy, []byte("old"), 0644); err != nil {
+		t.Fatalf("Failed to create old datapack: %v", err)
+	}
+	installPath := filepath.Join(dirs[0], "pack-2.0.zip")
+	if err := os.WriteFile(installPath, []byte("new"), 0644); err != nil {
+		t.Fatalf("Failed to create new datapack: %v", err)
+	}
+
+	syncDatapackCopies(&cfg, installPath, "pack-1.0.zip", zap.NewNop().Sugar())
+
+	if _, err := os.Stat(oldCopy); !os.IsNotExist(err) {
+		t.Error("Old datapack copy should be removed")
+	}
+	content, err := os.ReadFile(filepath.Join(dirs[1], "pack-2.0.zip"))
+	if err != nil || string(content) != "new" {
+		t.Errorf("New datapack should be copied to the second world, got %q (%v)", content, err)
+	}
+}
+
+func TestInstalledFilePath(t *testing.T) {
+	mod := db.Mod{FileName: "sodium.jar", InstallPath: "/srv/mods/sodium.jar"}
+	if got := installedFilePath(mod); got != "/srv/mods/sodium.jar" {
+		t.Errorf("installedFilePath(enabled) = %s, want /srv/mods/sodium.jar", got)
+	}
+	mod.Disabled = true
+	if got := installedFilePath(mod); got != "/srv/mods/sodium.jar.disabled" {
+		t.Errorf("installedFilePath(disabled) = %s, want /srv/mods/sodium.jar.disabled", got)
+	}
+	if got := installedFileName(mod); got != "sodium.jar.disabled" {
+		t.Errorf("installedFileName(disabled) = %s, want sodium.jar.disabled", got)
+	}
+}
+
+func TestKeepDisabled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sodium-0.6.1.jar")
+	if err := os.WriteFile(path, []byte("new"), 0644); err != nil {
+		t.Fatalf("Failed to create mod file: %v", err)
+	}
+
+	if err := keepDisabled(db.Mod{}, path); err != nil {
+		t.Fatalf("keepDisabled(enabled) error = %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Enabled mod file should stay in place: %v", err)
+	}
+
+	if err := keepDisabled(db.Mod{Disabled: true}, path); err != nil {
+		t.Fatalf("keepDisabled(disabled) error = %v", err)
+	}
+	if _, err := os.Stat(path + disabledSuffix); err != nil {
+		t.Errorf("Disabled mod file should be renamed: %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// checklistItem is a selectable entry of a confirmation checklist.
+type checklistItem struct {
+	Label   string
+	Checked bool
+}
+
+// ChecklistModel lets the user review a list of pending actions and pick which ones to apply.
+type ChecklistModel struct {
+	title     string
+	items     []checklistItem
+	cursor    int
+	confirmed bool
+}
+
+func newChecklistModel(title string, items []checklistItem) ChecklistModel {
+	return ChecklistModel{title: title, items: items}
+}
+
+func (m ChecklistModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m ChecklistModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch key.String() {
+	case "q", "esc", "ctrl+c":
+		return m, tea.Quit
+	case "enter":
+		m.confirmed = true
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.items)-1 {
+			m.cursor++
+		}
+	case " ", "x":
+		if len(m.items) > 0 {
+			m.items[m.cursor].Checked = !m.items[m.cursor].Checked
+		}
+	case "a":
+		all := true
+		for _, item := range m.items {
+			all = all && item.Checked
+		}
+		for i := range m.items {
+			m.items[i].Checked = !all
+		}
+	}
+	return m, nil
+}
+
+func (m ChecklistModel) View() string {
+	if m.confirmed {
+		return ""
+	}
+
+	s := "\n " + lipgloss.NewStyle().Bold(true).Render(m.title) + "\n\n"
+	for i, item := range m.items {
+		cursor := " "
+		if i == m.cursor {
+			cursor = lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Render(">")
+		}
+		check := "[ ]"
+		if item.Checked {
+			check = lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Render("[x]")
+		}
+		s += fmt.Sprintf(" %s %s %s\n", cursor, check, item.Label)
+	}
+
+	footerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	return s + "\n" + footerStyle.Render(" ↑/k: up  ↓/j: down  space: toggle  a: all  enter: apply  q: cancel") + "\n"
+}
+
+// Selected returns the indices of the checked items, or nil when the user cancelled.
+func (m ChecklistModel) Selected() []int {
+	if !m.confirmed {
+		return nil
+	}
+	var selected []int
+	for i, item := range m.items {
+		if item.Checked {
+			selected = append(selected, i)
+		}
+	}
+	return selected
+}
+
+// confirmChecklist shows the checklist and returns the indices of the items to apply.
+// With assumeYes, every checked item is applied without prompting.
+func confirmChecklist(title string, items []checklistItem, assumeYes bool) ([]int, error) {
+	m := newChecklistModel(title, items)
+	if assumeYes {
+		m.confirmed = true
+		return m.Selected(), nil
+	}
+
+	final, err := tea.NewProgram(m).Run()
+	if err != nil {
+		return nil, err
+	}
+	return final.(ChecklistModel).Selected(), nil
+}
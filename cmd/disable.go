@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/logger"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// disableCmd represents the disable command
+var disableCmd = &cobra.Command{
+	Use:   "disable <slug>...",
+	Short: "Disable installed mods without removing them",
+	Long: `Disable installed mods by adding a .disabled suffix to their files, so the game skips them.
+
+Disabled mods stay tracked: update keeps installing new versions, but leaves them disabled.
+Copies of disabled datapacks in other worlds are removed. Use enable to turn them back on.
+
+Example: modrinth-mod-updater disable sodium iris`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		setModsDisabled(args, true)
+	},
+}
+
+// enableCmd represents the enable command
+var enableCmd = &cobra.Command{
+	Use:   "enable <slug>...",
+	Short: "Enable disabled mods again",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		setModsDisabled(args, false)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(disableCmd)
+	rootCmd.AddCommand(enableCmd)
+}
+
+func setModsDisabled(slugs []string, disabled bool) {
+	cfg, _ := bootstrapLocal(".")
+
+	failed := false
+	for _, slug := range slugs {
+		var mod db.Mod
+		if err := db.DB.Where("project_slug = ?", slug).First(&mod).Error; err != nil {
+			logger.Log.Errorw("Mod not found in database", zap.String("slug", slug), zap.Error(err))
+			fmt.Printf("  ✗ %s: not installed\n", slug)
+			failed = true
+			continue
+		}
+		if err := setModDisabled(&cfg, &mod, disabled, logger.Log.With(zap.String("project_slug", slug))); err != nil {
+			logger.Log.Errorw("Failed to change mod state", zap.String("slug", slug), zap.Error(err))
+			fmt.Printf("  ✗ %s: %v\n", slug, err)
+			failed = true
+			continue
+		}
+		fmt.Printf("  ✓ %s (disabled: %t)\n", mod.Title, disabled)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// setModDisabled renames a mod's file to or from its disabled name and records the new state. A missing
+// file is not an error, so records of deleted files can still be toggled.
+func setModDisabled(cfg *config.Config, mod *db.Mod, disabled bool, log *zap.SugaredLogger) error {
+	if mod.Disabled == disabled {
+		return nil
+	}
+	from := installedFilePath(*mod)
+	mod.Disabled = disabled
+	to := installedFilePath(*mod)
+
+	if _, err := os.Stat(to); err == nil {
+		mod.Disabled = !disabled
+		return fmt.Errorf("%s already exists", to)
+	}
+	if err := os.Rename(from, to); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			mod.Disabled = !disabled
+			return err
+		}
+		log.Warnw("Mod file is missing, only updating its state", zap.String("path", from))
+	}
+
+	if mod.ProjectType == "datapack" {
+		if disabled {
+			removeDatapackCopies(cfg, *mod, log)
+		} else {
+			syncDatapackCopies(cfg, mod.InstallPath, "", log)
+		}
+	}
+	return db.DB.Save(mod).Error
+}
@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || freebsd)
+
+package cmd
+
+import "errors"
+
+// freeDiskSpace is not implemented on this platform.
+func freeDiskSpace(_ string) (uint64, error) {
+	return 0, errors.New("not supported on this platform")
+}
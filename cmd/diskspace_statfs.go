@@ -0,0 +1,15 @@
+//go:build linux || darwin || freebsd
+
+package cmd
+
+import "syscall"
+
+// freeDiskSpace returns the bytes available to unprivileged users on the file system containing path.
+func freeDiskSpace(path string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	// The field types differ between platforms.
+	return uint64(st.Bavail) * uint64(st.Bsize), nil
+}
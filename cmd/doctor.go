@@ -0,0 +1,352 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/logger"
+	"modrinth-mod-updater/modrinth"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Results of a doctor check.
+const (
+	doctorPass = "pass"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+// Free disk space below which doctor warns or fails.
+const (
+	lowDiskSpace      = 1 << 30   // 1 GiB
+	criticalDiskSpace = 100 << 20 // 100 MiB
+)
+
+// doctorResult is the outcome of one doctor check.
+type doctorResult struct {
+	Status  string // doctorPass, doctorWarn or doctorFail
+	Check   string
+	Message string
+	Hint    string // What to do about a warning or failure
+}
+
+// doctorReport prints results as they come in and counts them.
+type doctorReport struct {
+	counts map[string]int
+}
+
+func (r *doctorReport) add(results ...doctorResult) {
+	marks := map[string]string{doctorPass: "✓", doctorWarn: "!", doctorFail: "✗"}
+	for _, res := range results {
+		r.counts[res.Status]++
+		fmt.Printf("  %s %-14s %s\n", marks[res.Status], res.Check, res.Message)
+		if res.Hint != "" && res.Status != doctorPass {
+			fmt.Printf("    → %s\n", res.Hint)
+		}
+	}
+}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the configuration and environment",
+	Long: `Check everything the other commands rely on and print a pass, warn or fail result with a hint for
+each problem, instead of stopping at the first one:
+
+  - the configuration: required values, MINECRAFT_VERSION and MINECRAFT_LOADER against Modrinth's tags
+  - MODRINTH_API_KEY: whether Modrinth accepts it and it can read the followed projects
+  - the instance directories: whether they exist and are writable, and the free disk space
+  - the database: whether it opens, its schema migrates and it passes SQLite's integrity check
+  - whether the database and the files on disk agree
+  - whether the log file is writable
+
+Exits with status 1 when a check fails.
+
+Example: modrinth-mod-updater doctor`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if !runDoctor() {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// runDoctor runs every check and prints the results. It reports whether no check failed.
+func runDoctor() bool {
+	report := &doctorReport{counts: make(map[string]int)}
+	defer func() {
+		fmt.Printf("%d passed, %d warnings, %d failed.\n", report.counts[doctorPass], report.counts[doctorWarn], report.counts[doctorFail])
+	}()
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		report.add(doctorResult{doctorFail, "configuration", err.Error(), "Fix the value in .env or the environment, see .env.example"})
+		report.add(checkLogFile())
+		return false
+	}
+	report.add(checkConfigValues(&cfg)...)
+
+	if client, err := modrinth.NewClient(cfg); err != nil {
+		report.add(doctorResult{doctorFail, "Modrinth", err.Error(), ""})
+	} else {
+		report.add(checkModrinthTags(&cfg, client)...)
+		report.add(checkAPIKey(client))
+	}
+
+	report.add(checkDirectories(&cfg)...)
+	report.add(checkDiskSpace(cfg.MinecraftDir))
+	report.add(checkDatabase(&cfg)...)
+	report.add(checkLogFile())
+	return report.counts[doctorFail] == 0
+}
+
+// checkConfigValues checks the configuration values LoadConfig accepts but other commands reject or
+// Modrinth frowns upon.
+func checkConfigValues(cfg *config.Config) []doctorResult {
+	source := "environment variables"
+	if used := viper.ConfigFileUsed(); used != "" {
+		source = used
+	}
+	results := []doctorResult{{Status: doctorPass, Check: "configuration", Message: "loaded from " + source}}
+
+	if cfg.MinecraftVersion == "" {
+		results = append(results, doctorResult{doctorFail, "configuration", "MINECRAFT_VERSION is not set", "Set MINECRAFT_VERSION to the game version of the instance, e.g. 1.21.5"})
+	}
+	if cfg.UserAgent == config.DefaultUserAgent {
+		results = append(results, doctorResult{doctorWarn, "configuration", "USERAGENT is not set", "Set USERAGENT to a name and contact address, e.g. user/modpack (user@example.com)"})
+	}
+	if t := strings.ToLower(cfg.MinecraftInstallationType); t != "client" && t != "server" {
+		results = append(results, doctorResult{doctorWarn, "configuration", fmt.Sprintf("MINECRAFT_INSTALLATION_TYPE %q is neither client nor server", cfg.MinecraftInstallationType),
+			"Set MINECRAFT_INSTALLATION_TYPE to client or server, otherwise every project is installed regardless of its side"})
+	}
+	return results
+}
+
+// checkModrinthTags checks the configured game version and loader against Modrinth's tag lists.
+func checkModrinthTags(cfg *config.Config, client *modrinth.Client) []doctorResult {
+	var results []doctorResult
+	if cfg.MinecraftVersion != "" {
+		versions, err := client.GetGameVersions()
+		if err != nil {
+			return append(results, doctorResult{doctorFail, "Modrinth", "cannot reach the Modrinth API: " + err.Error(), "Check the network connection and any proxy settings"})
+		}
+		results = append(results, checkGameVersion(cfg.MinecraftVersion, versions))
+	}
+	loaders, err := client.GetLoaders()
+	if err != nil {
+		return append(results, doctorResult{doctorFail, "Modrinth", "cannot reach the Modrinth API: " + err.Error(), "Check the network connection and any proxy settings"})
+	}
+	return append(results, checkLoader(cfg, loaders))
+}
+
+func checkGameVersion(version string, known []modrinth.GameVersion) doctorResult {
+	latest := ""
+	for _, v := range known {
+		if v.Version == version {
+			return doctorResult{Status: doctorPass, Check: "game version", Message: fmt.Sprintf("Minecraft %s (%s)", version, v.VersionType)}
+		}
+		if latest == "" && v.VersionType == "release" {
+			latest = v.Version
+		}
+	}
+	return doctorResult{doctorFail, "game version", fmt.Sprintf("Modrinth does not know Minecraft %s", version),
+		fmt.Sprintf("Set MINECRAFT_VERSION to a version Modrinth lists, e.g. the latest release %s", latest)}
+}
+
+func checkLoader(cfg *config.Config, tags []modrinth.LoaderTag) doctorResult {
+	loader := strings.ToLower(cfg.MinecraftLoader)
+	projectType := "mod"
+	if cfg.IsPluginPlatform() {
+		projectType = "plugin"
+	}
+	for _, tag := range tags {
+		if tag.Name != loader {
+			continue
+		}
+		if !slices.Contains(tag.SupportedProjectTypes, projectType) {
+			return doctorResult{doctorFail, "loader", fmt.Sprintf("%s does not load %ss on Modrinth", loader, projectType),
+				"Set MINECRAFT_LOADER to a mod loader like fabric, quilt, forge or neoforge, or a plugin platform like paper"}
+		}
+		return doctorResult{Status: doctorPass, Check: "loader", Message: fmt.Sprintf("%s (%ss)", loader, projectType)}
+	}
+	return doctorResult{doctorFail, "loader", fmt.Sprintf("Modrinth does not know the loader %q", cfg.MinecraftLoader),
+		"Set MINECRAFT_LOADER to a mod loader like fabric, quilt, forge or neoforge, or a plugin platform like paper"}
+}
+
+// checkAPIKey checks that Modrinth accepts the API key and that it can read the followed projects.
+func checkAPIKey(client *modrinth.Client) doctorResult {
+	const hint = "Create a personal access token with the Read user data scope at https://modrinth.com/settings/pats"
+	if client.APIKey == "" {
+		return doctorResult{doctorWarn, "API key", "MODRINTH_API_KEY is not set, projects are followed on the local follow list", hint}
+	}
+	user, err := client.GetCurrentUser()
+	var apiErr *modrinth.APIError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+		return doctorResult{doctorFail, "API key", "Modrinth rejects MODRINTH_API_KEY, it is invalid, expired or lacks scopes", hint}
+	} else if err != nil {
+		return doctorResult{doctorFail, "API key", "cannot check MODRINTH_API_KEY: " + err.Error(), "Check the network connection and any proxy settings"}
+	}
+	follows, err := client.GetUserFollows(user.ID)
+	if err != nil {
+		return doctorResult{doctorFail, "API key", fmt.Sprintf("authenticated as %s, but cannot read the followed projects", user.Username), hint}
+	}
+	return doctorResult{Status: doctorPass, Check: "API key", Message: fmt.Sprintf("authenticated as %s, %d followed projects", user.Username, len(follows))}
+}
+
+// checkDirectories checks that the instance directory and the managed directories are writable. Missing
+// world datapack directories only warn, since worlds may not have been generated yet.
+func checkDirectories(cfg *config.Config) []doctorResult {
+	var results []doctorResult
+	writable := 0
+	dirs := append([]string{cfg.MinecraftDir}, managedDirs(cfg)...)
+	for _, dir := range dirs {
+		if res := checkWritableDir(dir); res.Status != doctorPass {
+			results = append(results, res)
+			continue
+		}
+		writable++
+	}
+	if writable > 0 {
+		results = append([]doctorResult{{Status: doctorPass, Check: "directories", Message: fmt.Sprintf("%d of %d writable", writable, len(dirs))}}, results...)
+	}
+	return results
+}
+
+func checkWritableDir(dir string) doctorResult {
+	info, err := os.Stat(dir)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return doctorResult{doctorWarn, "directories", dir + " does not exist", "It is created when something is installed into it; check the path if that is unexpected"}
+	case err != nil:
+		return doctorResult{doctorFail, "directories", err.Error(), "Check the permissions of the parent directories"}
+	case !info.IsDir():
+		return doctorResult{doctorFail, "directories", dir + " is not a directory", "Move the file away or point MINECRAFT_DIR elsewhere"}
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return doctorResult{doctorFail, "directories", dir + " is not writable", "Check the owner and permissions of the directory, e.g. with chown or chmod"}
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return doctorResult{Status: doctorPass, Check: "directories", Message: dir + " is writable"}
+}
+
+func checkDiskSpace(dir string) doctorResult {
+	free, err := freeDiskSpace(dir)
+	if err != nil {
+		return doctorResult{doctorWarn, "disk space", "cannot determine the free disk space: " + err.Error(), ""}
+	}
+	return diskSpaceResult(dir, free)
+}
+
+func diskSpaceResult(dir string, free uint64) doctorResult {
+	message := fmt.Sprintf("%s free on the disk of %s", formatBytes(free), dir)
+	switch {
+	case free < criticalDiskSpace:
+		return doctorResult{doctorFail, "disk space", message, "Free up space, downloads and archives will fail"}
+	case free < lowDiskSpace:
+		return doctorResult{doctorWarn, "disk space", message, "Free up space, or disable KEEP_OLD_VERSIONS and prune the versions directories"}
+	default:
+		return doctorResult{Status: doctorPass, Check: "disk space", Message: message}
+	}
+}
+
+// formatBytes formats a size in binary units, e.g. 1.5 GiB.
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// checkDatabase opens the database, which migrates its schema, runs SQLite's integrity check and compares
+// the records with the files on disk.
+func checkDatabase(cfg *config.Config) []doctorResult {
+	const hint = "Move the database file aside; the next run imports the installed files into a new one"
+	if _, err := os.Stat(cfg.DatabasePath); errors.Is(err, os.ErrNotExist) {
+		return []doctorResult{{Status: doctorPass, Check: "database", Message: cfg.DatabasePath + " is created on the first run"}}
+	}
+	if err := db.Open(cfg.DatabasePath); err != nil {
+		return []doctorResult{{doctorFail, "database", err.Error(), hint}}
+	}
+
+	var integrity string
+	if err := db.DB.Raw("PRAGMA integrity_check").Scan(&integrity).Error; err != nil {
+		return []doctorResult{{doctorFail, "database", "integrity check failed: " + err.Error(), hint}}
+	}
+	if integrity != "ok" {
+		return []doctorResult{{doctorFail, "database", "integrity check failed: " + integrity, hint}}
+	}
+
+	var mods []db.Mod
+	if err := db.DB.Find(&mods).Error; err != nil {
+		return []doctorResult{{doctorFail, "database", "cannot read the tracked mods: " + err.Error(), hint}}
+	}
+	return []doctorResult{
+		{Status: doctorPass, Check: "database", Message: fmt.Sprintf("schema up to date, integrity ok, %d mods tracked", len(mods))},
+		checkConsistency(cfg, mods),
+	}
+}
+
+// checkConsistency compares the tracked mods with the files in the managed directories, without hashing them.
+func checkConsistency(cfg *config.Config, mods []db.Mod) doctorResult {
+	missing := 0
+	for _, mod := range mods {
+		if mod.InstallPath == "" {
+			continue
+		}
+		if _, err := os.Stat(installedFilePath(mod)); errors.Is(err, os.ErrNotExist) {
+			missing++
+		}
+	}
+	untracked := 0
+	tracked := trackedPaths(cfg, mods)
+	for _, dir := range managedDirs(cfg) {
+		for _, path := range managedFiles(dir) {
+			if !tracked[path] {
+				untracked++
+			}
+		}
+	}
+
+	if missing == 0 && untracked == 0 {
+		return doctorResult{Status: doctorPass, Check: "files", Message: "every tracked file is on disk and every file is tracked"}
+	}
+	var problems []string
+	if missing > 0 {
+		problems = append(problems, fmt.Sprintf("%d tracked files are missing", missing))
+	}
+	if untracked > 0 {
+		problems = append(problems, fmt.Sprintf("%d files are not tracked", untracked))
+	}
+	return doctorResult{doctorWarn, "files", strings.Join(problems, ", "),
+		"Run verify for details, repair to restore missing files and prune to clean up untracked ones"}
+}
+
+func checkLogFile() doctorResult {
+	path := logger.FilePath()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return doctorResult{doctorFail, "log file", "cannot write " + path, "Run the updater from a directory you can write to, the log file is created in the working directory"}
+	}
+	f.Close()
+	return doctorResult{Status: doctorPass, Check: "log file", Message: "writing to " + path}
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/modrinth"
+)
+
+func TestCheckGameVersion(t *testing.T) {
+	known := []modrinth.GameVersion{
+		{Version: "25w14a", VersionType: "snapshot"},
+		{Version: "1.21.5", VersionType: "release"},
+		{Version: "1.21.4", VersionType: "release"},
+	}
+	tests := []struct {
+		version  string
+		want     string
+		wantHint string
+	}{
+		{"1.21.4", doctorPass, ""},
+		{"25w14a", doctorPass, ""},
+		{"1.21.9", doctorFail, "Set MINECRAFT_VERSION to a version Modrinth lists, e.g. the latest release 1.21.5"},
+	}
+	for _, tt := range tests {
+		got := checkGameVersion(tt.version, known)
+		if got.Status != tt.want || got.Hint != tt.wantHint {
+			t.Errorf("checkGameVersion(%q) = %s, %q, want %s, %q", tt.version, got.Status, got.Hint, tt.want, tt.wantHint)
+		}
+	}
+}
+
+func TestCheckLoader(t *testing.T) {
+	tags := []modrinth.LoaderTag{
+		{Name: "fabric", SupportedProjectTypes: []string{"mod", "modpack"}},
+		{Name: "paper", SupportedProjectTypes: []string{"plugin"}},
+		{Name: "iris", SupportedProjectTypes: []string{"shader"}},
+	}
+	tests := []struct {
+		loader string
+		want   string
+	}{
+		{"Fabric", doctorPass},
+		{"paper", doctorPass},
+		{"iris", doctorFail},
+		{"fabirc", doctorFail},
+	}
+	for _, tt := range tests {
+		if got := checkLoader(&config.Config{MinecraftLoader: tt.loader}, tags); got.Status != tt.want {
+			t.Errorf("checkLoader(%q) = %s (%s), want %s", tt.loader, got.Status, got.Message, tt.want)
+		}
+	}
+}
+
+func TestDiskSpaceResult(t *testing.T) {
+	tests := []struct {
+		free uint64
+		want string
+	}{
+		{50 << 20, doctorFail},
+		{512 << 20, doctorWarn},
+		{20 << 30, doctorPass},
+	}
+	for _, tt := range tests {
+		if got := diskSpaceResult("/srv/minecraft", tt.free); got.Status != tt.want {
+			t.Errorf("diskSpaceResult(%d) = %s, want %s", tt.free, got.Status, tt.want)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    uint64
+		want string
+	}{
+		{512, "512 B"},
+		{1536, "1.5 KiB"},
+		{100 << 20, "100.0 MiB"},
+		{3 << 30, "3.0 GiB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestCheckWritableDir(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path string
+		want string
+	}{
+		{dir, doctorPass},
+		{filepath.Join(dir, "missing"), doctorWarn},
+		{file, doctorFail},
+	}
+	for _, tt := range tests {
+		if got := checkWritableDir(tt.path); got.Status != tt.want {
+			t.Errorf("checkWritableDir(%q) = %s (%s), want %s", tt.path, got.Status, got.Message, tt.want)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("checkWritableDir() left %d entries behind, want 1", len(entries))
+	}
+}
+
+func TestCheckConsistency(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{MinecraftDir: dir}
+	for _, f := range []string{"mods/sodium.jar", "mods/manual.jar"} {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sodium := db.Mod{ProjectSlug: "sodium", FileName: "sodium.jar", InstallPath: filepath.Join(dir, "mods", "sodium.jar")}
+	lithium := db.Mod{ProjectSlug: "lithium", FileName: "lithium.jar", InstallPath: filepath.Join(dir, "mods", "lithium.jar")}
+
+	got := checkConsistency(cfg, []db.Mod{sodium, lithium})
+	want := "1 tracked files are missing, 1 files are not tracked"
+	if got.Status != doctorWarn || got.Message != want {
+		t.Errorf("checkConsistency() = %s, %q, want %s, %q", got.Status, got.Message, doctorWarn, want)
+	}
+
+	manual := db.Mod{ProjectSlug: "manual", FileName: "manual.jar", InstallPath: filepath.Join(dir, "mods", "manual.jar")}
+	if got := checkConsistency(cfg, []db.Mod{sodium, manual}); got.Status != doctorPass {
+		t.Errorf("checkConsistency() = %s (%s), want %s", got.Status, got.Message, doctorPass)
+	}
+}
@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/logger"
+	"modrinth-mod-updater/modrinth"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// Directories inside a jar that hold embedded (jar-in-jar) mods: Fabric/Quilt and Forge/NeoForge.
+var bundledJarDirs = []string{"META-INF/jars/", "META-INF/jarjar/"}
+
+// installedFile is a file in a managed directory and the project it belongs to.
+type installedFile struct {
+	Path      string
+	SHA1      string
+	ProjectID string    // Empty when the project is unknown
+	Title     string    // Project title, or the file name when unknown
+	Published time.Time // Publish date of the file's version, zero when unknown
+	Tracked   bool      // Whether the file is the one recorded in the database
+	Bundled   []bundledJar
+}
+
+// bundledJar is a jar embedded in another jar.
+type bundledJar struct {
+	Name      string
+	SHA1      string
+	ProjectID string
+	Published time.Time
+}
+
+// redundantFile is a file that can be removed because another file already provides its project.
+type redundantFile struct {
+	File        installedFile
+	KeptBy      string // File that stays: the other copy, or the jar bundling the project
+	Bundled     bool   // Whether KeptBy bundles the project instead of being a second copy
+	Recommended bool   // False when the bundled copy is older than the standalone file
+}
+
+func (r redundantFile) label() string {
+	if r.Bundled {
+		note := ""
+		if !r.Recommended {
+			note = " (newer than the bundled copy)"
+		}
+		return fmt.Sprintf("%s, bundled in %s%s", filepath.Base(r.File.Path), filepath.Base(r.KeptBy), note)
+	}
+	return fmt.Sprintf("%s, duplicate of %s", filepath.Base(r.File.Path), filepath.Base(r.KeptBy))
+}
+
+// duplicatesCmd represents the duplicates command
+var duplicatesCmd = &cobra.Command{
+	Use:   "duplicates",
+	Short: "Find and remove duplicate and bundled copies of mods",
+	Long: `Find files in the same directory that belong to the same project, and standalone mods that
+another mod already bundles (jar-in-jar, in META-INF/jars or META-INF/jarjar). Files are matched to
+projects by their hashes, so renamed files are found too.
+
+The redundant files are shown as a checklist and the selected ones are removed (or archived when
+KEEP_OLD_VERSIONS is enabled). Of two copies, the tracked one is kept, otherwise the newest one.
+Standalone mods newer than their bundled copy are not selected by default.
+
+Example: modrinth-mod-updater duplicates
+Example: modrinth-mod-updater duplicates --yes`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		assumeYes, _ := cmd.Flags().GetBool("yes")
+		removeDuplicates(assumeYes)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(duplicatesCmd)
+
+	duplicatesCmd.Flags().BoolP("yes", "y", false, "Remove the recommended files without confirmation")
+}
+
+func removeDuplicates(assumeYes bool) {
+	cfg, client := bootstrap(".")
+
+	var mods []db.Mod
+	if err := db.DB.Find(&mods).Error; err != nil {
+		logger.Log.Fatalw("Failed to load mods from database", zap.Error(err))
+	}
+	files, err := scanInstalledFiles(&cfg, client, mods)
+	if err != nil {
+		logger.Log.Fatalw("Failed to identify installed files", zap.Error(err))
+	}
+
+	redundant := findRedundantFiles(files)
+	if len(redundant) == 0 {
+		fmt.Println("No duplicate or bundled mods found.")
+		return
+	}
+
+	items := make([]checklistItem, len(redundant))
+	for i, r := range redundant {
+		items[i] = checklistItem{Label: r.label(), Checked: r.Recommended}
+	}
+	selected, err := confirmChecklist("Select the redundant files to remove", items, assumeYes)
+	if err != nil {
+		logger.Log.Fatalw("Failed to run confirmation UI", zap.Error(err))
+	}
+
+	removed := 0
+	for _, i := range selected {
+		if err := removeRedundantFile(&cfg, client, redundant[i].File, mods); err != nil {
+			logger.Log.Errorw("Failed to remove redundant file", zap.String("path", redundant[i].File.Path), zap.Error(err))
+			fmt.Printf("  ✗ %s: %v\n", items[i].Label, err)
+			continue
+		}
+		fmt.Printf("  ✓ %s\n", items[i].Label)
+		removed++
+	}
+	fmt.Printf("Removed %d of %d redundant files.\n", removed, len(redundant))
+}
+
+// scanInstalledFiles hashes the files in the managed directories and the jars they bundle, and identifies
+// their projects from the database and with a single Modrinth hash lookup.
+func scanInstalledFiles(cfg *config.Config, client *modrinth.Client, mods []db.Mod) ([]installedFile, error) {
+	trackedByPath := make(map[string]db.Mod, len(mods))
+	for _, mod := range mods {
+		trackedByPath[installedFilePath(mod)] = mod
+	}
+
+	var files []installedFile
+	var hashes []string
+	for _, dir := range managedDirs(cfg) {
+		for _, path := range managedFiles(dir) {
+			hash, err := calculateSHA1(path)
+			if err != nil {
+				logger.Log.Warnw("Failed to hash file", zap.String("path", path), zap.Error(err))
+				continue
+			}
+			file := installedFile{Path: path, SHA1: hash, Title: filepath.Base(path)}
+			if mod, ok := trackedByPath[path]; ok {
+				file.Tracked, file.ProjectID, file.Title = true, mod.ProjectID, mod.Title
+			}
+			if file.Bundled, err = bundledJars(path); err != nil {
+				logger.Log.Debugw("Failed to read bundled jars", zap.String("path", path), zap.Error(err))
+			}
+			hashes = append(hashes, hash)
+			for _, b := range file.Bundled {
+				hashes = append(hashes, b.SHA1)
+			}
+			files = append(files, file)
+		}
+	}
+
+	versions, err := client.GetVersionsByHashes(hashes, "sha1")
+	if err != nil {
+		return nil, err
+	}
+	for i := range files {
+		f := &files[i]
+		if v, ok := versions[f.SHA1]; ok {
+			f.Published, _ = time.Parse(time.RFC3339Nano, v.DatePublished)
+			if f.ProjectID == "" {
+				f.ProjectID = v.ProjectID
+			}
+		}
+		for j := range f.Bundled {
+			if v, ok := versions[f.Bundled[j].SHA1]; ok {
+				f.Bundled[j].ProjectID = v.ProjectID
+				f.Bundled[j].Published, _ = time.Parse(time.RFC3339Nano, v.DatePublished)
+			}
+		}
+	}
+	return files, nil
+}
+
+// bundledJars hashes the jars embedded in a jar. Zips and jars without embedded jars have none.
+func bundledJars(path string) ([]bundledJar, error) {
+	if !strings.EqualFold(filepath.Ext(path), ".jar") {
+		return nil, nil
+	}
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var bundled []bundledJar
+	for _, f := range r.File {
+		if !isBundledJar(f.Name) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return bundled, err
+		}
+		h := sha1.New()
+		_, err = io.Copy(h, rc)
+		rc.Close()
+		if err != nil {
+			return bundled, err
+		}
+		bundled = append(bundled, bundledJar{Name: f.Name, SHA1: hex.EncodeToString(h.Sum(nil))})
+	}
+	return bundled, nil
+}
+
+func isBundledJar(name string) bool {
+	if !strings.HasSuffix(strings.ToLower(name), ".jar") {
+		return false
+	}
+	for _, dir := range bundledJarDirs {
+		if rest, ok := strings.CutPrefix(name, dir); ok && !strings.Contains(rest, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+// findRedundantFiles returns the files of a project that has another file in the same directory, and
+// standalone files whose project another file in the same directory bundles.
+func findRedundantFiles(files []installedFile) []redundantFile {
+	type key struct{ dir, projectID string }
+	byProject := make(map[key][]installedFile)
+	bundlers := make(map[key]installedFile)
+	bundledDates := make(map[key]time.Time)
+	for _, f := range files {
+		dir := filepath.Dir(f.Path)
+		if f.ProjectID != "" {
+			byProject[key{dir, f.ProjectID}] = append(byProject[key{dir, f.ProjectID}], f)
+		}
+		for _, b := range f.Bundled {
+			if b.ProjectID != "" && b.ProjectID != f.ProjectID {
+				bundlers[key{dir, b.ProjectID}] = f
+				bundledDates[key{dir, b.ProjectID}] = b.Published
+			}
+		}
+	}
+
+	var redundant []redundantFile
+	for k, group := range byProject {
+		sort.SliceStable(group, func(i, j int) bool { return preferredCopy(group[i], group[j]) })
+		kept := group[0]
+		for _, f := range group[1:] {
+			redundant = append(redundant, redundantFile{File: f, KeptBy: kept.Path, Recommended: true})
+		}
+		if bundler, ok := bundlers[k]; ok {
+			bundledDate := bundledDates[k]
+			recommended := kept.Published.IsZero() || !bundledDate.Before(kept.Published)
+			redundant = append(redundant, redundantFile{File: kept, KeptBy: bundler.Path, Bundled: true, Recommended: recommended})
+		}
+	}
+	sort.Slice(redundant, func(i, j int) bool { return redundant[i].File.Path < redundant[j].File.Path })
+	return redundant
+}
+
+// preferredCopy orders copies of a project: the tracked file first, then the newest, then by path.
+func preferredCopy(a, b installedFile) bool {
+	if a.Tracked != b.Tracked {
+		return a.Tracked
+	}
+	if !a.Published.Equal(b.Published) {
+		return a.Published.After(b.Published)
+	}
+	return a.Path < b.Path
+}
+
+// removeRedundantFile removes a redundant file. A tracked file is forgotten and its project unfollowed,
+// so update does not install it again.
+func removeRedundantFile(cfg *config.Config, client *modrinth.Client, file installedFile, mods []db.Mod) error {
+	for _, mod := range mods {
+		if mod.InstallPath != file.Path {
+			continue
+		}
+		if isModrinthMod(mod) && mod.ProjectID != "" {
+			if err := unfollowProject(cfg, client, mod.ProjectID); err != nil {
+				return err
+			}
+		}
+		return pruneMod(cfg, mod, cfg.KeepOldVersions, logger.Log.With(zap.String("project_slug", mod.ProjectSlug)))
+	}
+	return pruneUntrackedFile(file.Path, cfg.KeepOldVersions)
+}
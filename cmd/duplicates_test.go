@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestIsBundledJar(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"META-INF/jars/cloth-config-15.0.jar", true},
+		{"META-INF/jarjar/kotlinforforge.jar", true},
+		{"META-INF/jars/nested/lib.jar", false},
+		{"META-INF/jars/readme.txt", false},
+		{"libs/other.jar", false},
+	}
+
+	for _, tt := range tests {
+		if got := isBundledJar(tt.name); got != tt.want {
+			t.Errorf("isBundledJar(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBundledJars(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mod.jar")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	for name, content := range map[string]string{
+		"fabric.mod.json":           "{}",
+		"META-INF/jars/library.jar": "hello",
+	} {
+		entry, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := entry.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	bundled, err := bundledJars(path)
+	if err != nil {
+		t.Fatalf("bundledJars() error = %v", err)
+	}
+	// sha1("hello")
+	if len(bundled) != 1 || bundled[0].SHA1 != "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d" {
+		t.Errorf("bundledJars() = %+v", bundled)
+	}
+
+	if bundled, err := bundledJars(filepath.Join(t.TempDir(), "pack.zip")); err != nil || bundled != nil {
+		t.Errorf("bundledJars(zip) = %v, %v, want nothing", bundled, err)
+	}
+}
+
+func TestFindRedundantFiles(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	files := []installedFile{
+		{Path: "/mods/sodium-old.jar", ProjectID: "sodium", Published: older},
+		{Path: "/mods/sodium-new.jar", ProjectID: "sodium", Published: newer},
+		{Path: "/mods/lithium-renamed.jar", ProjectID: "lithium", Published: newer},
+		{Path: "/mods/lithium.jar", ProjectID: "lithium", Published: older, Tracked: true},
+		{Path: "/mods/cloth-config.jar", ProjectID: "cloth", Published: older},
+		{Path: "/mods/fabric-api.jar", ProjectID: "fabric-api", Published: newer},
+		{Path: "/mods/big-mod.jar", ProjectID: "big", Bundled: []bundledJar{
+			{Name: "META-INF/jars/cloth.jar", ProjectID: "cloth", Published: older},
+			{Name: "META-INF/jars/fapi.jar", ProjectID: "fabric-api", Published: older},
+		}},
+		{Path: "/world2/datapacks/sodium-new.jar", ProjectID: "sodium", Published: newer},
+		{Path: "/mods/unknown.jar"},
+	}
+
+	var got []string
+	for _, r := range findRedundantFiles(files) {
+		got = append(got, r.label())
+		if r.Recommended != (r.File.Path != "/mods/fabric-api.jar") {
+			t.Errorf("%s: Recommended = %v", r.File.Path, r.Recommended)
+		}
+	}
+	want := []string{
+		"cloth-config.jar, bundled in big-mod.jar",
+		"fabric-api.jar, bundled in big-mod.jar (newer than the bundled copy)",
+		"lithium-renamed.jar, duplicate of lithium.jar",
+		"sodium-old.jar, duplicate of sodium-new.jar",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("findRedundantFiles() = %v, want %v", got, want)
+	}
+}
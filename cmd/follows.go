@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/logger"
+	"modrinth-mod-updater/modrinth"
+	"modrinth-mod-updater/provider"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// followsCmd groups the commands managing followed projects
+var followsCmd = &cobra.Command{
+	Use:   "follows",
+	Short: "Manage followed Modrinth projects",
+}
+
+// followsSyncCmd represents the follows sync command
+var followsSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Follow installed projects that are not followed yet",
+	Long: `Compare the installed Modrinth projects with the followed ones and follow the missing ones,
+so that update keeps mods dropped in manually (and imported by hash) up to date.
+
+With --unfollow, followed projects that are no longer installed are unfollowed as well.
+The pending changes are shown as a checklist for confirmation unless --yes is given.
+
+Example: modrinth-mod-updater follows sync
+Example: modrinth-mod-updater follows sync --unfollow --yes`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		unfollow, _ := cmd.Flags().GetBool("unfollow")
+		assumeYes, _ := cmd.Flags().GetBool("yes")
+		syncFollows(unfollow, assumeYes)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(followsCmd)
+	followsCmd.AddCommand(followsSyncCmd)
+
+	followsSyncCmd.Flags().Bool("unfollow", false, "Also unfollow projects that are no longer installed")
+	followsSyncCmd.Flags().BoolP("yes", "y", false, "Apply all changes without confirmation")
+}
+
+func syncFollows(unfollow, assumeYes bool) {
+	cfg, client := bootstrap(".")
+
+	followed, err := followedProjects(&cfg, client)
+	if err != nil {
+		logger.Log.Fatalw("Failed to get followed projects", zap.Error(err))
+	}
+	var installed []db.Mod
+	if err := db.DB.Find(&installed).Error; err != nil {
+		logger.Log.Fatalw("Failed to load mods from database", zap.Error(err))
+	}
+
+	var removedSlugs []string
+	if err := db.DB.Model(&db.ModVersion{}).Distinct().Pluck("project_slug", &removedSlugs).Error; err != nil {
+		logger.Log.Fatalw("Failed to load version history from database", zap.Error(err))
+	}
+	removed := make(map[string]bool, len(removedSlugs))
+	for _, slug := range removedSlugs {
+		removed[slug] = true
+	}
+	installable := func(p modrinth.Project) bool {
+		p.ProjectType = effectiveProjectType(p, &cfg)
+		return shouldProcessProject(p, &cfg, zap.NewNop().Sugar())
+	}
+
+	toFollow, toUnfollow := diffFollows(installed, followed, removed, installable)
+	if !unfollow {
+		toUnfollow = nil
+	}
+	if len(toFollow) == 0 && len(toUnfollow) == 0 {
+		fmt.Println("Follows are in sync with the installed projects.")
+		return
+	}
+
+	var items []checklistItem
+	for _, mod := range toFollow {
+		items = append(items, checklistItem{Label: "follow   " + mod.Title, Checked: true})
+	}
+	for _, p := range toUnfollow {
+		items = append(items, checklistItem{Label: "unfollow " + p.Title, Checked: true})
+	}
+	selected, err := confirmChecklist("Sync follows with installed projects", items, assumeYes)
+	if err != nil {
+		logger.Log.Fatalw("Failed to run confirmation UI", zap.Error(err))
+	}
+
+	applied := 0
+	for _, i := range selected {
+		if i < len(toFollow) {
+			mod := toFollow[i]
+			err = followProject(&cfg, client, &modrinth.Project{ID: mod.ProjectID, Slug: mod.ProjectSlug})
+		} else {
+			err = unfollowProject(&cfg, client, toUnfollow[i-len(toFollow)].ID)
+		}
+		if err != nil {
+			logger.Log.Errorw("Failed to apply follow change", zap.String("item", items[i].Label), zap.Error(err))
+			fmt.Printf("  ✗ %s: %v\n", items[i].Label, err)
+			continue
+		}
+		fmt.Printf("  ✓ %s\n", items[i].Label)
+		applied++
+	}
+	fmt.Printf("Applied %d of %d changes.\n", applied, len(items))
+}
+
+// diffFollows returns the installed Modrinth projects that are not followed, and the followed projects
+// that were installed before but are not anymore. removed holds the slugs with a version history, i.e.
+// projects whose files were updated, archived or pruned. Projects installable rejects, e.g. because of
+// the installation type, are never installed by update and are left followed.
+func diffFollows(installed []db.Mod, followed []modrinth.Project, removed map[string]bool, installable func(modrinth.Project) bool) ([]db.Mod, []modrinth.Project) {
+	followedIDs := make(map[string]bool, len(followed))
+	for _, p := range followed {
+		followedIDs[p.ID] = true
+	}
+	installedIDs := make(map[string]bool, len(installed))
+
+	var toFollow []db.Mod
+	for _, mod := range installed {
+		if !isModrinthMod(mod) || mod.ProjectID == "" {
+			continue
+		}
+		installedIDs[mod.ProjectID] = true
+		if !followedIDs[mod.ProjectID] {
+			toFollow = append(toFollow, mod)
+		}
+	}
+
+	var toUnfollow []modrinth.Project
+	for _, p := range followed {
+		// Modpacks and other unsupported types are followed for other reasons and never installed.
+		if isSupportedProjectType(p.ProjectType) && !installedIDs[p.ID] && removed[p.Slug] && installable(p) {
+			toUnfollow = append(toUnfollow, p)
+		}
+	}
+	return toFollow, toUnfollow
+}
+
+// followedProjects returns the user's followed Modrinth projects when an API key is configured,
+// together with the projects on the local follow list.
+func followedProjects(cfg *config.Config, client *modrinth.Client) ([]modrinth.Project, error) {
+	var projects []modrinth.Project
+	if cfg.ModrinthAPIKey != "" {
+		followed, err := client.GetFollowedProjects()
+		if err != nil {
+			return nil, err
+		}
+		projects = followed
+	}
+
+	var local []db.LocalFollow
+	if err := db.DB.Find(&local).Error; err != nil {
+		return nil, fmt.Errorf("failed to load local follows: %w", err)
+	}
+	var ids []string
+	for _, f := range local {
+		if !slices.ContainsFunc(projects, func(p modrinth.Project) bool { return p.ID == f.ProjectID }) {
+			ids = append(ids, f.ProjectID)
+		}
+	}
+	localProjects, err := client.GetProjects(ids)
+	if err != nil {
+		return nil, err
+	}
+	return append(projects, localProjects...), nil
+}
+
+// followProject follows a project on Modrinth, or adds it to the local follow list when no API key is configured.
+func followProject(cfg *config.Config, client *modrinth.Client, project *modrinth.Project) error {
+	if cfg.ModrinthAPIKey != "" {
+		if err := client.FollowProject(project.ID); err != nil {
+			return err
+		}
+	} else {
+		follow := db.LocalFollow{ProjectID: project.ID, ProjectSlug: project.Slug}
+		if err := db.DB.Where("project_id = ?", project.ID).FirstOrCreate(&follow).Error; err != nil {
+			return err
+		}
+	}
+	return markFollowed([]string{project.ID})
+}
+
+// markFollowed records that the installed mods of the given Modrinth projects are followed, which makes
+// them subject to UNFOLLOWED_POLICY once they are unfollowed.
+func markFollowed(projectIDs []string) error {
+	if len(projectIDs) == 0 {
+		return nil
+	}
+	return db.DB.Model(&db.Mod{}).
+		Where("project_id IN ? AND provider IN ? AND followed = ?", projectIDs, []string{provider.ModrinthName, ""}, false).
+		Update("followed", true).Error
+}
+
+// unfollowProject unfollows a project on Modrinth when an API key is configured and removes it from the local follow list.
+func unfollowProject(cfg *config.Config, client *modrinth.Client, projectID string) error {
+	if cfg.ModrinthAPIKey != "" {
+		if err := client.UnfollowProject(projectID); err != nil {
+			return err
+		}
+	}
+	return db.DB.Unscoped().Where("project_id = ?", projectID).Delete(&db.LocalFollow{}).Error
+}
+
+// parseProjectRef extracts a project slug or ID from a slug, ID or Modrinth URL
+// such as https://modrinth.com/mod/sodium or https://modrinth.com/mod/sodium/version/0.6.0.
+func parseProjectRef(arg string) (string, error) {
+	u, err := url.Parse(arg)
+	if err != nil || u.Host == "" {
+		return arg, nil
+	}
+	if u.Host != "modrinth.com" && !strings.HasSuffix(u.Host, ".modrinth.com") {
+		return "", fmt.Errorf("%s is not a Modrinth URL", arg)
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	// API URLs look like https://api.modrinth.com/v2/project/<id>.
+	if len(segments) > 0 && segments[0] == "v2" {
+		segments = segments[1:]
+	}
+	if len(segments) < 2 || segments[1] == "" {
+		return "", fmt.Errorf("%s does not point to a project", arg)
+	}
+	return segments[1], nil
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"testing"
+
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/modrinth"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDiffFollows(t *testing.T) {
+	installed := []db.Mod{
+		{ProjectSlug: "sodium", ProjectID: "AANobbMI"},
+		{ProjectSlug: "lithium", ProjectID: "gvQqBUqZ"},
+		{ProjectSlug: "from-github", Provider: "github"},
+	}
+	followed := []modrinth.Project{
+		{ID: "AANobbMI", Slug: "sodium", ProjectType: "mod"},
+		{ID: "P7dR8mSH", Slug: "fabric-api", ProjectType: "mod"},
+		{ID: "1KVo5zza", Slug: "fabulously-optimized", ProjectType: "modpack"},
+		// Followed but never installed, e.g. just followed on the website.
+		{ID: "NNAgCjsB", Slug: "entityculling", ProjectType: "mod"},
+		// Installed before, but update skips it on this installation.
+		{ID: "YL57xq9U", Slug: "iris", ProjectType: "mod", ServerSide: "unsupported"},
+	}
+	removed := map[string]bool{"fabric-api": true, "iris": true}
+	installable := func(p modrinth.Project) bool { return p.ServerSide != "unsupported" }
+
+	toFollow, toUnfollow := diffFollows(installed, followed, removed, installable)
+	if len(toFollow) != 1 || toFollow[0].ProjectSlug != "lithium" {
+		t.Errorf("toFollow = %+v, want only lithium", toFollow)
+	}
+	if len(toUnfollow) != 1 || toUnfollow[0].Slug != "fabric-api" {
+		t.Errorf("toUnfollow = %+v, want only fabric-api", toUnfollow)
+	}
+}
+
+func TestChecklistSelected(t *testing.T) {
+	m := newChecklistModel("title", []checklistItem{{Label: "a", Checked: true}, {Label: "b"}, {Label: "c", Checked: true}})
+	if got := m.Selected(); got != nil {
+		t.Errorf("Selected() before confirming = %v, want nil", got)
+	}
+	m.confirmed = true
+	if got := m.Selected(); len(got) != 2 || got[0] != 0 || got[1] != 2 {
+		t.Errorf("Selected() = %v, want [0 2]", got)
+	}
+}
+
+func TestParseProjectRef(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    string
+		wantErr bool
+	}{
+		{"sodium", "sodium", false},
+		{"AANobbMI", "AANobbMI", false},
+		{"https://modrinth.com/mod/sodium", "sodium", false},
+		{"https://modrinth.com/shader/complementary-reimagined/versions", "complementary-reimagined", false},
+		{"https://modrinth.com/mod/sodium/version/mc1.21.1-0.6.0", "sodium", false},
+		{"https://api.modrinth.com/v2/project/AANobbMI", "AANobbMI", false},
+		{"https://modrinth.com/mods", "", true},
+		{"https://www.curseforge.com/minecraft/mc-mods/jei", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseProjectRef(tt.arg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseProjectRef(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseProjectRef(%q) = %q, want %q", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestChecklistUpdate(t *testing.T) {
+	var m tea.Model = newChecklistModel("title", []checklistItem{{Label: "a", Checked: true}, {Label: "b", Checked: true}})
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyDown},
+		{Type: tea.KeySpace, Runes: []rune{' '}},
+		{Type: tea.KeyEnter},
+	} {
+		m, _ = m.Update(key)
+	}
+
+	if got := m.(ChecklistModel).Selected(); len(got) != 1 || got[0] != 0 {
+		t.Errorf("Selected() after unchecking b = %v, want [0]", got)
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,6 +13,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 
 	"modrinth-mod-updater/config"
 	"modrinth-mod-updater/db"
@@ -45,6 +47,7 @@ type ModInfo struct {
 	ProjectType        string
 	Selected           bool // Whether this mod is selected for download
 	Selectable         bool // Whether this mod can be selected (not up-to-date)
+	Disabled           bool // Whether the installed file is disabled
 }
 
 // Model represents the state of the TUI
@@ -122,6 +125,10 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if len(m.mods) > 0 && m.mods[m.selectedIndex].Selectable {
 			m.mods[m.selectedIndex].Selected = !m.mods[m.selectedIndex].Selected
 		}
+	case "e":
+		if len(m.mods) > 0 && !m.downloading {
+			return m, m.toggleSelectedMod()
+		}
 	case "ctrl+d":
 		if !m.downloading {
 			m.downloading = true
@@ -250,7 +257,7 @@ func renderFooter() string {
 		Foreground(lipgloss.Color("8")).
 		Italic(true)
 
-	return footerStyle.Render("↑/k: up  ↓/j: down  space: select  ctrl+d: download  q: quit")
+	return footerStyle.Render("↑/k: up  ↓/j: down  space: select  e: enable/disable  ctrl+d: download  q: quit")
 }
 
 func (m Model) renderModRow(index int, mod ModInfo) string {
@@ -265,6 +272,12 @@ func (m Model) renderModRow(index int, mod ModInfo) string {
 	default:
 		statusColor = "7" // White
 	}
+	status := mod.Status
+	if mod.Disabled {
+		// Disabled mods can still be updated, but show their state instead of the update status.
+		status = "disabled"
+		statusColor = "8" // Grey
+	}
 
 	rowStyle := lipgloss.NewStyle().Padding(0, 1)
 	isSelected := index == m.selectedIndex
@@ -286,7 +299,7 @@ func (m Model) renderModRow(index int, mod ModInfo) string {
 	}
 
 	// Pad status before applying color to maintain column alignment
-	paddedStatus := fmt.Sprintf("%-15s", mod.Status)
+	paddedStatus := fmt.Sprintf("%-15s", status)
 	coloredStatus := statusStyle.Render(paddedStatus)
 
 	row := fmt.Sprintf("%s %-39s %-20s %-20s %s",
@@ -341,7 +354,7 @@ func (m Model) loadMods() tea.Cmd {
 
 func (m Model) fetchModsWithProgress() ([]ModInfo, error) {
 	// Get followed projects from Modrinth
-	followedProjects, err := m.client.GetFollowedProjects()
+	followedProjects, err := followedProjects(&m.cfg, m.client)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get followed projects: %w", err)
 	}
@@ -350,13 +363,14 @@ func (m Model) fetchModsWithProgress() ([]ModInfo, error) {
 	processedCount := 0
 
 	for _, project := range followedProjects {
-		// Skip non-mod/shader/resourcepack projects
-		if project.ProjectType != "mod" && project.ProjectType != "shader" && project.ProjectType != "resourcepack" {
+		// Skip project types the updater cannot install
+		if !isSupportedProjectType(project.ProjectType) {
 			continue
 		}
+		project.ProjectType = effectiveProjectType(project, &m.cfg)
 
 		// Get latest version
-		versions, err := m.client.GetProjectVersions(project.Slug, project.ProjectType, m.cfg.MinecraftVersion, m.cfg.MinecraftLoader)
+		versions, err := fetchCompatibleVersions(m.client, &m.cfg, project.Slug, project.ProjectType)
 		if err != nil || len(versions) == 0 {
 			continue
 		}
@@ -365,7 +379,7 @@ func (m Model) fetchModsWithProgress() ([]ModInfo, error) {
 
 		// Check if mod is installed
 		var installedMod db.Mod
-		result := db.DB.Where("project_slug = ?", project.Slug).First(&installedMod)
+		installedErr := findModrinthMod(project.Slug, &installedMod)
 
 		var modInfo ModInfo
 		modInfo.Title = project.Title
@@ -374,10 +388,11 @@ func (m Model) fetchModsWithProgress() ([]ModInfo, error) {
 		modInfo.ProjectType = project.ProjectType
 		modInfo.AvailableVersion = latestVersion.VersionNumber
 
-		if result.Error == nil {
+		if installedErr == nil {
 			// Mod is installed
 			modInfo.InstalledVersion = installedMod.VersionNumber
 			modInfo.InstalledVersionID = installedMod.VersionID
+			modInfo.Disabled = installedMod.Disabled
 			if installedMod.VersionID == latestVersion.ID {
 				modInfo.Status = "up-to-date"
 				modInfo.Selectable = false // Can't select up-to-date mods
@@ -437,44 +452,61 @@ func (m Model) downloadSelectedMods() tea.Cmd {
 }
 
 func (m Model) downloadAndRecordMod(mod ModInfo) error {
-	versions, err := m.client.GetProjectVersions(mod.Slug, mod.ProjectType, m.cfg.MinecraftVersion, m.cfg.MinecraftLoader)
+	versions, err := fetchCompatibleVersions(m.client, &m.cfg, mod.Slug, mod.ProjectType)
 	if err != nil || len(versions) == 0 {
 		return fmt.Errorf("failed to get versions: %w", err)
 	}
 
-	latestVersion := versions[0]
-	primaryFile := findPrimaryFile(latestVersion)
+	var existingMod db.Mod
+	var installed *db.Mod
+	switch err := findModrinthMod(mod.Slug, &existingMod); {
+	case err == nil:
+		installed = &existingMod
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return err
+	}
+
+	selected, err := selectVersion(m.client, &m.cfg, mod.ProjectType, versions, installed, logger.Log)
+	if err != nil {
+		return err
+	}
+	defer selected.discard()
+	latestVersion, primaryFile := selected.Version, selected.File
 	if primaryFile == nil {
 		return fmt.Errorf("no files found for version %s", latestVersion.ID)
 	}
 
-	targetSubDir := getTargetSubDir(mod.ProjectType)
-	projectBaseDir := filepath.Join(m.cfg.MinecraftDir, targetSubDir)
+	projectBaseDir := projectBaseDir(&m.cfg, mod.ProjectType)
 	downloadPath := filepath.Join(projectBaseDir, primaryFile.Filename)
 
 	// Before downloading, archive the old version if it exists in the database
-	var existingMod db.Mod
-	result := db.DB.Where("project_slug = ?", mod.Slug).First(&existingMod)
-	if result.Error == nil {
+	if installed != nil {
 		archiveAndCleanupOld(existingMod, projectBaseDir, &m.cfg, logger.Log)
 	}
 
-	if err := m.client.DownloadModFile(logger.Log, downloadPath, primaryFile.URL); err != nil {
+	if err := installSelectedFile(m.client, selected, downloadPath, logger.Log); err != nil {
 		return fmt.Errorf("download failed: %w", err)
 	}
+	if existingMod.Disabled {
+		if err := keepDisabled(existingMod, downloadPath); err != nil {
+			return fmt.Errorf("failed to keep mod disabled: %w", err)
+		}
+	} else if mod.ProjectType == "datapack" {
+		syncDatapackCopies(&m.cfg, downloadPath, existingMod.FileName, logger.Log)
+	}
 
 	return m.updateModDatabase(mod, latestVersion, primaryFile, downloadPath)
 }
 
 func (m Model) updateModDatabase(mod ModInfo, latestVersion modrinth.Version, primaryFile *modrinth.File, downloadPath string) error {
 	var existingMod db.Mod
-	result := db.DB.Where("project_slug = ?", mod.Slug).First(&existingMod)
-
-	if result.Error == nil {
+	if err := findModrinthMod(mod.Slug, &existingMod); err == nil {
 		existingMod.VersionID = latestVersion.ID
 		existingMod.VersionNumber = latestVersion.VersionNumber
 		existingMod.FileName = primaryFile.Filename
 		existingMod.InstallPath = downloadPath
+		existingMod.ProjectType = mod.ProjectType
+		applyFileMetadata(&existingMod, m.cfg.MinecraftLoader)
 		return db.DB.Save(&existingMod).Error
 	}
 
@@ -482,6 +514,7 @@ func (m Model) updateModDatabase(mod ModInfo, latestVersion modrinth.Version, pr
 		ProjectSlug:   mod.Slug,
 		ProjectID:     mod.Slug,
 		Title:         mod.Title,
+		ProjectType:   mod.ProjectType,
 		Color:         mod.Color,
 		Updated:       time.Now(),
 		VersionID:     latestVersion.ID,
@@ -489,6 +522,7 @@ func (m Model) updateModDatabase(mod ModInfo, latestVersion modrinth.Version, pr
 		FileName:      primaryFile.Filename,
 		InstallPath:   downloadPath,
 	}
+	applyFileMetadata(&newMod, m.cfg.MinecraftLoader)
 	return db.DB.Create(&newMod).Error
 }
 
@@ -510,3 +544,29 @@ func runGUI() {
 		logger.Log.Fatalw("Failed to run GUI", zap.Error(err))
 	}
 }
+
+// toggleSelectedMod enables or disables the installed mod under the cursor.
+func (m *Model) toggleSelectedMod() tea.Cmd {
+	mod := &m.mods[m.selectedIndex]
+	var installed db.Mod
+	if err := findModrinthMod(mod.Slug, &installed); err != nil {
+		m.message = mod.Title + " is not installed"
+		return nil
+	}
+
+	disabled := !installed.Disabled
+	if err := setModDisabled(&m.cfg, &installed, disabled, logger.Log.With(zap.String("project_slug", mod.Slug))); err != nil {
+		logger.Log.Errorw("Failed to change mod state", zap.String("slug", mod.Slug), zap.Error(err))
+		m.message = fmt.Sprintf("Failed to change %s: %v", mod.Title, err)
+		return nil
+	}
+	mod.Disabled = disabled
+	if disabled {
+		m.message = "Disabled " + mod.Title
+	} else {
+		m.message = "Enabled " + mod.Title
+	}
+	return tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+		return clearMessageMsg{}
+	})
+}
@@ -2,31 +2,32 @@ package cmd
 
 import (
 	"crypto/sha1"
+	"crypto/sha512"
 	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"modrinth-mod-updater/config"
 	"modrinth-mod-updater/db"
 	"modrinth-mod-updater/logger"
-	"modrinth-mod-updater/modrinth"
+	"modrinth-mod-updater/provider"
 
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
-// importInstalledMods scans the mods directory and adds unknown mods to the database
-func importInstalledMods(client *modrinth.Client, minecraftDir string) error {
+// importInstalledMods scans the managed directories and adds unknown mods to the database,
+// identifying them with the first provider that recognizes the file.
+func importInstalledMods(providers []provider.Provider, cfg *config.Config) error {
 	logger.Log.Info("Scanning for existing mods...")
 
-	dirs := []string{
-		filepath.Join(minecraftDir, "mods"),
-		filepath.Join(minecraftDir, "shaderpacks"),
-		filepath.Join(minecraftDir, "resourcepacks"),
-	}
-
-	for _, dir := range dirs {
+	for _, dir := range managedDirs(cfg) {
 		if _, err := os.Stat(dir); os.IsNotExist(err) {
 			continue
 		}
@@ -35,7 +36,11 @@ func importInstalledMods(client *modrinth.Client, minecraftDir string) error {
 			if err != nil {
 				return err
 			}
-			return processModFile(client, path, info)
+			// Plugins keep their data in subdirectories of plugins/, which may contain unrelated archives.
+			if info.IsDir() && path != dir && filepath.Base(dir) == "plugins" {
+				return filepath.SkipDir
+			}
+			return processModFile(providers, cfg, path, info)
 		})
 
 		if err != nil {
@@ -46,7 +51,7 @@ func importInstalledMods(client *modrinth.Client, minecraftDir string) error {
 	return nil
 }
 
-func processModFile(client *modrinth.Client, path string, info os.FileInfo) error {
+func processModFile(providers []provider.Provider, cfg *config.Config, path string, info os.FileInfo) error {
 	if info.IsDir() {
 		if info.Name() == "versions" {
 			return filepath.SkipDir
@@ -54,69 +59,158 @@ func processModFile(client *modrinth.Client, path string, info os.FileInfo) erro
 		return nil
 	}
 
-	ext := strings.ToLower(filepath.Ext(path))
+	// Disabled files are imported as disabled mods, recorded under their enabled name.
+	installPath, disabled := strings.CutSuffix(path, disabledSuffix)
+	ext := strings.ToLower(filepath.Ext(installPath))
 	if ext != ".jar" && ext != ".zip" {
 		return nil
 	}
 
-	filename := info.Name()
+	filename := filepath.Base(installPath)
 	var count int64
 	db.DB.Model(&db.Mod{}).Where("file_name = ?", filename).Count(&count)
 	if count > 0 {
 		return nil
 	}
 
-	hash, err := calculateSHA1(path)
-	if err != nil {
-		logger.Log.Warnw("Failed to calculate hash", zap.String("file", filename), zap.Error(err))
-		return nil
+	match, providerName := identifyFile(providers, path)
+	if match == nil {
+		return importLocalJar(cfg, path)
 	}
-
-	version, err := client.GetVersionByHash(hash)
+	project, version := match.Project, match.Version
+	slug, err := sourceSlug(providerName, project.Ref)
 	if err != nil {
-		logger.Log.Debugw("Mod not found on Modrinth by hash", zap.String("file", filename), zap.Error(err))
+		logger.Log.Warnw("Skipping identified file", zap.String("file", filename), zap.Error(err))
 		return nil
 	}
 
-	project, err := client.GetProject(version.ProjectID)
-	if err != nil {
-		logger.Log.Warnw("Failed to get project details", zap.String("project_id", version.ProjectID), zap.Error(err))
+	var existing db.Mod
+	switch err := findProviderMod(providerName, slug, &existing); {
+	case err == nil:
+		logger.Log.Warnw("Found another file of an installed project, run duplicates to clean up",
+			zap.String("title", project.Title), zap.String("file", filename), zap.String("installed", existing.FileName))
+		return nil
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		logger.Log.Warnw("Skipping identified file", zap.String("file", filename), zap.Error(err))
 		return nil
 	}
 
 	newMod := db.Mod{
-		ProjectSlug:   project.Slug,
+		ProjectSlug:   slug,
 		ProjectID:     project.ID,
+		Provider:      providerName,
+		Source:        project.Ref,
 		Title:         project.Title,
+		ProjectType:   projectTypeForPath(installPath, project.ProjectType),
+		ClientSide:    project.ClientSide,
+		ServerSide:    project.ServerSide,
 		IconURL:       project.IconURL,
 		Color:         project.Color,
 		Updated:       time.Now(),
 		VersionID:     version.ID,
 		VersionNumber: version.VersionNumber,
 		FileName:      filename,
-		InstallPath:   path,
+		InstallPath:   installPath,
+		Disabled:      disabled,
 	}
+	applyFileMetadata(&newMod, cfg.MinecraftLoader)
 
 	if err := db.DB.Create(&newMod).Error; err != nil {
-		logger.Log.Errorw("Failed to save imported mod to DB", zap.String("slug", project.Slug), zap.Error(err))
+		logger.Log.Errorw("Failed to save imported mod to DB", zap.String("slug", slug), zap.Error(err))
 	} else {
-		logger.Log.Infow("Imported existing mod", zap.String("title", project.Title), zap.String("version", version.VersionNumber))
+		logger.Log.Infow("Imported existing mod", zap.String("title", project.Title), zap.String("version", version.VersionNumber), zap.String("provider", providerName))
 	}
 
 	return nil
 }
 
+// importLocalJar records a jar no provider recognises as a local mod, described by its loader metadata.
+// Files without metadata are left for prune to report.
+func importLocalJar(cfg *config.Config, path string) error {
+	mod := localMod(path, cfg.MinecraftLoader)
+	if mod.JarModID == "" {
+		return nil
+	}
+
+	var existing db.Mod
+	if err := db.DB.Where("project_slug = ?", mod.ProjectSlug).First(&existing).Error; err == nil {
+		logger.Log.Warnw("Found another file of an installed mod, run duplicates to clean up",
+			zap.String("title", mod.Title), zap.String("file", mod.FileName), zap.String("installed", existing.FileName))
+		return nil
+	}
+	if err := db.DB.Create(&mod).Error; err != nil {
+		logger.Log.Errorw("Failed to save imported mod to DB", zap.String("slug", mod.ProjectSlug), zap.Error(err))
+		return nil
+	}
+	logger.Log.Infow("Imported unknown jar as local mod", zap.String("title", mod.Title), zap.String("mod_id", mod.JarModID),
+		zap.String("version", mod.JarVersion), zap.String("loader", mod.JarLoader))
+	return nil
+}
+
+// identifyFile asks each provider in turn to identify a file, returning the first match and its provider name.
+func identifyFile(providers []provider.Provider, path string) (*provider.Match, string) {
+	for _, p := range providers {
+		match, err := p.IdentifyFile(path)
+		switch {
+		case err == nil:
+			return match, p.Name()
+		case errors.Is(err, provider.ErrNotSupported):
+			continue
+		case errors.Is(err, provider.ErrNotFound):
+			logger.Log.Debugw("File not found by provider", zap.String("file", filepath.Base(path)), zap.String("provider", p.Name()), zap.Error(err))
+		default:
+			logger.Log.Warnw("Failed to identify file", zap.String("file", filepath.Base(path)), zap.String("provider", p.Name()), zap.Error(err))
+		}
+	}
+	return nil, ""
+}
+
 func calculateSHA1(filePath string) (string, error) {
+	return calculateHash(filePath, sha1.New())
+}
+
+func calculateSHA512(filePath string) (string, error) {
+	return calculateHash(filePath, sha512.New())
+}
+
+func calculateHash(filePath string, h hash.Hash) (string, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return "", err
 	}
 	defer file.Close()
 
-	hash := sha1.New()
-	if _, err := io.Copy(hash, file); err != nil {
+	if _, err := io.Copy(h, file); err != nil {
 		return "", err
 	}
 
-	return hex.EncodeToString(hash.Sum(nil)), nil
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// verifyFileHashes checks a file against the expected hashes, preferring sha512 over sha1.
+// It fails when neither hash is available.
+func verifyFileHashes(filePath string, hashes map[string]string) error {
+	algorithms := []struct {
+		name string
+		calc func(string) (string, error)
+	}{
+		{"sha512", calculateSHA512},
+		{"sha1", calculateSHA1},
+	}
+
+	for _, algo := range algorithms {
+		expected, ok := hashes[algo.name]
+		if !ok || expected == "" {
+			continue
+		}
+		actual, err := algo.calc(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to hash %s: %w", filePath, err)
+		}
+		if !strings.EqualFold(actual, expected) {
+			return fmt.Errorf("%s mismatch for %s: expected %s, got %s", algo.name, filepath.Base(filePath), expected, actual)
+		}
+		return nil
+	}
+	return fmt.Errorf("no sha512 or sha1 hash to verify %s", filepath.Base(filePath))
 }
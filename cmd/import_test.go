@@ -35,3 +35,34 @@ func TestCalculateSHA1FileNotFound(t *testing.T) {
 		t.Error("Expected error for non-existent file")
 	}
 }
+
+func TestVerifyFileHashes(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "test.txt")
+	if err := os.WriteFile(filePath, []byte("hello world"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	sha1 := "2aae6c35c94fcfb415dbe95f408b9ce91ee846ed"
+	sha512 := "309ecc489c12d6eb4cc40f50c902f2b4d0ed77ee511a7c7a9bcd3ca86d4cd86f989dd35bc5ff499670da34255b45b0cfd830e81f605dcf7dc5542e93ae9cd76f"
+
+	tests := []struct {
+		name    string
+		hashes  map[string]string
+		wantErr bool
+	}{
+		{"matching sha512", map[string]string{"sha512": sha512}, false},
+		{"matching sha1", map[string]string{"sha1": sha1}, false},
+		{"sha512 preferred over bad sha1", map[string]string{"sha512": sha512, "sha1": "bad"}, false},
+		{"mismatch", map[string]string{"sha1": "0000"}, true},
+		{"no hashes", map[string]string{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyFileHashes(filePath, tt.hashes)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("verifyFileHashes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
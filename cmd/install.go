@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/logger"
+	"modrinth-mod-updater/modrinth"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// installCmd represents the install command
+var installCmd = &cobra.Command{
+	Use:   "install <version-url|version-id|slug@version>",
+	Short: "Install a specific version of a project and pin it",
+	Long: `Install a specific Modrinth version instead of the latest one, and pin the project so update keeps it.
+
+The version can be given as a Modrinth version page URL, a version ID, or as slug@version-number.
+The version must support the configured Minecraft version and loader unless --force is given.
+The previously installed file is archived or removed like during updates. Use unpin to let
+update manage the project again.
+
+Example: modrinth-mod-updater install sodium@mc1.21.1-0.6.0
+Example: modrinth-mod-updater install https://modrinth.com/mod/sodium/version/mc1.21.1-0.6.0`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+		installVersion(args[0], force)
+	},
+}
+
+// unpinCmd represents the unpin command
+var unpinCmd = &cobra.Command{
+	Use:   "unpin <slug>",
+	Short: "Let update manage a pinned project again",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		setPinned(args[0], false)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(installCmd)
+	rootCmd.AddCommand(unpinCmd)
+
+	installCmd.Flags().Bool("force", false, "Install even if the version does not support the configured Minecraft version or loader")
+}
+
+// parseVersionRef splits a version reference into project and version. The project is empty
+// when the reference is a bare version ID.
+func parseVersionRef(arg string) (project, version string, err error) {
+	if u, parseErr := url.Parse(arg); parseErr == nil && u.Host != "" {
+		if _, err := parseProjectRef(arg); err != nil {
+			return "", "", err
+		}
+		// https://modrinth.com/<type>/<slug>/version/<id-or-number>
+		segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(segments) < 4 || segments[2] != "version" || segments[3] == "" {
+			return "", "", fmt.Errorf("%s does not point to a version", arg)
+		}
+		return segments[1], segments[3], nil
+	}
+
+	if project, version, ok := strings.Cut(arg, "@"); ok {
+		if project == "" || version == "" {
+			return "", "", fmt.Errorf("invalid version reference %q, expected slug@version", arg)
+		}
+		return project, version, nil
+	}
+	return "", arg, nil
+}
+
+// checkVersionCompatibility reports why a version cannot be used with the configuration.
+func checkVersionCompatibility(cfg *config.Config, slug, projectType string, version *modrinth.Version) error {
+	gameVersions := []string{cfg.MinecraftVersion}
+	if cfg.AllowsVersionFallback(slug) {
+		gameVersions = append(gameVersions, cfg.GameVersionFallbacks...)
+	}
+	if !slices.ContainsFunc(version.GameVersions, func(v string) bool { return slices.Contains(gameVersions, v) }) {
+		return fmt.Errorf("version %s supports Minecraft %s, not %s", version.VersionNumber, strings.Join(version.GameVersions, ", "), cfg.MinecraftVersion)
+	}
+	loaders := projectLoaders(cfg, projectType)
+	if len(loaders) > 0 && !slices.ContainsFunc(version.Loaders, func(l string) bool { return slices.Contains(loaders, l) }) {
+		return fmt.Errorf("version %s supports %s, not %s", version.VersionNumber, strings.Join(version.Loaders, ", "), strings.Join(loaders, ", "))
+	}
+	return nil
+}
+
+func installVersion(ref string, force bool) {
+	cfg, client := bootstrap(".")
+
+	projectRef, versionRef, err := parseVersionRef(ref)
+	if err != nil {
+		logger.Log.Fatalw("Invalid version reference", zap.String("ref", ref), zap.Error(err))
+	}
+	var version *modrinth.Version
+	if projectRef == "" {
+		version, err = client.GetVersion(versionRef)
+	} else {
+		version, err = client.GetProjectVersion(projectRef, versionRef)
+	}
+	if err != nil {
+		logger.Log.Fatalw("Failed to get version", zap.String("ref", ref), zap.Error(err))
+	}
+	project, err := client.GetProject(version.ProjectID)
+	if err != nil {
+		logger.Log.Fatalw("Failed to get project", zap.String("project_id", version.ProjectID), zap.Error(err))
+	}
+	project.ProjectType = effectiveProjectType(*project, &cfg)
+	log := logger.Log.With(zap.String("project_slug", project.Slug), zap.String("version", version.VersionNumber))
+
+	if err := checkVersionCompatibility(&cfg, project.Slug, project.ProjectType, version); err != nil {
+		if !force {
+			log.Fatalw("Version is not compatible, use --force to install anyway", zap.Error(err))
+		}
+		log.Warnw("Installing incompatible version", zap.Error(err))
+	}
+
+	primaryFile := findPrimaryFile(*version)
+	if primaryFile == nil {
+		log.Fatal("Version has no files")
+	}
+
+	if err := installPinnedVersion(&cfg, client, project, version, primaryFile, log); err != nil {
+		log.Fatalw("Failed to install version", zap.Error(err))
+	}
+	fmt.Printf("Installed %s %s and pinned it\n", project.Title, version.VersionNumber)
+}
+
+// installPinnedVersion replaces the installed file of a project with the given version and pins it.
+func installPinnedVersion(cfg *config.Config, client *modrinth.Client, project *modrinth.Project, version *modrinth.Version, file *modrinth.File, log *zap.SugaredLogger) error {
+	baseDir := projectBaseDir(cfg, project.ProjectType)
+
+	var mod db.Mod
+	err := findModrinthMod(project.Slug, &mod)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err == nil && mod.FileName != "" {
+		oldDir := filepath.Dir(mod.InstallPath)
+		if _, statErr := os.Stat(filepath.Join(oldDir, installedFileName(mod))); statErr == nil {
+			if mod.VersionID == version.ID {
+				mod.Pinned = true
+				return db.DB.Save(&mod).Error
+			}
+			archiveAndCleanupOld(mod, oldDir, cfg, log)
+		}
+	}
+
+	downloadPath := filepath.Join(baseDir, file.Filename)
+	if err := client.DownloadModFile(log, downloadPath, file.URL); err != nil {
+		return err
+	}
+	if err := verifyFileHashes(downloadPath, file.Hashes); err != nil {
+		os.Remove(downloadPath)
+		return err
+	}
+	if mod.Disabled {
+		if err := keepDisabled(mod, downloadPath); err != nil {
+			return err
+		}
+	} else if project.ProjectType == "datapack" {
+		syncDatapackCopies(cfg, downloadPath, mod.FileName, log)
+	}
+
+	applyInstalledVersion(cfg, &mod, project, version, downloadPath)
+	mod.Pinned = true
+	return db.DB.Save(&mod).Error
+}
+
+func setPinned(slug string, pinned bool) {
+	bootstrapLocal(".")
+
+	result := db.DB.Model(&db.Mod{}).Where("project_slug = ?", slug).Update("pinned", pinned)
+	if result.Error != nil {
+		logger.Log.Fatalw("Failed to update database record", zap.Error(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		logger.Log.Fatalw("Mod not found in database", zap.String("slug", slug))
+	}
+	fmt.Printf("Updated %s (pinned: %t)\n", slug, pinned)
+}
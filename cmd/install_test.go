@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"testing"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/modrinth"
+)
+
+func TestParseVersionRef(t *testing.T) {
+	tests := []struct {
+		arg         string
+		wantProject string
+		wantVersion string
+		wantErr     bool
+	}{
+		{"AABBCCDD", "", "AABBCCDD", false},
+		{"sodium@mc1.21.1-0.6.0", "sodium", "mc1.21.1-0.6.0", false},
+		{"https://modrinth.com/mod/sodium/version/mc1.21.1-0.6.0", "sodium", "mc1.21.1-0.6.0", false},
+		{"https://modrinth.com/datapack/terralith/version/2.5.5", "terralith", "2.5.5", false},
+		{"https://modrinth.com/mod/sodium", "", "", true},
+		{"https://example.com/mod/sodium/version/1.0", "", "", true},
+		{"sodium@", "", "", true},
+		{"@1.0", "", "", true},
+	}
+
+	for _, tt := range tests {
+		project, version, err := parseVersionRef(tt.arg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseVersionRef(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			continue
+		}
+		if project != tt.wantProject || version != tt.wantVersion {
+			t.Errorf("parseVersionRef(%q) = %q, %q, want %q, %q", tt.arg, project, version, tt.wantProject, tt.wantVersion)
+		}
+	}
+}
+
+func TestCheckVersionCompatibility(t *testing.T) {
+	cfg := &config.Config{
+		MinecraftVersion:        "1.21.5",
+		MinecraftLoader:         "fabric",
+		GameVersionFallbacks:    []string{"1.21.4"},
+		VersionFallbackProjects: []string{"lithium"},
+	}
+
+	tests := []struct {
+		name        string
+		slug        string
+		projectType string
+		version     modrinth.Version
+		wantErr     bool
+	}{
+		{"compatible", "sodium", "mod", modrinth.Version{GameVersions: []string{"1.21.5"}, Loaders: []string{"fabric", "quilt"}}, false},
+		{"wrong game version", "sodium", "mod", modrinth.Version{GameVersions: []string{"1.21.4"}, Loaders: []string{"fabric"}}, true},
+		{"fallback allowed", "lithium", "mod", modrinth.Version{GameVersions: []string{"1.21.4"}, Loaders: []string{"fabric"}}, false},
+		{"wrong loader", "sodium", "mod", modrinth.Version{GameVersions: []string{"1.21.5"}, Loaders: []string{"forge"}}, true},
+		{"resourcepack ignores loader", "faithful", "resourcepack", modrinth.Version{GameVersions: []string{"1.21.5"}, Loaders: []string{"minecraft"}}, false},
+	}
+
+	for _, tt := range tests {
+		err := checkVersionCompatibility(cfg, tt.slug, tt.projectType, &tt.version)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkVersionCompatibility(%s) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
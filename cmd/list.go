@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/jarmeta"
+	"modrinth-mod-updater/logger"
+	"modrinth-mod-updater/modrinth"
+	"modrinth-mod-updater/packwiz"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// listFilter selects the mods shown by list. Empty fields match everything.
+type listFilter struct {
+	ProjectType string
+	Side        string // client or server
+	Pinned      string // yes or no
+	Disabled    string // yes or no
+	Source      string // provider name
+	Kind        string // dependency or explicit
+}
+
+// listEntry is a row of the list output.
+type listEntry struct {
+	Slug        string `json:"slug"`
+	Title       string `json:"title"`
+	ProjectType string `json:"project_type"`
+	Version     string `json:"version"`
+	Source      string `json:"source"`
+	Side        string `json:"side"` // both, client or server
+	Pinned      bool   `json:"pinned"`
+	Disabled    bool   `json:"disabled"`
+	Kind        string `json:"kind"`      // dependency, explicit or unknown
+	FileName    string `json:"file_name"` // Name on disk, with the .disabled suffix of disabled mods
+}
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the tracked mods",
+	Long: `List every mod, shader, resource pack, datapack and plugin recorded in the database.
+
+A project counts as a dependency when the installed version of another tracked mod requires it,
+and as explicit otherwise.
+
+Example: modrinth-mod-updater list --type mod --side server
+Example: modrinth-mod-updater list --kind dependency --format markdown`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		filter := listFilter{}
+		filter.ProjectType, _ = cmd.Flags().GetString("type")
+		filter.Side, _ = cmd.Flags().GetString("side")
+		filter.Pinned, _ = cmd.Flags().GetString("pinned")
+		filter.Disabled, _ = cmd.Flags().GetString("disabled")
+		filter.Source, _ = cmd.Flags().GetString("source")
+		filter.Kind, _ = cmd.Flags().GetString("kind")
+		format, _ := cmd.Flags().GetString("format")
+		listMods(filter, format)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(infoCmd)
+
+	listCmd.Flags().String("type", "", "Only show this project type (mod, shader, resourcepack, datapack, plugin)")
+	listCmd.Flags().String("side", "", "Only show projects that run on this side (client or server)")
+	listCmd.Flags().String("pinned", "", "Only show pinned (yes) or unpinned (no) projects")
+	listCmd.Flags().String("disabled", "", "Only show disabled (yes) or enabled (no) projects")
+	listCmd.Flags().String("source", "", "Only show projects from this provider (modrinth, github, maven, curseforge)")
+	listCmd.Flags().String("kind", "", "Only show dependencies or explicitly installed projects (dependency or explicit)")
+	listCmd.Flags().StringP("format", "f", "table", "Output format: table, json, csv or markdown")
+}
+
+func listMods(filter listFilter, format string) {
+	_, client := bootstrapLocal(".")
+
+	var mods []db.Mod
+	if err := db.DB.Order("title").Find(&mods).Error; err != nil {
+		logger.Log.Fatalw("Failed to load mods from database", zap.Error(err))
+	}
+
+	dependencies, err := requiredProjectIDs(client, mods)
+	if err != nil {
+		logger.Log.Warnw("Failed to determine dependencies", zap.Error(err))
+	}
+
+	var entries []listEntry
+	for _, mod := range mods {
+		entry := newListEntry(mod, dependencies)
+		if filter.matches(mod, entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	if err := writeList(os.Stdout, entries, format); err != nil {
+		logger.Log.Fatalw("Failed to write list", zap.Error(err))
+	}
+}
+
+// requiredProjectIDs returns the IDs of projects required by the installed version of any tracked mod.
+// It returns nil when the versions cannot be fetched.
+func requiredProjectIDs(client *modrinth.Client, mods []db.Mod) (map[string]bool, error) {
+	versions, err := fetchVersionsByID(client, mods)
+	if err != nil {
+		return nil, err
+	}
+	required := make(map[string]bool)
+	for _, v := range versions {
+		for _, dep := range v.Dependencies {
+			if dep.DependencyType == "required" && dep.ProjectID != "" {
+				required[dep.ProjectID] = true
+			}
+		}
+	}
+	return required, nil
+}
+
+func newListEntry(mod db.Mod, dependencies map[string]bool) listEntry {
+	entry := listEntry{
+		Slug:        mod.ProjectSlug,
+		Title:       mod.Title,
+		ProjectType: mod.ProjectType,
+		Version:     mod.VersionNumber,
+		Source:      mod.Provider,
+		Side:        packwiz.SideFromSupport(mod.ClientSide, mod.ServerSide),
+		Pinned:      mod.Pinned,
+		Disabled:    mod.Disabled,
+		Kind:        "explicit",
+		FileName:    installedFileName(mod),
+	}
+	if entry.Source == "" {
+		entry.Source = "modrinth"
+	}
+	switch {
+	case dependencies == nil:
+		entry.Kind = "unknown"
+	case mod.ProjectID != "" && dependencies[mod.ProjectID]:
+		entry.Kind = "dependency"
+	}
+	return entry
+}
+
+func (f listFilter) matches(mod db.Mod, entry listEntry) bool {
+	if f.ProjectType != "" && !strings.EqualFold(entry.ProjectType, f.ProjectType) {
+		return false
+	}
+	if f.Side != "" && !projectSupportsInstallationType(modrinth.Project{ClientSide: mod.ClientSide, ServerSide: mod.ServerSide, ProjectType: mod.ProjectType}, f.Side) {
+		return false
+	}
+	if f.Pinned != "" && entry.Pinned != isYes(f.Pinned) {
+		return false
+	}
+	if f.Disabled != "" && entry.Disabled != isYes(f.Disabled) {
+		return false
+	}
+	if f.Source != "" && !strings.EqualFold(entry.Source, f.Source) {
+		return false
+	}
+	return f.Kind == "" || strings.EqualFold(entry.Kind, f.Kind)
+}
+
+func isYes(value string) bool {
+	b, err := strconv.ParseBool(value)
+	return (err == nil && b) || strings.EqualFold(value, "yes")
+}
+
+// writeList renders the entries in the given format.
+func writeList(w io.Writer, entries []listEntry, format string) error {
+	header := []string{"SLUG", "TITLE", "TYPE", "VERSION", "SOURCE", "SIDE", "PINNED", "KIND", "FILE"}
+	rows := make([][]string, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, []string{e.Slug, e.Title, e.ProjectType, e.Version, e.Source, e.Side, strconv.FormatBool(e.Pinned), e.Kind, e.FileName})
+	}
+
+	switch strings.ToLower(format) {
+	case "json":
+		if entries == nil {
+			entries = []listEntry{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	case "markdown", "md":
+		fmt.Fprintf(w, "| %s |\n", strings.Join(header, " | "))
+		fmt.Fprintf(w, "|%s\n", strings.Repeat(" --- |", len(header)))
+		for _, row := range rows {
+			for i := range row {
+				row[i] = strings.ReplaceAll(row[i], "|", `\|`)
+			}
+			fmt.Fprintf(w, "| %s |\n", strings.Join(row, " | "))
+		}
+		return nil
+	case "table", "":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown format %q, expected table, json, csv or markdown", format)
+	}
+}
+
+// infoCmd represents the info command
+var infoCmd = &cobra.Command{
+	Use:   "info <slug>",
+	Short: "Show details of a tracked mod",
+	Long: `Show the database record of a tracked mod, whether its file is present and matches the
+published hashes, its archived versions and live project metadata from Modrinth.
+
+Example: modrinth-mod-updater info sodium`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		showInfo(args[0])
+	},
+}
+
+func showInfo(slug string) {
+	cfg, client := bootstrapLocal(".")
+
+	var mod db.Mod
+	if err := db.DB.Where("project_slug = ?", slug).First(&mod).Error; err != nil {
+		logger.Log.Fatalw("Mod not found in database", zap.String("slug", slug), zap.Error(err))
+	}
+	var archived []db.ModVersion
+	db.DB.Where("project_slug = ?", slug).Order("created_at DESC").Find(&archived)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	source := mod.Provider
+	if mod.Source != "" {
+		source += " (" + mod.Source + ")"
+	}
+	fmt.Fprintf(w, "Title:\t%s\n", mod.Title)
+	fmt.Fprintf(w, "Slug:\t%s\n", mod.ProjectSlug)
+	fmt.Fprintf(w, "Type:\t%s\n", mod.ProjectType)
+	fmt.Fprintf(w, "Source:\t%s\n", source)
+	fmt.Fprintf(w, "Version:\t%s (%s)\n", mod.VersionNumber, mod.VersionID)
+	fmt.Fprintf(w, "Pinned:\t%t\n", mod.Pinned)
+	fmt.Fprintf(w, "Disabled:\t%t\n", mod.Disabled)
+	fmt.Fprintf(w, "Side:\tclient %s, server %s\n", valueOr(mod.ClientSide, "unknown"), valueOr(mod.ServerSide, "unknown"))
+	if mod.JarModID != "" {
+		fmt.Fprintf(w, "Jar metadata:\t%s %s (%s), environment %s\n", mod.JarModID, valueOr(mod.JarVersion, "unknown"), mod.JarLoader, valueOr(mod.JarEnvironment, "unknown"))
+		fmt.Fprintf(w, "Jar depends:\t%s\n", valueOr(formatJarDependencies(mod.JarDepends), "-"))
+		if len(mod.JarBreaks) > 0 {
+			fmt.Fprintf(w, "Jar breaks:\t%s\n", formatJarDependencies(mod.JarBreaks))
+		}
+	}
+	fmt.Fprintf(w, "File:\t%s\n", installedFilePath(mod))
+	fmt.Fprintf(w, "File status:\t%s\n", fileStatus(client, &cfg, mod))
+
+	if isModrinthMod(mod) && mod.ProjectID != "" {
+		if project, err := client.GetProject(mod.ProjectID); err != nil {
+			logger.Log.Warnw("Failed to get project details", zap.String("slug", slug), zap.Error(err))
+		} else {
+			fmt.Fprintf(w, "Description:\t%s\n", project.Description)
+			fmt.Fprintf(w, "Downloads:\t%d\n", project.Downloads)
+			fmt.Fprintf(w, "Followers:\t%d\n", project.Followers)
+			fmt.Fprintf(w, "Last updated:\t%s\n", project.Updated)
+			fmt.Fprintf(w, "Page:\thttps://modrinth.com/%s/%s\n", project.ProjectType, project.Slug)
+			if project.SourceURL != "" {
+				fmt.Fprintf(w, "Source code:\t%s\n", project.SourceURL)
+			}
+		}
+	}
+	w.Flush()
+
+	fmt.Printf("\nArchived versions (%d):\n", len(archived))
+	for _, v := range archived {
+		location := v.ArchivePath
+		if location == "" {
+			location = "(deleted)"
+		}
+		fmt.Printf("  %s  %s  %s\n", v.CreatedAt.Format("2006-01-02"), valueOr(v.VersionNumber, v.VersionID), location)
+	}
+}
+
+// fileStatus describes whether a mod's file exists and matches the hashes published for its version.
+func fileStatus(client *modrinth.Client, cfg *config.Config, mod db.Mod) string {
+	path := installedFilePath(mod)
+	if _, err := os.Stat(path); err != nil {
+		return "missing"
+	}
+	if !isModrinthMod(mod) || mod.VersionID == "" {
+		return "present (hash not checked)"
+	}
+	version, err := client.GetVersion(mod.VersionID)
+	if err != nil {
+		return "present (version lookup failed)"
+	}
+	file := versionFileByName(*version, mod.FileName)
+	if file == nil {
+		return "present (no published file)"
+	}
+	if err := verifyFileHashes(path, file.Hashes); err != nil {
+		logger.Log.Debugw("Hash check failed", zap.String("path", modpackPath(cfg, mod)), zap.Error(err))
+		return "present, hash mismatch"
+	}
+	return "present, hash ok"
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// formatJarDependencies lists dependencies declared in a jar as "id range | range".
+func formatJarDependencies(deps []jarmeta.Dependency) string {
+	parts := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		if len(dep.Versions) == 0 {
+			parts = append(parts, dep.ID)
+			continue
+		}
+		parts = append(parts, dep.ID+" "+strings.Join(dep.Versions, " | "))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"modrinth-mod-updater/db"
+)
+
+func TestListFilterMatches(t *testing.T) {
+	dependencies := map[string]bool{"P7dR8mSH": true}
+	mods := []db.Mod{
+		{ProjectSlug: "fabric-api", ProjectID: "P7dR8mSH", ProjectType: "mod", ClientSide: "required", ServerSide: "required"},
+		{ProjectSlug: "sodium", ProjectID: "AANobbMI", ProjectType: "mod", ClientSide: "required", ServerSide: "unsupported", Pinned: true},
+		{ProjectSlug: "mymod", ProjectType: "mod", Provider: "github", ClientSide: "", ServerSide: "", Disabled: true},
+		{ProjectSlug: "complementary", ProjectID: "HVnmMxH1", ProjectType: "shader", ClientSide: "required", ServerSide: "unsupported"},
+	}
+
+	tests := []struct {
+		name   string
+		filter listFilter
+		want   []string
+	}{
+		{"no filter", listFilter{}, []string{"fabric-api", "sodium", "mymod", "complementary"}},
+		{"type", listFilter{ProjectType: "shader"}, []string{"complementary"}},
+		{"server side", listFilter{Side: "server"}, []string{"fabric-api"}},
+		{"pinned", listFilter{Pinned: "yes"}, []string{"sodium"}},
+		{"unpinned", listFilter{Pinned: "false"}, []string{"fabric-api", "mymod", "complementary"}},
+		{"source", listFilter{Source: "github"}, []string{"mymod"}},
+		{"disabled", listFilter{Disabled: "yes"}, []string{"mymod"}},
+		{"enabled", listFilter{Disabled: "no", ProjectType: "mod"}, []string{"fabric-api", "sodium"}},
+		{"dependency", listFilter{Kind: "dependency"}, []string{"fabric-api"}},
+		{"explicit", listFilter{Kind: "explicit", ProjectType: "mod"}, []string{"sodium", "mymod"}},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, mod := range mods {
+			if tt.filter.matches(mod, newListEntry(mod, dependencies)) {
+				got = append(got, mod.ProjectSlug)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: matched %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewListEntryUnknownKind(t *testing.T) {
+	if entry := newListEntry(db.Mod{ProjectID: "x"}, nil); entry.Kind != "unknown" || entry.Source != "modrinth" {
+		t.Errorf("newListEntry() = %+v, want unknown kind from modrinth", entry)
+	}
+}
+
+func TestWriteList(t *testing.T) {
+	entries := []listEntry{{Slug: "sodium", Title: "Sodium | Fast", ProjectType: "mod", Version: "0.6.0", Source: "modrinth", Side: "client", Kind: "explicit", FileName: "sodium.jar"}}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"table", "sodium  Sodium | Fast"},
+		{"csv", "sodium,Sodium | Fast,mod,0.6.0,modrinth,client,false,explicit,sodium.jar"},
+		{"markdown", `| sodium | Sodium \| Fast | mod |`},
+		{"json", `"slug": "sodium"`},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := writeList(&buf, entries, tt.format); err != nil {
+			t.Errorf("writeList(%q) error = %v", tt.format, err)
+			continue
+		}
+		if !strings.Contains(buf.String(), tt.want) {
+			t.Errorf("writeList(%q) = %q, want it to contain %q", tt.format, buf.String(), tt.want)
+		}
+	}
+
+	if err := writeList(&bytes.Buffer{}, entries, "xml"); err == nil {
+		t.Error("writeList(xml) expected error")
+	}
+}
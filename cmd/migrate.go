@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/logger"
+	"modrinth-mod-updater/modrinth"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// migrationSnapshot records the mod set before a Minecraft version migration, so it can be undone.
+// The installed files are copied next to it, into files/<slug>/<file name>.
+type migrationSnapshot struct {
+	CreatedAt   time.Time `json:"created_at"`
+	FromVersion string    `json:"from_version"`
+	ToVersion   string    `json:"to_version"`
+	Mods        []db.Mod  `json:"mods"`
+}
+
+const migrationSnapshotFile = "snapshot.json"
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate --to <version> | --undo",
+	Short: "Move every mod to another Minecraft version, with undo",
+	Long: `Switch the instance to another Minecraft version in one step.
+
+The installed files and their database records are first copied to a snapshot in the migrations
+directory. Then every project with a build for the target version is updated to it, and
+MINECRAFT_VERSION is rewritten in .env. Projects without a compatible build keep their current file,
+or are disabled like with the disable command when --disable-incompatible is given.
+
+migrate --undo restores the files, database records and MINECRAFT_VERSION of the latest snapshot.
+
+Example: modrinth-mod-updater migrate --to 1.21.5 --disable-incompatible
+Example: modrinth-mod-updater migrate --undo`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		to, _ := cmd.Flags().GetString("to")
+		undo, _ := cmd.Flags().GetBool("undo")
+		disable, _ := cmd.Flags().GetBool("disable-incompatible")
+
+		switch {
+		case undo:
+			undoMigration()
+		case to != "":
+			migrateTo(to, disable)
+		default:
+			_ = cmd.Usage()
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+
+	migrateCmd.Flags().String("to", "", "Minecraft version to migrate to")
+	migrateCmd.Flags().Bool("undo", false, "Restore the state before the latest migration")
+	migrateCmd.Flags().Bool("disable-incompatible", false, "Disable mods without a build for the target version")
+	migrateCmd.MarkFlagsMutuallyExclusive("to", "undo")
+}
+
+func migrateTo(version string, disableIncompatible bool) {
+	cfg, client := bootstrap(".")
+	if version == cfg.MinecraftVersion {
+		logger.Log.Fatalw("Already on this Minecraft version", zap.String("version", version))
+	}
+	known, err := client.GetGameVersions()
+	if err != nil {
+		logger.Log.Fatalw("Failed to get Minecraft versions", zap.Error(err))
+	}
+	if err := requireKnownGameVersion(version, known); err != nil {
+		logger.Log.Fatalw("Cannot migrate", zap.Error(err))
+	}
+
+	target := upgradeConfig(&cfg, version, "")
+	fmt.Printf("Checking projects for Minecraft %s...\n", version)
+	entries := checkUpgradeReadiness(&target, client)
+
+	dir, snapshot, err := createMigrationSnapshot(&cfg, version)
+	if err != nil {
+		logger.Log.Fatalw("Failed to snapshot installed mods", zap.Error(err))
+	}
+	fmt.Printf("Saved snapshot of %d mods to %s\n", len(snapshot.Mods), dir)
+
+	installMigrationVersions(&target, client, entries)
+
+	incompatible := installedIncompatible(entries)
+	for _, mod := range incompatible {
+		if !disableIncompatible {
+			fmt.Printf("  ! %s %s has no build for %s, keeping it\n", mod.Title, mod.VersionNumber, version)
+			continue
+		}
+		if err := setModDisabled(&cfg, &mod, true, logger.Log.With(zap.String("project_slug", mod.ProjectSlug))); err != nil {
+			logger.Log.Warnw("Failed to disable mod", zap.String("path", mod.InstallPath), zap.Error(err))
+			fmt.Printf("  ✗ %s: failed to disable: %v\n", mod.Title, err)
+			continue
+		}
+		fmt.Printf("  - %s disabled, no build for %s\n", mod.Title, version)
+	}
+
+	setConfiguredVersion(version)
+	fmt.Printf("Migrated to Minecraft %s. Run 'migrate --undo' to go back to %s.\n", version, cfg.MinecraftVersion)
+}
+
+// requireKnownGameVersion fails when Modrinth does not know the target version, e.g. because of a typo,
+// which would otherwise make every project look incompatible.
+func requireKnownGameVersion(version string, known []modrinth.GameVersion) error {
+	if slices.ContainsFunc(known, func(v modrinth.GameVersion) bool { return v.Version == version }) {
+		return nil
+	}
+	return fmt.Errorf("minecraft version %s is unknown to Modrinth", version)
+}
+
+// installMigrationVersions installs the compatible build of every project that is ready for the target version.
+func installMigrationVersions(target *config.Config, client *modrinth.Client, entries []upgradeEntry) {
+	providers := providersByName(newProviders(target, client))
+
+	var downloadedCount, updatedCount atomic.Int64
+	var wg sync.WaitGroup
+	for _, e := range entries {
+		if e.Status != upgradeReady {
+			continue
+		}
+		wg.Add(1)
+		go func(e upgradeEntry) {
+			defer wg.Done()
+			if e.project != nil {
+				processProject(*e.project, target, client, false, printProgress, &downloadedCount, &updatedCount)
+			} else if p, ok := providers[e.Provider]; ok {
+				processProviderMod(*e.sourceMod, p, target, false, printProgress, &downloadedCount, &updatedCount)
+			}
+		}(e)
+	}
+	wg.Wait()
+	fmt.Printf("Installed %d new projects, updated %d.\n", downloadedCount.Load(), updatedCount.Load())
+}
+
+// installedIncompatible returns the installed mods without a usable build for the target version, including
+// mods whose required dependencies have none.
+func installedIncompatible(entries []upgradeEntry) []db.Mod {
+	var mods []db.Mod
+	for _, e := range entries {
+		if e.Status != upgradeMissing && e.Status != upgradeBlocked {
+			continue
+		}
+		var mod db.Mod
+		if err := db.DB.Where("project_slug = ?", e.Slug).First(&mod).Error; err != nil || mod.InstallPath == "" || mod.Disabled {
+			continue
+		}
+		if _, err := os.Stat(mod.InstallPath); err == nil {
+			mods = append(mods, mod)
+		}
+	}
+	return mods
+}
+
+// setConfiguredVersion rewrites MINECRAFT_VERSION in .env, or tells the user to change it when it comes
+// from the environment.
+func setConfiguredVersion(version string) {
+	if os.Getenv("MINECRAFT_VERSION") != "" {
+		fmt.Printf("  ! MINECRAFT_VERSION is set in the environment, change it to %s there\n", version)
+	}
+	if err := config.SetEnvValue(".", "MINECRAFT_VERSION", version); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			fmt.Printf("  ! No .env file found, set MINECRAFT_VERSION=%s yourself\n", version)
+			return
+		}
+		logger.Log.Fatalw("Failed to update .env", zap.Error(err))
+	}
+}
+
+func migrationsDir(cfg *config.Config) string {
+	return filepath.Join(cfg.MinecraftDir, "migrations")
+}
+
+// createMigrationSnapshot copies every installed file and all mod records into a new snapshot directory.
+func createMigrationSnapshot(cfg *config.Config, to string) (string, *migrationSnapshot, error) {
+	snapshot := &migrationSnapshot{CreatedAt: time.Now(), FromVersion: cfg.MinecraftVersion, ToVersion: to}
+	if err := db.DB.Find(&snapshot.Mods).Error; err != nil {
+		return "", nil, fmt.Errorf("failed to load mods: %w", err)
+	}
+
+	dir := filepath.Join(migrationsDir(cfg), snapshot.CreatedAt.Format("20060102-150405")+"-"+cfg.MinecraftVersion+"-to-"+to)
+	for _, mod := range snapshot.Mods {
+		if mod.InstallPath == "" {
+			continue
+		}
+		path := installedFilePath(mod)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err := copyFile(path, filepath.Join(dir, "files", mod.ProjectSlug, mod.FileName)); err != nil {
+			return "", nil, fmt.Errorf("failed to copy %s: %w", path, err)
+		}
+	}
+	return dir, snapshot, saveMigrationSnapshot(dir, snapshot)
+}
+
+func saveMigrationSnapshot(dir string, snapshot *migrationSnapshot) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, migrationSnapshotFile), data, 0644)
+}
+
+// latestMigrationSnapshot returns the directory and contents of the newest snapshot.
+func latestMigrationSnapshot(cfg *config.Config) (string, *migrationSnapshot, error) {
+	entries, err := os.ReadDir(migrationsDir(cfg))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", nil, err
+	}
+	var dirs []string
+	for _, e := range entries {
+		if e.IsDir() {
+			dirs = append(dirs, e.Name())
+		}
+	}
+	// Snapshot directories start with their creation time, so the newest sorts last.
+	sort.Strings(dirs)
+	for i := len(dirs) - 1; i >= 0; i-- {
+		dir := filepath.Join(migrationsDir(cfg), dirs[i])
+		data, err := os.ReadFile(filepath.Join(dir, migrationSnapshotFile))
+		if err != nil {
+			continue
+		}
+		var snapshot migrationSnapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return "", nil, fmt.Errorf("failed to read snapshot %s: %w", dir, err)
+		}
+		return dir, &snapshot, nil
+	}
+	return "", nil, errors.New("no migration snapshot found")
+}
+
+func undoMigration() {
+	cfg, _ := bootstrapLocal(".")
+
+	dir, snapshot, err := latestMigrationSnapshot(&cfg)
+	if err != nil {
+		logger.Log.Fatalw("Failed to find migration snapshot", zap.Error(err))
+	}
+	if err := restoreMigrationSnapshot(&cfg, dir, snapshot); err != nil {
+		logger.Log.Fatalw("Failed to restore migration snapshot", zap.String("snapshot", dir), zap.Error(err))
+	}
+	setConfiguredVersion(snapshot.FromVersion)
+
+	if err := os.RemoveAll(dir); err != nil {
+		logger.Log.Warnw("Failed to remove migration snapshot", zap.String("snapshot", dir), zap.Error(err))
+	}
+	fmt.Printf("Restored %d mods for Minecraft %s.\n", len(snapshot.Mods), snapshot.FromVersion)
+}
+
+// restoreMigrationSnapshot replaces the installed files and mod records with those of the snapshot.
+func restoreMigrationSnapshot(cfg *config.Config, dir string, snapshot *migrationSnapshot) error {
+	log := logger.Log.With(zap.String("snapshot", dir))
+
+	var current []db.Mod
+	if err := db.DB.Find(&current).Error; err != nil {
+		return fmt.Errorf("failed to load mods: %w", err)
+	}
+	for _, mod := range current {
+		if mod.FileName == "" {
+			continue
+		}
+		if err := os.Remove(installedFilePath(mod)); err != nil && !os.IsNotExist(err) {
+			log.Warnw("Failed to remove file", zap.String("path", installedFilePath(mod)), zap.Error(err))
+		}
+		if mod.ProjectType == "datapack" {
+			removeDatapackCopies(cfg, mod, log)
+		}
+	}
+
+	ids := make([]uint, 0, len(snapshot.Mods))
+	for _, mod := range snapshot.Mods {
+		ids = append(ids, mod.ID)
+		source := filepath.Join(dir, "files", mod.ProjectSlug, mod.FileName)
+		if _, err := os.Stat(source); err != nil {
+			continue
+		}
+		if err := copyFile(source, installedFilePath(mod)); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", installedFilePath(mod), err)
+		}
+		if mod.ProjectType == "datapack" && !mod.Disabled {
+			syncDatapackCopies(cfg, mod.InstallPath, "", log)
+		}
+	}
+
+	if err := db.DB.Unscoped().Where("id NOT IN ?", append(ids, 0)).Delete(&db.Mod{}).Error; err != nil {
+		return fmt.Errorf("failed to delete records added by the migration: %w", err)
+	}
+	for i := range snapshot.Mods {
+		if err := db.DB.Save(&snapshot.Mods[i]).Error; err != nil {
+			return fmt.Errorf("failed to restore record for %s: %w", snapshot.Mods[i].ProjectSlug, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/modrinth"
+)
+
+func TestLatestMigrationSnapshot(t *testing.T) {
+	cfg := &config.Config{MinecraftDir: t.TempDir()}
+
+	if _, _, err := latestMigrationSnapshot(cfg); err == nil {
+		t.Error("latestMigrationSnapshot() without snapshots should fail")
+	}
+
+	older := filepath.Join(migrationsDir(cfg), "20260101-120000-1.21.3-to-1.21.4")
+	newer := filepath.Join(migrationsDir(cfg), "20260301-120000-1.21.4-to-1.21.5")
+	if err := saveMigrationSnapshot(older, &migrationSnapshot{FromVersion: "1.21.3", ToVersion: "1.21.4"}); err != nil {
+		t.Fatalf("saveMigrationSnapshot() error = %v", err)
+	}
+	snapshot := &migrationSnapshot{
+		FromVersion: "1.21.4",
+		ToVersion:   "1.21.5",
+		Mods:        []db.Mod{{ProjectSlug: "sodium", FileName: "sodium-0.6.0.jar"}, {ProjectSlug: "lithium", Disabled: true}},
+	}
+	if err := saveMigrationSnapshot(newer, snapshot); err != nil {
+		t.Fatalf("saveMigrationSnapshot() error = %v", err)
+	}
+	// Directories without a snapshot, e.g. from an interrupted migration, are skipped.
+	if err := os.MkdirAll(filepath.Join(migrationsDir(cfg), "20260401-120000-1.21.5-to-1.21.6"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, got, err := latestMigrationSnapshot(cfg)
+	if err != nil {
+		t.Fatalf("latestMigrationSnapshot() error = %v", err)
+	}
+	if dir != newer {
+		t.Errorf("dir = %s, want %s", dir, newer)
+	}
+	if got.FromVersion != "1.21.4" || len(got.Mods) != 2 || got.Mods[0].ProjectSlug != "sodium" || !got.Mods[1].Disabled {
+		t.Errorf("snapshot = %+v", got)
+	}
+}
+
+func TestRequireKnownGameVersion(t *testing.T) {
+	known := []modrinth.GameVersion{{Version: "1.21.5"}, {Version: "1.21.4"}}
+	if err := requireKnownGameVersion("1.21.5", known); err != nil {
+		t.Errorf("requireKnownGameVersion(1.21.5) error = %v", err)
+	}
+	if err := requireKnownGameVersion("1.21.55", known); err == nil {
+		t.Error("Expected error for an unknown version")
+	}
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/logger"
+	"modrinth-mod-updater/modrinth"
+	"modrinth-mod-updater/mrpack"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// mrpackCmd groups the Modrinth modpack commands
+var mrpackCmd = &cobra.Command{
+	Use:   "mrpack",
+	Short: "Work with Modrinth modpacks (.mrpack)",
+	Long:  `Install Modrinth modpacks (.mrpack files) into the configured Minecraft instance, or export the tracked mods as one.`,
+}
+
+// mrpackInstallCmd represents the mrpack install command
+var mrpackInstallCmd = &cobra.Command{
+	Use:   "install <file|project>",
+	Short: "Install a Modrinth modpack into the instance",
+	Long: `Install a Modrinth modpack from a local .mrpack file or a Modrinth project slug/ID.
+
+Every file listed in modrinth.index.json is downloaded into the instance with hash
+verification, honoring each file's client/server environment and the configured
+MINECRAFT_INSTALLATION_TYPE. The overrides/ directory and the client-overrides/ or
+server-overrides/ directory are then applied, and every installed file is recorded in
+the database so later update runs manage it.
+
+Example: modrinth-mod-updater mrpack install ./my-pack.mrpack
+Example: modrinth-mod-updater mrpack install fabulously-optimized`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		skipOptional, _ := cmd.Flags().GetBool("skip-optional")
+		installModpack(args[0], skipOptional)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mrpackCmd)
+	mrpackCmd.AddCommand(mrpackInstallCmd)
+
+	mrpackInstallCmd.Flags().Bool("skip-optional", false, "Skip files the pack marks as optional for this installation type")
+}
+
+// installModpack installs the pack at source, which is either a local file or a Modrinth project.
+func installModpack(source string, skipOptional bool) {
+	cfg, client := bootstrap(".")
+
+	packPath, cleanup, err := resolveModpackSource(client, &cfg, source)
+	if err != nil {
+		logger.Log.Fatalw("Failed to resolve modpack", zap.String("source", source), zap.Error(err))
+	}
+	defer cleanup()
+
+	pack, err := mrpack.Open(packPath)
+	if err != nil {
+		logger.Log.Fatalw("Failed to open modpack", zap.String("path", packPath), zap.Error(err))
+	}
+	defer pack.Close()
+
+	log := logger.Log.With(zap.String("modpack", pack.Index.Name))
+	warnModpackDependencies(pack.Index, &cfg, log)
+
+	installed, failed := 0, 0
+	for _, file := range pack.Index.Files {
+		if !file.SupportedOn(cfg.MinecraftInstallationType) || (skipOptional && file.Optional(cfg.MinecraftInstallationType)) {
+			log.Infow("Skipping modpack file for this installation type", zap.String("path", file.Path))
+			continue
+		}
+		if err := installModpackFile(client, &cfg, file, log); err != nil {
+			log.Errorw("Failed to install modpack file", zap.String("path", file.Path), zap.Error(err))
+			fmt.Printf("  ✗ %s: %v\n", file.Path, err)
+			failed++
+			continue
+		}
+		fmt.Printf("  ✓ %s\n", file.Path)
+		installed++
+	}
+
+	overrides, err := pack.ExtractOverrides(cfg.MinecraftDir, cfg.MinecraftInstallationType)
+	if err != nil {
+		log.Errorw("Failed to apply modpack overrides", zap.Error(err))
+	}
+
+	summary := fmt.Sprintf("Installed %s %s: %d files, %d overrides, %d failed", pack.Index.Name, pack.Index.VersionID, installed, len(overrides), failed)
+	log.Info(summary)
+	fmt.Println(summary)
+}
+
+// resolveModpackSource returns a local path to the .mrpack for source, downloading the newest
+// compatible version when source is a Modrinth project. The returned cleanup removes any temporary file.
+func resolveModpackSource(client *modrinth.Client, cfg *config.Config, source string) (string, func(), error) {
+	noop := func() {}
+	if info, err := os.Stat(source); err == nil && !info.IsDir() {
+		return source, noop, nil
+	}
+
+	project, err := client.GetProject(source)
+	if err != nil {
+		return "", noop, err
+	}
+	if project.ProjectType != "modpack" {
+		return "", noop, fmt.Errorf("project %s is a %s, not a modpack", project.Slug, project.ProjectType)
+	}
+
+	versions, err := client.GetProjectVersions(project.Slug, []string{cfg.MinecraftVersion}, []string{cfg.MinecraftLoader})
+	if err != nil {
+		return "", noop, err
+	}
+	if len(versions) == 0 {
+		return "", noop, fmt.Errorf("no version of %s for Minecraft %s (%s)", project.Slug, cfg.MinecraftVersion, cfg.MinecraftLoader)
+	}
+	primaryFile := findPrimaryFile(versions[0])
+	if primaryFile == nil {
+		return "", noop, fmt.Errorf("version %s has no files", versions[0].ID)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "mrpack-")
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(tmpDir) }
+
+	packPath := filepath.Join(tmpDir, primaryFile.Filename)
+	if err := client.DownloadModFile(logger.Log, packPath, primaryFile.URL); err != nil {
+		cleanup()
+		return "", noop, err
+	}
+	if err := verifyFileHashes(packPath, primaryFile.Hashes); err != nil {
+		cleanup()
+		return "", noop, err
+	}
+	return packPath, cleanup, nil
+}
+
+// warnModpackDependencies logs when the pack targets a different game or loader version than configured.
+func warnModpackDependencies(index mrpack.Index, cfg *config.Config, log *zap.SugaredLogger) {
+	if mc, ok := index.Dependencies["minecraft"]; ok && mc != cfg.MinecraftVersion {
+		log.Warnw("Modpack targets a different Minecraft version",
+			zap.String("modpack_version", mc),
+			zap.String("configured_version", cfg.MinecraftVersion),
+		)
+	}
+	if loader := index.Loader(); loader != "" && loader != cfg.MinecraftLoader {
+		log.Warnw("Modpack targets a different loader",
+			zap.String("modpack_loader", loader),
+			zap.String("configured_loader", cfg.MinecraftLoader),
+		)
+	}
+}
+
+// installModpackFile downloads a single pack file into the instance, verifies it and records it.
+func installModpackFile(client *modrinth.Client, cfg *config.Config, file mrpack.File, log *zap.SugaredLogger) error {
+	target := filepath.Join(cfg.MinecraftDir, filepath.FromSlash(file.Path))
+
+	var lastErr error
+	downloaded := false
+	for _, url := range file.Downloads {
+		if lastErr = client.DownloadModFile(log, target, url); lastErr != nil {
+			continue
+		}
+		if lastErr = verifyFileHashes(target, file.Hashes); lastErr != nil {
+			os.Remove(target)
+			continue
+		}
+		downloaded = true
+		break
+	}
+	if !downloaded {
+		if lastErr == nil {
+			lastErr = fmt.Errorf("no download URLs")
+		}
+		return lastErr
+	}
+
+	// Files that are not on Modrinth (e.g. hosted elsewhere) are installed but cannot be managed.
+	version, err := client.GetVersionByHash(file.Hashes["sha1"])
+	if err != nil {
+		log.Infow("Modpack file is not a Modrinth version, leaving it unmanaged", zap.String("path", file.Path))
+		return nil
+	}
+	project, err := client.GetProject(version.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project details: %w", err)
+	}
+	return recordPackVersion(cfg, client, project, version, target, log)
+}
@@ -0,0 +1,304 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/logger"
+	"modrinth-mod-updater/modrinth"
+	"modrinth-mod-updater/mrpack"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// versionsBatchSize limits the number of version IDs requested from /versions at once.
+const versionsBatchSize = 100
+
+// modpackMeta holds the user-provided details of an exported pack.
+type modpackMeta struct {
+	Name          string
+	Version       string
+	Summary       string
+	LoaderVersion string
+}
+
+// mrpackExportCmd represents the mrpack export command
+var mrpackExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the tracked mods as a Modrinth modpack",
+	Long: `Export every tracked mod, shader and resource pack as a Modrinth modpack (.mrpack).
+
+The pack's modrinth.index.json lists each file with its hashes, size and download URL,
+and marks client- or server-only projects through the per-file env field. The configured
+Minecraft version and loader are added as dependencies. Directories passed with --include
+are bundled as overrides.
+
+Example: modrinth-mod-updater mrpack export --name "My Server" --version 1.2.0 --include config`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		meta := modpackMeta{}
+		meta.Name, _ = cmd.Flags().GetString("name")
+		meta.Version, _ = cmd.Flags().GetString("version")
+		meta.Summary, _ = cmd.Flags().GetString("summary")
+		meta.LoaderVersion, _ = cmd.Flags().GetString("loader-version")
+		output, _ := cmd.Flags().GetString("output")
+		include, _ := cmd.Flags().GetStringSlice("include")
+		includeClient, _ := cmd.Flags().GetStringSlice("include-client")
+		includeServer, _ := cmd.Flags().GetStringSlice("include-server")
+
+		overrides := map[string][]string{
+			mrpack.OverridesDir:       include,
+			mrpack.ClientOverridesDir: includeClient,
+			mrpack.ServerOverridesDir: includeServer,
+		}
+		exportModpack(meta, output, overrides)
+	},
+}
+
+func init() {
+	mrpackCmd.AddCommand(mrpackExportCmd)
+
+	mrpackExportCmd.Flags().StringP("output", "o", "", "Output file (default: <name>-<version>.mrpack)")
+	mrpackExportCmd.Flags().String("name", "Modpack", "Name of the modpack")
+	mrpackExportCmd.Flags().String("version", "1.0.0", "Version of the modpack")
+	mrpackExportCmd.Flags().String("summary", "", "Short description of the modpack")
+	mrpackExportCmd.Flags().String("loader-version", "", "Loader version dependency (default: LOADER_VERSION)")
+	mrpackExportCmd.Flags().StringSlice("include", nil, "Instance directories to bundle as overrides (e.g. config)")
+	mrpackExportCmd.Flags().StringSlice("include-client", nil, "Instance directories to bundle as client-only overrides")
+	mrpackExportCmd.Flags().StringSlice("include-server", nil, "Instance directories to bundle as server-only overrides")
+}
+
+func exportModpack(meta modpackMeta, output string, overrideDirs map[string][]string) {
+	cfg, client := bootstrap(".")
+	if meta.LoaderVersion == "" {
+		meta.LoaderVersion = cfg.LoaderVersion
+	}
+	if err := requireLoaderVersion(&cfg, meta.LoaderVersion); err != nil {
+		logger.Log.Fatalw("Cannot export modpack", zap.Error(err))
+	}
+
+	var mods []db.Mod
+	if err := db.DB.Find(&mods).Error; err != nil {
+		logger.Log.Fatalw("Failed to load mods from database", zap.Error(err))
+	}
+
+	versions, err := fetchVersionsByID(client, mods)
+	if err != nil {
+		logger.Log.Fatalw("Failed to fetch version details", zap.Error(err))
+	}
+	fillMissingSides(client, mods)
+
+	index, skipped := buildModpackIndex(mods, versions, &cfg, meta)
+	printSkippedExports(skipped)
+
+	overrides, err := modpackOverrides(cfg.MinecraftDir, overrideDirs)
+	if err != nil {
+		logger.Log.Fatalw("Invalid override directory", zap.Error(err))
+	}
+
+	if output == "" {
+		output = fmt.Sprintf("%s-%s.mrpack", strings.ReplaceAll(meta.Name, " ", "-"), meta.Version)
+	}
+	out, err := os.Create(output)
+	if err != nil {
+		logger.Log.Fatalw("Failed to create modpack file", zap.String("path", output), zap.Error(err))
+	}
+	defer out.Close()
+
+	if err := mrpack.Write(out, index, overrides); err != nil {
+		logger.Log.Fatalw("Failed to write modpack", zap.String("path", output), zap.Error(err))
+	}
+
+	summary := fmt.Sprintf("Exported %d files to %s", len(index.Files), output)
+	logger.Log.Info(summary)
+	fmt.Println(summary)
+}
+
+// fetchVersionsByID loads the Modrinth versions of all given mods, keyed by version ID.
+func fetchVersionsByID(client *modrinth.Client, mods []db.Mod) (map[string]modrinth.Version, error) {
+	var ids []string
+	for _, mod := range mods {
+		if mod.VersionID != "" && isModrinthMod(mod) {
+			ids = append(ids, mod.VersionID)
+		}
+	}
+
+	versions := make(map[string]modrinth.Version, len(ids))
+	for start := 0; start < len(ids); start += versionsBatchSize {
+		end := min(start+versionsBatchSize, len(ids))
+		batch, err := client.GetVersions(ids[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range batch {
+			versions[v.ID] = v
+		}
+	}
+	return versions, nil
+}
+
+// fillMissingSides looks up client/server support for mods recorded before it was stored.
+func fillMissingSides(client *modrinth.Client, mods []db.Mod) {
+	for i := range mods {
+		if !isModrinthMod(mods[i]) || (mods[i].ClientSide != "" && mods[i].ServerSide != "") {
+			continue
+		}
+		project, err := client.GetProject(mods[i].ProjectSlug)
+		if err != nil {
+			logger.Log.Warnw("Failed to get project sides", zap.String("slug", mods[i].ProjectSlug), zap.Error(err))
+			continue
+		}
+		mods[i].ClientSide = project.ClientSide
+		mods[i].ServerSide = project.ServerSide
+		if err := db.DB.Model(&mods[i]).Updates(db.Mod{ClientSide: project.ClientSide, ServerSide: project.ServerSide}).Error; err != nil {
+			logger.Log.Warnw("Failed to store project sides", zap.String("slug", mods[i].ProjectSlug), zap.Error(err))
+		}
+	}
+}
+
+// requireLoaderVersion fails when the configured loader is a pack dependency but no loader version is
+// given, since launchers would treat a pack without it as vanilla.
+func requireLoaderVersion(cfg *config.Config, loaderVersion string) error {
+	if mrpack.LoaderDependency(cfg.MinecraftLoader) != "" && loaderVersion == "" {
+		return fmt.Errorf("no version for loader %s, set --loader-version or LOADER_VERSION", cfg.MinecraftLoader)
+	}
+	return nil
+}
+
+// Reasons for leaving a tracked mod out of an export.
+const (
+	skipDisabled  = "disabled"
+	skipNoFile    = "no downloadable Modrinth file"
+	skipOtherFile = "installed file is not a file of its Modrinth version"
+)
+
+// skippedExport is a tracked mod left out of an export.
+type skippedExport struct {
+	Slug   string
+	Reason string // One of the skip* reasons
+}
+
+func printSkippedExports(skipped []skippedExport) {
+	for _, s := range skipped {
+		logger.Log.Warnw("Skipping mod in export", zap.String("slug", s.Slug), zap.String("reason", s.Reason))
+		fmt.Printf("  ! skipped %s (%s)\n", s.Slug, s.Reason)
+	}
+}
+
+// exportedFile returns the Modrinth file an export describes for a tracked mod, or why the mod is left
+// out. Disabled mods are left out, since packs have no way to ship a mod disabled.
+func exportedFile(mod db.Mod, versions map[string]modrinth.Version) (*modrinth.File, string) {
+	if mod.Disabled {
+		return nil, skipDisabled
+	}
+	version, ok := versions[mod.VersionID]
+	if !ok {
+		return nil, skipNoFile
+	}
+	file := installedVersionFile(version, mod)
+	if file == nil {
+		return nil, skipOtherFile
+	}
+	return file, ""
+}
+
+// buildModpackIndex creates the modpack index for the tracked mods. It returns the mods that could not be
+// included, e.g. because they are disabled or their version or file is unknown to Modrinth.
+func buildModpackIndex(mods []db.Mod, versions map[string]modrinth.Version, cfg *config.Config, meta modpackMeta) (mrpack.Index, []skippedExport) {
+	index := mrpack.Index{
+		FormatVersion: 1,
+		Game:          "minecraft",
+		VersionID:     meta.Version,
+		Name:          meta.Name,
+		Summary:       meta.Summary,
+		Files:         []mrpack.File{},
+		Dependencies:  map[string]string{"minecraft": cfg.MinecraftVersion},
+	}
+	if key := mrpack.LoaderDependency(cfg.MinecraftLoader); key != "" && meta.LoaderVersion != "" {
+		index.Dependencies[key] = meta.LoaderVersion
+	}
+
+	var skipped []skippedExport
+	for _, mod := range mods {
+		file, reason := exportedFile(mod, versions)
+		if file == nil {
+			skipped = append(skipped, skippedExport{mod.ProjectSlug, reason})
+			continue
+		}
+
+		index.Files = append(index.Files, mrpack.File{
+			Path:      modpackPath(cfg, mod),
+			Hashes:    map[string]string{"sha1": file.Hashes["sha1"], "sha512": file.Hashes["sha512"]},
+			Env:       &mrpack.Env{Client: sideToEnv(mod.ClientSide), Server: sideToEnv(mod.ServerSide)},
+			Downloads: []string{file.URL},
+			FileSize:  int64(file.Size),
+		})
+	}
+	return index, skipped
+}
+
+// installedVersionFile returns the file of a version that is installed for mod: the file with the
+// installed name, or with the hash recorded when it was installed. It returns nil when the installed file
+// is none of the version's files, so exports never describe another file than the one on disk.
+func installedVersionFile(v modrinth.Version, mod db.Mod) *modrinth.File {
+	for i := range v.Files {
+		f := &v.Files[i]
+		if f.Filename == mod.FileName || (mod.FileHash != "" && strings.EqualFold(f.Hashes["sha1"], mod.FileHash)) {
+			return f
+		}
+	}
+	return nil
+}
+
+// versionFileByName returns the file of a version matching the installed file name,
+// falling back to the primary file.
+func versionFileByName(v modrinth.Version, fileName string) *modrinth.File {
+	for i := range v.Files {
+		if v.Files[i].Filename == fileName {
+			return &v.Files[i]
+		}
+	}
+	return findPrimaryFile(v)
+}
+
+// modpackPath returns the instance-relative, slash-separated path of an installed mod.
+func modpackPath(cfg *config.Config, mod db.Mod) string {
+	if rel, err := filepath.Rel(cfg.MinecraftDir, mod.InstallPath); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(filepath.Join(getTargetSubDir(mod.ProjectType), mod.FileName))
+}
+
+// sideToEnv converts Modrinth's client_side/server_side values to a modpack env value.
+func sideToEnv(side string) string {
+	switch side {
+	case "optional", "unsupported":
+		return side
+	default:
+		return "required"
+	}
+}
+
+// modpackOverrides converts instance-relative directories into pack overrides.
+func modpackOverrides(minecraftDir string, dirs map[string][]string) ([]mrpack.Override, error) {
+	var overrides []mrpack.Override
+	for _, packDir := range []string{mrpack.OverridesDir, mrpack.ClientOverridesDir, mrpack.ServerOverridesDir} {
+		for _, dir := range dirs[packDir] {
+			target := filepath.ToSlash(filepath.Clean(dir))
+			if err := mrpack.ValidatePath(target); err != nil {
+				return nil, err
+			}
+			source := filepath.Join(minecraftDir, filepath.FromSlash(target))
+			if info, err := os.Stat(source); err != nil || !info.IsDir() {
+				return nil, fmt.Errorf("override directory %s does not exist", source)
+			}
+			overrides = append(overrides, mrpack.Override{Source: source, Target: target, Dir: packDir})
+		}
+	}
+	return overrides, nil
+}
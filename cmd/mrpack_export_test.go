@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/modrinth"
+)
+
+func TestBuildModpackIndex(t *testing.T) {
+	cfg := config.Config{MinecraftDir: "/srv/mc", MinecraftVersion: "1.21.4", MinecraftLoader: "fabric"}
+	mods := []db.Mod{
+		{
+			ProjectSlug: "sodium",
+			VersionID:   "v1",
+			FileName:    "sodium.jar",
+			InstallPath: filepath.Join("/srv/mc", "mods", "sodium.jar"),
+			ClientSide:  "required",
+			ServerSide:  "unsupported",
+		},
+		{ProjectSlug: "unknown", VersionID: "missing", FileName: "unknown.jar"},
+		{ProjectSlug: "lithium", VersionID: "v2", FileName: "lithium.jar", Disabled: true},
+		// The installed file was renamed, but its recorded hash identifies it.
+		{ProjectSlug: "iris", VersionID: "v3", FileName: "iris-renamed.jar", FileHash: "C", InstallPath: filepath.Join("/srv/mc", "mods", "iris-renamed.jar")},
+		{ProjectSlug: "other", VersionID: "v4", FileName: "other-custom.jar", FileHash: "x"},
+	}
+	versions := map[string]modrinth.Version{
+		"v1": {ID: "v1", Files: []modrinth.File{
+			{Filename: "sodium-sources.jar", URL: "https://cdn/sources.jar", Primary: true},
+			{Filename: "sodium.jar", URL: "https://cdn/sodium.jar", Size: 42, Hashes: map[string]string{"sha1": "a", "sha512": "b"}},
+		}},
+		"v2": {ID: "v2", Files: []modrinth.File{{Filename: "lithium.jar", URL: "https://cdn/lithium.jar", Primary: true}}},
+		"v3": {ID: "v3", Files: []modrinth.File{{Filename: "iris.jar", URL: "https://cdn/iris.jar", Primary: true, Hashes: map[string]string{"sha1": "c"}}}},
+		"v4": {ID: "v4", Files: []modrinth.File{{Filename: "other.jar", URL: "https://cdn/other.jar", Primary: true, Hashes: map[string]string{"sha1": "d"}}}},
+	}
+
+	index, skipped := buildModpackIndex(mods, versions, &cfg, modpackMeta{Name: "Pack", Version: "1.0.0", LoaderVersion: "0.16.9"})
+
+	expectedSkipped := []skippedExport{{"unknown", skipNoFile}, {"lithium", skipDisabled}, {"other", skipOtherFile}}
+	if !slices.Equal(skipped, expectedSkipped) {
+		t.Errorf("Expected skipped %v, got %v", expectedSkipped, skipped)
+	}
+	if index.Dependencies["minecraft"] != "1.21.4" || index.Dependencies["fabric-loader"] != "0.16.9" {
+		t.Errorf("Unexpected dependencies: %v", index.Dependencies)
+	}
+	if len(index.Files) != 2 {
+		t.Fatalf("Expected 2 files, got %d", len(index.Files))
+	}
+	if index.Files[1].Downloads[0] != "https://cdn/iris.jar" {
+		t.Errorf("Expected the renamed file to be matched by hash, got %+v", index.Files[1])
+	}
+
+	file := index.Files[0]
+	if file.Path != "mods/sodium.jar" || file.Downloads[0] != "https://cdn/sodium.jar" || file.FileSize != 42 {
+		t.Errorf("Unexpected file entry: %+v", file)
+	}
+	if file.Env.Client != "required" || file.Env.Server != "unsupported" {
+		t.Errorf("Unexpected env: %+v", file.Env)
+	}
+}
+
+func TestSideToEnv(t *testing.T) {
+	tests := map[string]string{
+		"required":    "required",
+		"optional":    "optional",
+		"unsupported": "unsupported",
+		"unknown":     "required",
+		"":            "required",
+	}
+	for side, expected := range tests {
+		if result := sideToEnv(side); result != expected {
+			t.Errorf("sideToEnv(%q) = %q, want %q", side, result, expected)
+		}
+	}
+}
+
+func TestRequireLoaderVersion(t *testing.T) {
+	tests := []struct {
+		loader, version string
+		wantErr         bool
+	}{
+		{"fabric", "0.16.9", false},
+		{"fabric", "", true},
+		{"neoforge", "", true},
+		// Plugin platforms are not pack dependencies.
+		{"paper", "", false},
+	}
+	for _, tt := range tests {
+		cfg := config.Config{MinecraftLoader: tt.loader}
+		if err := requireLoaderVersion(&cfg, tt.version); (err != nil) != tt.wantErr {
+			t.Errorf("requireLoaderVersion(%s, %q) error = %v, wantErr %v", tt.loader, tt.version, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/logger"
+	"modrinth-mod-updater/modrinth"
+	"modrinth-mod-updater/provider"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// outdatedEntry is a project with a pending update.
+type outdatedEntry struct {
+	Slug             string    `json:"slug"`
+	Title            string    `json:"title"`
+	Provider         string    `json:"provider"`
+	InstalledVersion string    `json:"installed_version,omitempty"` // Empty when the project is not installed yet
+	LatestVersion    string    `json:"latest_version"`
+	LatestVersionID  string    `json:"latest_version_id"`
+	Published        time.Time `json:"published,omitzero"`
+	Channel          string    `json:"channel"` // release, beta or alpha
+}
+
+// outdatedFailure is a project that could not be checked.
+type outdatedFailure struct {
+	Slug  string
+	Title string
+	Err   error
+}
+
+// Exit statuses of outdated, besides 0 when everything is up to date.
+const (
+	outdatedExitUpdates = 1 // Updates are available
+	outdatedExitFailed  = 2 // Some projects could not be checked, whether or not updates are available
+)
+
+// outdatedCmd represents the outdated command
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "List projects with available updates without installing them",
+	Long: `Check every followed project and tracked source like update does, and print the installed version,
+the latest compatible version, its publish date and release channel for each project with a pending update.
+
+Exits with status 1 when updates are available and with status 2 when some projects could not be
+checked, e.g. because an API is down, so it can drive monitoring checks.
+
+Example: modrinth-mod-updater outdated
+Example: modrinth-mod-updater outdated --json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		entries, failures := listOutdated()
+		hasUpdates := reportOutdated(entries, asJSON, len(failures) > 0)
+		reportOutdatedFailures(failures)
+		if code := outdatedExitCode(hasUpdates, failures); code != 0 {
+			os.Exit(code)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(outdatedCmd)
+
+	outdatedCmd.Flags().Bool("json", false, "Print the pending updates as JSON")
+}
+
+// listOutdated plans the update of every followed project and tracked source and returns those with pending
+// updates, and those that could not be checked.
+func listOutdated() ([]outdatedEntry, []outdatedFailure) {
+	cfg, client := bootstrap(".")
+
+	projects, err := followedProjects(&cfg, client)
+	if err != nil {
+		logger.Log.Errorw("Failed to get followed projects", zap.Error(err))
+		return nil, []outdatedFailure{{Title: "followed projects", Err: err}}
+	}
+	var sourceMods []db.Mod
+	if err := db.DB.Where("provider <> ?", provider.ModrinthName).Find(&sourceMods).Error; err != nil {
+		logger.Log.Warnw("Failed to load mods from other providers", zap.Error(err))
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		entries  []outdatedEntry
+		failures []outdatedFailure
+	)
+	add := func(e outdatedEntry) {
+		mu.Lock()
+		defer mu.Unlock()
+		entries = append(entries, e)
+	}
+	fail := func(slug, title string, err error) {
+		logger.Log.Warnw("Failed to check project", zap.String("project_slug", slug), zap.Error(err))
+		mu.Lock()
+		defer mu.Unlock()
+		failures = append(failures, outdatedFailure{Slug: slug, Title: title, Err: err})
+	}
+
+	for _, project := range projects {
+		if !isSupportedProjectType(project.ProjectType) {
+			continue
+		}
+		wg.Add(1)
+		go func(p modrinth.Project) {
+			defer wg.Done()
+			log := logger.Log.With(zap.String("project_slug", p.Slug))
+			plan, err := planProjectUpdate(p, &cfg, client, log)
+			if err != nil {
+				fail(p.Slug, p.Title, err)
+				return
+			}
+			if plan == nil {
+				return
+			}
+			plan.selection().discard()
+			if plan.UpToDate() {
+				return
+			}
+			add(projectOutdatedEntry(plan))
+		}(project)
+	}
+
+	providers := providersByName(newProviders(&cfg, client))
+	for _, mod := range sourceMods {
+		p, ok := providers[mod.Provider]
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(m db.Mod) {
+			defer wg.Done()
+			plan, err := planProviderUpdate(m, p, &cfg)
+			if err != nil {
+				fail(m.ProjectSlug, m.Title, err)
+				return
+			}
+			if plan == nil || m.Pinned || plan.Version.ID == m.VersionID {
+				return
+			}
+			add(outdatedEntry{
+				Slug: m.ProjectSlug, Title: m.Title, Provider: m.Provider,
+				InstalledVersion: m.VersionNumber, LatestVersion: plan.Version.VersionNumber, LatestVersionID: plan.Version.ID,
+				Published: plan.Version.Published, Channel: plan.Version.VersionType,
+			})
+		}(mod)
+	}
+
+	wg.Wait()
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Title < entries[j].Title })
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Title < failures[j].Title })
+	return entries, failures
+}
+
+func projectOutdatedEntry(plan *projectPlan) outdatedEntry {
+	published, _ := time.Parse(time.RFC3339Nano, plan.Version.DatePublished)
+	entry := outdatedEntry{
+		Slug:            plan.Project.Slug,
+		Title:           plan.Project.Title,
+		Provider:        provider.ModrinthName,
+		LatestVersion:   plan.Version.VersionNumber,
+		LatestVersionID: plan.Version.ID,
+		Published:       published,
+		Channel:         plan.Version.VersionType,
+	}
+	if plan.Existing != nil {
+		entry.InstalledVersion = plan.Existing.VersionNumber
+	}
+	return entry
+}
+
+// reportOutdated prints the pending updates and reports whether there are any. incomplete is set when some
+// projects could not be checked.
+func reportOutdated(entries []outdatedEntry, asJSON, incomplete bool) bool {
+	if asJSON {
+		if entries == nil {
+			entries = []outdatedEntry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			logger.Log.Fatalw("Failed to encode pending updates", zap.Error(err))
+		}
+		return len(entries) > 0
+	}
+
+	if len(entries) == 0 {
+		if incomplete {
+			fmt.Println("No updates found for the projects that could be checked.")
+		} else {
+			fmt.Println("All projects are up to date.")
+		}
+		return false
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROJECT\tINSTALLED\tLATEST\tPUBLISHED\tCHANNEL")
+	for _, e := range entries {
+		installed := e.InstalledVersion
+		if installed == "" {
+			installed = "(not installed)"
+		}
+		published := "-"
+		if !e.Published.IsZero() {
+			published = e.Published.Format(time.DateOnly)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.Title, installed, e.LatestVersion, published, e.Channel)
+	}
+	w.Flush()
+	fmt.Printf("\n%d updates available.\n", len(entries))
+	return true
+}
+
+// reportOutdatedFailures prints the projects that could not be checked to stderr, keeping the JSON
+// output on stdout intact.
+func reportOutdatedFailures(failures []outdatedFailure) {
+	if len(failures) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\n%d projects could not be checked:\n", len(failures))
+	for _, f := range failures {
+		fmt.Fprintf(os.Stderr, "  ✗ %s: %v\n", f.Title, f.Err)
+	}
+}
+
+// outdatedExitCode returns the exit status of outdated. Failures take precedence, since updates of the
+// projects that could not be checked are unknown.
+func outdatedExitCode(hasUpdates bool, failures []outdatedFailure) int {
+	switch {
+	case len(failures) > 0:
+		return outdatedExitFailed
+	case hasUpdates:
+		return outdatedExitUpdates
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/modrinth"
+)
+
+func TestProjectPlanUpToDate(t *testing.T) {
+	version := modrinth.Version{ID: "v2"}
+	tests := []struct {
+		name     string
+		existing *db.Mod
+		want     bool
+	}{
+		{"not installed", nil, false},
+		{"older version installed", &db.Mod{VersionID: "v1"}, false},
+		{"latest installed", &db.Mod{VersionID: "v2"}, true},
+	}
+
+	for _, tt := range tests {
+		plan := &projectPlan{Version: version, Existing: tt.existing}
+		if got := plan.UpToDate(); got != tt.want {
+			t.Errorf("%s: UpToDate() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProjectOutdatedEntry(t *testing.T) {
+	plan := &projectPlan{
+		Project:  modrinth.Project{Slug: "sodium", Title: "Sodium"},
+		Version:  modrinth.Version{ID: "v2", VersionNumber: "0.6.0", VersionType: "beta", DatePublished: "2024-08-01T10:00:00.123Z"},
+		Existing: &db.Mod{VersionNumber: "0.5.0"},
+	}
+
+	entry := projectOutdatedEntry(plan)
+	if entry.Slug != "sodium" || entry.Provider != "modrinth" || entry.InstalledVersion != "0.5.0" || entry.LatestVersion != "0.6.0" || entry.Channel != "beta" {
+		t.Errorf("projectOutdatedEntry() = %+v", entry)
+	}
+	if want := time.Date(2024, 8, 1, 10, 0, 0, 123000000, time.UTC); !entry.Published.Equal(want) {
+		t.Errorf("Published = %v, want %v", entry.Published, want)
+	}
+
+	plan.Existing = nil
+	if entry := projectOutdatedEntry(plan); entry.InstalledVersion != "" {
+		t.Errorf("InstalledVersion for new project = %q, want empty", entry.InstalledVersion)
+	}
+}
+
+func TestOutdatedExitCode(t *testing.T) {
+	failures := []outdatedFailure{{Slug: "sodium", Title: "Sodium", Err: errors.New("service unavailable")}}
+	tests := []struct {
+		name       string
+		hasUpdates bool
+		failures   []outdatedFailure
+		want       int
+	}{
+		{"up to date", false, nil, 0},
+		{"updates", true, nil, outdatedExitUpdates},
+		{"failures", false, failures, outdatedExitFailed},
+		{"updates and failures", true, failures, outdatedExitFailed},
+	}
+
+	for _, tt := range tests {
+		if got := outdatedExitCode(tt.hasUpdates, tt.failures); got != tt.want {
+			t.Errorf("%s: outdatedExitCode() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
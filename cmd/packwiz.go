@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/logger"
+	"modrinth-mod-updater/modrinth"
+	"modrinth-mod-updater/mrpack"
+	"modrinth-mod-updater/packwiz"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// packwizCmd groups the packwiz interoperability commands
+var packwizCmd = &cobra.Command{
+	Use:   "packwiz",
+	Short: "Import and export packwiz pack trees",
+	Long:  `Exchange the tracked mod set with packwiz-managed repositories (pack.toml, index.toml and .pw.toml files).`,
+}
+
+// packwizExportCmd represents the packwiz export command
+var packwizExportCmd = &cobra.Command{
+	Use:   "export <dir>",
+	Short: "Export the tracked mods as a packwiz pack tree",
+	Long: `Write a packwiz pack.toml, index.toml and one .pw.toml metafile per tracked mod into <dir>.
+Each metafile carries the download URL, hash and Modrinth update metadata of the installed version.
+
+Example: modrinth-mod-updater packwiz export ./pack --name "My Server"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		author, _ := cmd.Flags().GetString("author")
+		version, _ := cmd.Flags().GetString("version")
+		loaderVersion, _ := cmd.Flags().GetString("loader-version")
+		exportPackwiz(args[0], packwiz.Pack{Name: name, Author: author, Version: version}, loaderVersion)
+	},
+}
+
+// packwizImportCmd represents the packwiz import command
+var packwizImportCmd = &cobra.Command{
+	Use:   "import <dir>",
+	Short: "Install a packwiz pack tree into the instance",
+	Long: `Read pack.toml from <dir>, download every mod listed in its index with hash verification,
+copy the remaining pack files into the instance and record Modrinth-hosted mods in the database.
+
+Example: modrinth-mod-updater packwiz import ./pack`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		importPackwiz(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(packwizCmd)
+	packwizCmd.AddCommand(packwizExportCmd)
+	packwizCmd.AddCommand(packwizImportCmd)
+
+	packwizExportCmd.Flags().String("name", "Modpack", "Name of the pack")
+	packwizExportCmd.Flags().String("author", "", "Author of the pack")
+	packwizExportCmd.Flags().String("version", "1.0.0", "Version of the pack")
+	packwizExportCmd.Flags().String("loader-version", "", "Loader version (default: LOADER_VERSION)")
+}
+
+func exportPackwiz(dir string, pack packwiz.Pack, loaderVersion string) {
+	cfg, client := bootstrap(".")
+	if loaderVersion == "" {
+		loaderVersion = cfg.LoaderVersion
+	}
+	if err := requireLoaderVersion(&cfg, loaderVersion); err != nil {
+		logger.Log.Fatalw("Cannot export packwiz pack", zap.Error(err))
+	}
+
+	var mods []db.Mod
+	if err := db.DB.Find(&mods).Error; err != nil {
+		logger.Log.Fatalw("Failed to load mods from database", zap.Error(err))
+	}
+	versions, err := fetchVersionsByID(client, mods)
+	if err != nil {
+		logger.Log.Fatalw("Failed to fetch version details", zap.Error(err))
+	}
+	fillMissingSides(client, mods)
+
+	metafiles, skipped := buildPackwizMods(mods, versions, &cfg)
+	printSkippedExports(skipped)
+
+	pack.Versions = map[string]string{"minecraft": cfg.MinecraftVersion}
+	if mrpack.LoaderDependency(cfg.MinecraftLoader) != "" {
+		pack.Versions[cfg.MinecraftLoader] = loaderVersion
+	}
+
+	if err := packwiz.Write(dir, pack, metafiles); err != nil {
+		logger.Log.Fatalw("Failed to write packwiz pack", zap.String("dir", dir), zap.Error(err))
+	}
+
+	summary := fmt.Sprintf("Exported %d mods to %s", len(metafiles), filepath.Join(dir, packwiz.PackFileName))
+	logger.Log.Info(summary)
+	fmt.Println(summary)
+}
+
+// buildPackwizMods creates a metafile for every tracked mod, keyed by its path in the pack.
+// It skips mods the same way as buildModpackIndex, and mods whose file has no sha512 hash.
+func buildPackwizMods(mods []db.Mod, versions map[string]modrinth.Version, cfg *config.Config) (map[string]packwiz.Mod, []skippedExport) {
+	metafiles := make(map[string]packwiz.Mod, len(mods))
+	var skipped []skippedExport
+	for _, mod := range mods {
+		file, reason := exportedFile(mod, versions)
+		if file != nil && file.Hashes["sha512"] == "" {
+			file, reason = nil, skipNoFile
+		}
+		if file == nil {
+			skipped = append(skipped, skippedExport{mod.ProjectSlug, reason})
+			continue
+		}
+
+		metaPath := path.Join(path.Dir(modpackPath(cfg, mod)), mod.ProjectSlug+packwiz.MetafileExt)
+		metafiles[metaPath] = packwiz.Mod{
+			Name:     mod.Title,
+			FileName: file.Filename,
+			Side:     packwiz.SideFromSupport(mod.ClientSide, mod.ServerSide),
+			Download: packwiz.Download{URL: file.URL, HashFormat: "sha512", Hash: file.Hashes["sha512"]},
+			Update:   &packwiz.Update{Modrinth: &packwiz.ModrinthUpdate{ModID: mod.ProjectID, Version: mod.VersionID}},
+		}
+	}
+	return metafiles, skipped
+}
+
+func importPackwiz(dir string) {
+	cfg, client := bootstrap(".")
+
+	pack, err := packwiz.ReadPack(dir)
+	if err != nil {
+		logger.Log.Fatalw("Failed to read packwiz pack", zap.String("dir", dir), zap.Error(err))
+	}
+	index, err := packwiz.ReadIndex(dir, pack)
+	if err != nil {
+		logger.Log.Fatalw("Failed to read packwiz index", zap.String("dir", dir), zap.Error(err))
+	}
+	if mc := pack.Versions["minecraft"]; mc != "" && mc != cfg.MinecraftVersion {
+		logger.Log.Warnw("Pack targets a different Minecraft version",
+			zap.String("pack_version", mc),
+			zap.String("configured_version", cfg.MinecraftVersion),
+		)
+	}
+
+	// Index paths are relative to the index file, not to pack.toml.
+	indexDir := filepath.Join(dir, filepath.Dir(filepath.FromSlash(pack.Index.File)))
+	log := logger.Log.With(zap.String("pack", pack.Name))
+
+	installed, failed := 0, 0
+	for _, entry := range index.Files {
+		hashFormat := entry.HashFormat
+		if hashFormat == "" {
+			hashFormat = index.HashFormat
+		}
+		source := filepath.Join(indexDir, filepath.FromSlash(entry.File))
+		if err := packwiz.VerifyFile(source, hashFormat, entry.Hash); err != nil {
+			log.Errorw("Pack file failed hash verification", zap.String("file", entry.File), zap.Error(err))
+			failed++
+			continue
+		}
+
+		copied := true
+		if entry.Metafile {
+			err = installPackwizMod(client, &cfg, source, path.Dir(entry.File), log)
+		} else {
+			copied, err = installPackFile(source, filepath.Join(cfg.MinecraftDir, filepath.FromSlash(entry.File)), entry.Preserve)
+		}
+		if err != nil {
+			log.Errorw("Failed to install pack file", zap.String("file", entry.File), zap.Error(err))
+			fmt.Printf("  ✗ %s: %v\n", entry.File, err)
+			failed++
+			continue
+		}
+		if !copied {
+			fmt.Printf("  - %s: kept, the pack preserves local changes\n", entry.File)
+			continue
+		}
+		fmt.Printf("  ✓ %s\n", entry.File)
+		installed++
+	}
+
+	summary := fmt.Sprintf("Imported %s: %d files, %d failed", pack.Name, installed, failed)
+	log.Info(summary)
+	fmt.Println(summary)
+}
+
+// installPackwizMod downloads the file described by a metafile into targetDir (relative to the instance)
+// and records it when it carries Modrinth update metadata.
+func installPackwizMod(client *modrinth.Client, cfg *config.Config, metaPath, targetDir string, log *zap.SugaredLogger) error {
+	mod, err := packwiz.ReadMod(metaPath)
+	if err != nil {
+		return err
+	}
+	if !mod.SupportedOn(cfg.MinecraftInstallationType) {
+		log.Infow("Skipping pack mod for this installation type", zap.String("name", mod.Name), zap.String("side", mod.Side))
+		return nil
+	}
+	if mod.Download.URL == "" {
+		return fmt.Errorf("%s has no download URL (mode %q is not supported)", mod.Name, mod.Download.Mode)
+	}
+
+	target := filepath.Join(cfg.MinecraftDir, filepath.FromSlash(targetDir), mod.FileName)
+	if err := client.DownloadModFile(log, target, mod.Download.URL); err != nil {
+		return err
+	}
+	if err := packwiz.VerifyFile(target, mod.Download.HashFormat, mod.Download.Hash); err != nil {
+		os.Remove(target)
+		return err
+	}
+
+	if mod.Update == nil || mod.Update.Modrinth == nil {
+		log.Infow("Pack mod has no Modrinth metadata, leaving it unmanaged", zap.String("name", mod.Name))
+		return nil
+	}
+	version, err := client.GetVersion(mod.Update.Modrinth.Version)
+	if err != nil {
+		return err
+	}
+	project, err := client.GetProject(mod.Update.Modrinth.ModID)
+	if err != nil {
+		return err
+	}
+	return recordPackVersion(cfg, client, project, version, target, log)
+}
+
+// installPackFile copies a non-metafile pack file (e.g. a config) into the instance. Files the pack marks
+// as preserved are meant to be edited by users, so an existing copy is kept. It reports whether the file
+// was copied.
+func installPackFile(source, target string, preserve bool) (bool, error) {
+	if preserve {
+		if _, err := os.Stat(target); err == nil {
+			return false, nil
+		}
+	}
+	return true, copyFile(source, target)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/modrinth"
+)
+
+func TestBuildPackwizMods(t *testing.T) {
+	cfg := config.Config{MinecraftDir: "/srv/mc"}
+	mods := []db.Mod{
+		{
+			ProjectSlug: "sodium",
+			ProjectID:   "AANobbMI",
+			Title:       "Sodium",
+			VersionID:   "v1",
+			FileName:    "sodium.jar",
+			InstallPath: filepath.Join("/srv/mc", "mods", "sodium.jar"),
+			ClientSide:  "required",
+			ServerSide:  "unsupported",
+		},
+		{ProjectSlug: "no-hash", VersionID: "v2", FileName: "x.jar"},
+		{ProjectSlug: "lithium", VersionID: "v3", FileName: "lithium.jar", Disabled: true},
+		{ProjectSlug: "other", VersionID: "v3", FileName: "lithium-custom.jar"},
+	}
+	versions := map[string]modrinth.Version{
+		"v1": {ID: "v1", Files: []modrinth.File{{Filename: "sodium.jar", URL: "https://cdn/sodium.jar", Hashes: map[string]string{"sha512": "abc"}}}},
+		"v2": {ID: "v2", Files: []modrinth.File{{Filename: "x.jar", URL: "https://cdn/x.jar"}}},
+		"v3": {ID: "v3", Files: []modrinth.File{{Filename: "lithium.jar", URL: "https://cdn/lithium.jar", Hashes: map[string]string{"sha512": "def"}}}},
+	}
+
+	metafiles, skipped := buildPackwizMods(mods, versions, &cfg)
+
+	expectedSkipped := []skippedExport{{"no-hash", skipNoFile}, {"lithium", skipDisabled}, {"other", skipOtherFile}}
+	if !slices.Equal(skipped, expectedSkipped) {
+		t.Errorf("Expected skipped %v, got %v", expectedSkipped, skipped)
+	}
+	if len(metafiles) != 1 {
+		t.Errorf("Expected 1 metafile, got %v", metafiles)
+	}
+	mod, ok := metafiles["mods/sodium.pw.toml"]
+	if !ok {
+		t.Fatalf("Expected mods/sodium.pw.toml, got %v", metafiles)
+	}
+	if mod.Side != "client" || mod.Download.Hash != "abc" || mod.Update.Modrinth.ModID != "AANobbMI" || mod.Update.Modrinth.Version != "v1" {
+		t.Errorf("Unexpected metafile: %+v", mod)
+	}
+}
+
+func TestInstallPackFile(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "pack", "options.txt")
+	if err := os.MkdirAll(filepath.Dir(source), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(source, []byte("pack"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		existing string
+		preserve bool
+		want     string
+	}{
+		{"new file", "", false, "pack"},
+		{"new preserved file", "", true, "pack"},
+		{"overwritten", "local", false, "pack"},
+		{"preserved", "local", true, "local"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := filepath.Join(t.TempDir(), "config", "options.txt")
+			if tt.existing != "" {
+				if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(target, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			copied, err := installPackFile(source, target, tt.preserve)
+			if err != nil {
+				t.Fatalf("installPackFile() error = %v", err)
+			}
+			if got, _ := os.ReadFile(target); string(got) != tt.want || copied != (tt.want == "pack") {
+				t.Errorf("installPackFile() = %v, content %q, want content %q", copied, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/jarmeta"
+	"modrinth-mod-updater/logger"
+	"modrinth-mod-updater/modrinth"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// Kinds of orphans found by prune.
+const (
+	orphanUnfollowed = "unfollowed" // Tracked Modrinth project that is no longer followed
+	orphanUntracked  = "untracked"  // File in a managed directory that is not in the database
+	orphanStale      = "stale"      // Database record whose file is gone
+)
+
+// Actions prune can apply to orphans.
+const (
+	pruneRemove  = "remove"
+	pruneArchive = "archive"
+	pruneAdopt   = "adopt"
+)
+
+// localProvider marks files adopted by prune that no provider knows. They are kept as they are.
+const localProvider = "local"
+
+// orphan is a file or database record that update no longer manages.
+type orphan struct {
+	Kind string
+	Path string
+	Mod  *db.Mod // Database record, nil for untracked files
+}
+
+func (o orphan) label() string {
+	name := filepath.Base(o.Path)
+	if o.Mod != nil {
+		name = o.Mod.Title
+	}
+	return fmt.Sprintf("%-10s %s", o.Kind, name)
+}
+
+// pruneCmd represents the prune command
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Find and clean up files and records update no longer manages",
+	Long: `Find installed projects that are no longer followed, files in the managed directories that
+are not tracked in the database, and database records whose file is gone.
+
+Without --action the findings are only listed. With --action, the selected ones are handled
+after confirmation in a checklist (skipped with --yes):
+
+  remove   delete the file and forget the record
+  archive  move the file to the versions directory and forget the record
+  adopt    follow the project again, track the unknown file as a local mod,
+           or download the recorded version of a missing file again
+
+Example: modrinth-mod-updater prune
+Example: modrinth-mod-updater prune --only untracked --action archive`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		action, _ := cmd.Flags().GetString("action")
+		only, _ := cmd.Flags().GetStringSlice("only")
+		assumeYes, _ := cmd.Flags().GetBool("yes")
+		prune(action, only, assumeYes)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+
+	pruneCmd.Flags().String("action", "", "Action to apply: remove, archive or adopt")
+	pruneCmd.Flags().StringSlice("only", nil, "Only handle these kinds: unfollowed, untracked, stale")
+	pruneCmd.Flags().BoolP("yes", "y", false, "Apply the action to everything found without confirmation")
+}
+
+func prune(action string, only []string, assumeYes bool) {
+	if action != "" && !slices.Contains([]string{pruneRemove, pruneArchive, pruneAdopt}, action) {
+		logger.Log.Fatalw("Invalid action, expected remove, archive or adopt", zap.String("action", action))
+	}
+	cfg, client := bootstrap(".")
+
+	followed, err := followedProjects(&cfg, client)
+	if err != nil {
+		logger.Log.Fatalw("Failed to get followed projects", zap.Error(err))
+	}
+	var mods []db.Mod
+	if err := db.DB.Find(&mods).Error; err != nil {
+		logger.Log.Fatalw("Failed to load mods from database", zap.Error(err))
+	}
+
+	orphans := slices.DeleteFunc(findOrphans(&cfg, mods, followed), func(o orphan) bool {
+		return len(only) > 0 && !slices.Contains(only, o.Kind)
+	})
+	if len(orphans) == 0 {
+		fmt.Println("Nothing to prune.")
+		return
+	}
+	if action == "" {
+		for _, o := range orphans {
+			fmt.Printf("  %s  %s\n", o.label(), o.Path)
+		}
+		fmt.Printf("Found %d files and records to prune. Use --action to handle them.\n", len(orphans))
+		return
+	}
+
+	items := make([]checklistItem, len(orphans))
+	for i, o := range orphans {
+		items[i] = checklistItem{Label: o.label(), Checked: true}
+	}
+	selected, err := confirmChecklist("Select what to "+action, items, assumeYes)
+	if err != nil {
+		logger.Log.Fatalw("Failed to run confirmation UI", zap.Error(err))
+	}
+
+	applied := 0
+	for _, i := range selected {
+		if err := applyPruneAction(&cfg, client, orphans[i], action); err != nil {
+			logger.Log.Errorw("Failed to prune", zap.String("item", items[i].Label), zap.Error(err))
+			fmt.Printf("  ✗ %s: %v\n", items[i].Label, err)
+			continue
+		}
+		fmt.Printf("  ✓ %s\n", items[i].Label)
+		applied++
+	}
+	fmt.Printf("Applied %s to %d of %d.\n", action, applied, len(orphans))
+}
+
+// findOrphans classifies the tracked mods and the files in the managed directories.
+func findOrphans(cfg *config.Config, mods []db.Mod, followed []modrinth.Project) []orphan {
+	var orphans []orphan
+	for i := range mods {
+		mod := &mods[i]
+		if mod.InstallPath == "" {
+			continue
+		}
+		if _, err := os.Stat(installedFilePath(*mod)); errors.Is(err, os.ErrNotExist) {
+			orphans = append(orphans, orphan{Kind: orphanStale, Path: mod.InstallPath, Mod: mod})
+		} else if isUnfollowed(*mod, followed) {
+			orphans = append(orphans, orphan{Kind: orphanUnfollowed, Path: mod.InstallPath, Mod: mod})
+		}
+	}
+
+	tracked := trackedPaths(cfg, mods)
+	for _, dir := range managedDirs(cfg) {
+		for _, path := range managedFiles(dir) {
+			if !tracked[path] {
+				orphans = append(orphans, orphan{Kind: orphanUntracked, Path: path})
+			}
+		}
+	}
+	return orphans
+}
+
+// trackedPaths returns the paths of the tracked mods' files on disk, including the copies of datapacks in
+// other worlds. Disabled mods are tracked under their .disabled path and have no datapack copies.
+func trackedPaths(cfg *config.Config, mods []db.Mod) map[string]bool {
+	tracked := make(map[string]bool)
+	for _, mod := range mods {
+		if mod.InstallPath == "" {
+			continue
+		}
+		tracked[installedFilePath(mod)] = true
+		if mod.ProjectType == "datapack" && !mod.Disabled {
+			for _, dir := range cfg.DatapackDirs() {
+				tracked[filepath.Join(dir, mod.FileName)] = true
+			}
+		}
+	}
+	return tracked
+}
+
+// isUnfollowed reports whether a tracked Modrinth mod's project is no longer followed. Pinned mods were
+// installed deliberately and never count as unfollowed.
+func isUnfollowed(mod db.Mod, followed []modrinth.Project) bool {
+	if !isModrinthMod(mod) || mod.ProjectID == "" || mod.Pinned {
+		return false
+	}
+	return !slices.ContainsFunc(followed, func(p modrinth.Project) bool { return p.ID == mod.ProjectID })
+}
+
+// managedFiles returns the jar and zip files in a managed directory the import scan would look at, including
+// disabled ones with the .disabled suffix.
+func managedFiles(dir string) []string {
+	var files []string
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != dir && (info.Name() == "versions" || filepath.Base(dir) == "plugins") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := strings.TrimSuffix(path, disabledSuffix)
+		if ext := strings.ToLower(filepath.Ext(name)); ext == ".jar" || ext == ".zip" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files
+}
+
+func applyPruneAction(cfg *config.Config, client *modrinth.Client, o orphan, action string) error {
+	log := logger.Log.With(zap.String("kind", o.Kind), zap.String("path", o.Path))
+	switch {
+	case action == pruneAdopt:
+		return adoptOrphan(cfg, client, o, log)
+	case o.Kind == orphanStale && action == pruneArchive:
+		return errors.New("the file is gone, nothing to archive")
+	case o.Kind == orphanStale:
+		return db.DB.Unscoped().Delete(o.Mod).Error
+	case o.Mod == nil:
+		return pruneUntrackedFile(o.Path, action == pruneArchive)
+	default:
+		return pruneMod(cfg, *o.Mod, action == pruneArchive, log)
+	}
+}
+
+// pruneMod removes or archives a tracked mod's file and deletes its record.
+func pruneMod(cfg *config.Config, mod db.Mod, archive bool, log *zap.SugaredLogger) error {
+	archiveCfg := *cfg
+	archiveCfg.KeepOldVersions = archive
+	archiveAndCleanupOld(mod, filepath.Dir(mod.InstallPath), &archiveCfg, log)
+	if mod.ProjectType == "datapack" {
+		removeDatapackCopies(cfg, mod, log)
+	}
+	return db.DB.Unscoped().Delete(&mod).Error
+}
+
+func pruneUntrackedFile(path string, archive bool) error {
+	if !archive {
+		return os.Remove(path)
+	}
+	versionsDir := filepath.Join(filepath.Dir(path), "versions")
+	if err := os.MkdirAll(versionsDir, 0755); err != nil {
+		return err
+	}
+	target := filepath.Join(versionsDir, filepath.Base(path))
+	if _, err := os.Stat(target); err == nil {
+		return fmt.Errorf("%s already exists", target)
+	}
+	return os.Rename(path, target)
+}
+
+func adoptOrphan(cfg *config.Config, client *modrinth.Client, o orphan, log *zap.SugaredLogger) error {
+	switch o.Kind {
+	case orphanUnfollowed:
+		return followProject(cfg, client, &modrinth.Project{ID: o.Mod.ProjectID, Slug: o.Mod.ProjectSlug})
+	case orphanStale:
+		return downloadRecordedVersion(cfg, client, o.Mod, log)
+	}
+
+	mod := localMod(o.Path, cfg.MinecraftLoader)
+	return db.DB.Create(&mod).Error
+}
+
+// localMod describes a file no provider knows as a local mod. Jars with loader metadata are named after
+// the mod they declare, other files after their file name.
+func localMod(path, loader string) db.Mod {
+	installPath, disabled := strings.CutSuffix(path, disabledSuffix)
+	name := filepath.Base(installPath)
+	mod := db.Mod{
+		ProjectSlug: localProvider + "-" + strings.TrimSuffix(name, filepath.Ext(name)),
+		Provider:    localProvider,
+		Title:       name,
+		ProjectType: projectTypeForPath(installPath, "mod"),
+		FileName:    name,
+		InstallPath: installPath,
+		Pinned:      true,
+		Disabled:    disabled,
+	}
+	meta := applyFileMetadata(&mod, loader)
+	if meta == nil || meta.ID == "" {
+		return mod
+	}
+	mod.ProjectSlug = localProvider + "-" + meta.ID
+	mod.Title = valueOr(meta.Name, meta.ID)
+	mod.VersionNumber = meta.Version
+	mod.ClientSide, mod.ServerSide = environmentSides(meta.Environment)
+	return mod
+}
+
+// environmentSides converts the environment declared in a jar to Modrinth client and server support.
+func environmentSides(environment string) (client, server string) {
+	switch environment {
+	case jarmeta.EnvironmentClient:
+		return "required", "unsupported"
+	case jarmeta.EnvironmentServer:
+		return "unsupported", "required"
+	default:
+		return "required", "required"
+	}
+}
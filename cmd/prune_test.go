@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/modrinth"
+)
+
+func TestFindOrphans(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{MinecraftDir: dir, MinecraftLoader: "fabric", DatapackWorlds: []string{"world", "world_creative"}}
+	files := []string{
+		"mods/sodium.jar",
+		"mods/lithium.jar",
+		"mods/pinned.jar",
+		"mods/manual.jar",
+		"mods/manual-off.jar.disabled",
+		"mods/zoom.jar.disabled",
+		"mods/notes.txt",
+		"mods/versions/old-sodium.jar",
+		"world/datapacks/terralith.zip",
+		"world_creative/datapacks/terralith.zip",
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mods := []db.Mod{
+		{ProjectSlug: "sodium", ProjectID: "AANobbMI", Provider: "modrinth", FileName: "sodium.jar", InstallPath: filepath.Join(dir, "mods", "sodium.jar")},
+		{ProjectSlug: "lithium", ProjectID: "gvQqBUqZ", Provider: "modrinth", FileName: "lithium.jar", InstallPath: filepath.Join(dir, "mods", "lithium.jar")},
+		{ProjectSlug: "pinned", ProjectID: "pinnedID", Provider: "modrinth", Pinned: true, FileName: "pinned.jar", InstallPath: filepath.Join(dir, "mods", "pinned.jar")},
+		{ProjectSlug: "iris", ProjectID: "YL57xq9U", Provider: "modrinth", FileName: "iris.jar", InstallPath: filepath.Join(dir, "mods", "iris.jar")},
+		{ProjectSlug: "zoom", Provider: "github", Disabled: true, FileName: "zoom.jar", InstallPath: filepath.Join(dir, "mods", "zoom.jar")},
+		{ProjectSlug: "terralith", ProjectID: "8oi3bsk5", Provider: "modrinth", ProjectType: "datapack", FileName: "terralith.zip", InstallPath: filepath.Join(dir, "world", "datapacks", "terralith.zip")},
+	}
+	followed := []modrinth.Project{{ID: "AANobbMI"}, {ID: "YL57xq9U"}, {ID: "8oi3bsk5"}}
+
+	var got []string
+	for _, o := range findOrphans(cfg, mods, followed) {
+		rel, _ := filepath.Rel(dir, o.Path)
+		got = append(got, o.Kind+" "+filepath.ToSlash(rel))
+	}
+	want := []string{
+		"unfollowed mods/lithium.jar",
+		"stale mods/iris.jar",
+		"untracked mods/manual-off.jar.disabled",
+		"untracked mods/manual.jar",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("findOrphans() = %v, want %v", got, want)
+	}
+}
+
+func TestIsUnfollowed(t *testing.T) {
+	followed := []modrinth.Project{{ID: "AANobbMI"}}
+	tests := []struct {
+		name string
+		mod  db.Mod
+		want bool
+	}{
+		{"followed", db.Mod{Provider: "modrinth", ProjectID: "AANobbMI"}, false},
+		{"not followed", db.Mod{Provider: "modrinth", ProjectID: "gvQqBUqZ"}, true},
+		{"pinned", db.Mod{Provider: "modrinth", ProjectID: "gvQqBUqZ", Pinned: true}, false},
+		{"other provider", db.Mod{Provider: "github", ProjectID: "owner/repo"}, false},
+		{"local file", db.Mod{Provider: localProvider}, false},
+	}
+
+	for _, tt := range tests {
+		if got := isUnfollowed(tt.mod, followed); got != tt.want {
+			t.Errorf("isUnfollowed(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPruneUntrackedFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "manual.jar")
+	if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pruneUntrackedFile(path, true); err != nil {
+		t.Fatalf("pruneUntrackedFile(archive) error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "versions", "manual.jar")); err != nil {
+		t.Errorf("archived file missing: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("original file still exists")
+	}
+
+	if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := pruneUntrackedFile(path, true); err == nil {
+		t.Error("pruneUntrackedFile(archive) should not overwrite an archived file")
+	}
+	if err := pruneUntrackedFile(path, false); err != nil {
+		t.Fatalf("pruneUntrackedFile(remove) error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("removed file still exists")
+	}
+}
+
+func TestLocalMod(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mods")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	jar := filepath.Join(dir, "mymod-1.2.jar.disabled")
+	writeTestZip(t, jar, map[string]string{
+		"fabric.mod.json": `{"id": "mymod", "version": "1.2.0", "name": "My Mod", "environment": "client", "depends": {"fabricloader": ">=0.16"}}`,
+	})
+	plain := filepath.Join(dir, "notes.zip")
+	writeTestZip(t, plain, map[string]string{"readme.txt": "hello"})
+
+	mod := localMod(jar, "fabric")
+	if mod.ProjectSlug != "local-mymod" || mod.Title != "My Mod" || mod.VersionNumber != "1.2.0" || mod.JarLoader != "fabric" ||
+		mod.ClientSide != "required" || mod.ServerSide != "unsupported" || len(mod.JarDepends) != 1 {
+		t.Errorf("localMod(jar) = %+v", mod)
+	}
+	if !mod.Disabled || mod.FileName != "mymod-1.2.jar" || mod.ProjectType != "mod" {
+		t.Errorf("localMod(jar) should be a disabled mod named mymod-1.2.jar, got %+v", mod)
+	}
+
+	mod = localMod(plain, "fabric")
+	if mod.ProjectSlug != "local-notes" || mod.Title != "notes.zip" || mod.JarModID != "" {
+		t.Errorf("localMod(zip) = %+v", mod)
+	}
+}
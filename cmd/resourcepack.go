@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/modrinth"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+)
+
+// maxResourcePackCandidates limits how many versions are inspected when looking for a matching pack_format.
+const maxResourcePackCandidates = 5
+
+// packFormatRange is an inclusive range of resource pack formats.
+type packFormatRange struct {
+	Min int
+	Max int
+}
+
+func (r packFormatRange) overlaps(other packFormatRange) bool {
+	return r.Min <= other.Max && other.Min <= r.Max
+}
+
+// parsePackFormatRange parses a RESOURCEPACK_FORMAT value such as "34" or "32-34".
+func parsePackFormatRange(value string) (packFormatRange, error) {
+	lo, hi, isRange := strings.Cut(strings.TrimSpace(value), "-")
+	minFormat, err := strconv.Atoi(strings.TrimSpace(lo))
+	if err != nil {
+		return packFormatRange{}, fmt.Errorf("invalid resource pack format %q", value)
+	}
+	maxFormat := minFormat
+	if isRange {
+		if maxFormat, err = strconv.Atoi(strings.TrimSpace(hi)); err != nil {
+			return packFormatRange{}, fmt.Errorf("invalid resource pack format %q", value)
+		}
+	}
+	if minFormat > maxFormat {
+		minFormat, maxFormat = maxFormat, minFormat
+	}
+	return packFormatRange{Min: minFormat, Max: maxFormat}, nil
+}
+
+// packMeta mirrors the parts of pack.mcmeta that describe supported formats.
+type packMeta struct {
+	Pack struct {
+		PackFormat       int             `json:"pack_format"`
+		SupportedFormats json.RawMessage `json:"supported_formats"`
+		MinFormat        json.RawMessage `json:"min_format"`
+		MaxFormat        json.RawMessage `json:"max_format"`
+	} `json:"pack"`
+}
+
+// readPackFormats returns the range of formats a resource pack zip declares in its pack.mcmeta.
+func readPackFormats(path string) (packFormatRange, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return packFormatRange{}, fmt.Errorf("failed to open resource pack: %w", err)
+	}
+	defer r.Close()
+
+	f, err := r.Open("pack.mcmeta")
+	if err != nil {
+		return packFormatRange{}, fmt.Errorf("pack.mcmeta not found: %w", err)
+	}
+	defer f.Close()
+
+	var meta packMeta
+	if err := json.NewDecoder(f).Decode(&meta); err != nil {
+		return packFormatRange{}, fmt.Errorf("failed to parse pack.mcmeta: %w", err)
+	}
+
+	formats := packFormatRange{Min: meta.Pack.PackFormat, Max: meta.Pack.PackFormat}
+	if lo, ok := parseFormatValue(meta.Pack.MinFormat); ok {
+		formats.Min = lo
+		formats.Max = max(formats.Max, lo)
+	}
+	if hi, ok := parseFormatValue(meta.Pack.MaxFormat); ok {
+		formats.Max = hi
+	}
+	if supported, ok := parseSupportedFormats(meta.Pack.SupportedFormats); ok {
+		formats.Min = min(formats.Min, supported.Min)
+		formats.Max = max(formats.Max, supported.Max)
+	}
+	return formats, nil
+}
+
+// parseFormatValue accepts a format written as an integer or as a [major, minor] array.
+func parseFormatValue(raw json.RawMessage) (int, bool) {
+	if len(raw) == 0 {
+		return 0, false
+	}
+	var n int
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n, true
+	}
+	var parts []int
+	if err := json.Unmarshal(raw, &parts); err == nil && len(parts) > 0 {
+		return parts[0], true
+	}
+	return 0, false
+}
+
+// parseSupportedFormats accepts supported_formats as an integer, a [min, max] array or a
+// {"min_inclusive", "max_inclusive"} object.
+func parseSupportedFormats(raw json.RawMessage) (packFormatRange, bool) {
+	if n, ok := parseFormatValue(raw); ok {
+		var parts []int
+		if err := json.Unmarshal(raw, &parts); err == nil && len(parts) == 2 {
+			return packFormatRange{Min: parts[0], Max: parts[1]}, true
+		}
+		return packFormatRange{Min: n, Max: n}, true
+	}
+	var obj struct {
+		MinInclusive int `json:"min_inclusive"`
+		MaxInclusive int `json:"max_inclusive"`
+	}
+	if len(raw) > 0 && json.Unmarshal(raw, &obj) == nil {
+		return packFormatRange{Min: obj.MinInclusive, Max: obj.MaxInclusive}, true
+	}
+	return packFormatRange{}, false
+}
+
+// versionSelection is the version and file selectVersion picked.
+type versionSelection struct {
+	Version modrinth.Version
+	File    *modrinth.File
+	// Probed is a temporary copy of File downloaded to check its pack_format, empty when the file was not
+	// downloaded. installSelectedFile moves it into place, and discard removes it when it is not installed.
+	Probed string
+}
+
+// discard removes the probed copy of the selected file, if any.
+func (s versionSelection) discard() {
+	if s.Probed != "" {
+		os.RemoveAll(filepath.Dir(s.Probed))
+	}
+}
+
+// selectVersion picks the version and file to install from the compatible versions (newest first).
+// Resource packs are additionally checked against RESOURCEPACK_FORMAT, in which case the files of the
+// newest few versions are inspected until one declares a matching pack_format. The installed version, if
+// any, is accepted without a check, so only versions newer than it are inspected.
+func selectVersion(client *modrinth.Client, cfg *config.Config, projectType string, versions []modrinth.Version, installed *db.Mod, log *zap.SugaredLogger) (versionSelection, error) {
+	if len(versions) == 0 {
+		return versionSelection{}, fmt.Errorf("no compatible versions")
+	}
+	if projectType != "resourcepack" || cfg.ResourcePackFormat == "" {
+		return versionSelection{Version: versions[0], File: findPrimaryFile(versions[0])}, nil
+	}
+
+	wanted, err := parsePackFormatRange(cfg.ResourcePackFormat)
+	if err != nil {
+		return versionSelection{}, err
+	}
+
+	for i, v := range versions {
+		if i >= maxResourcePackCandidates {
+			break
+		}
+		if installed != nil && v.ID == installed.VersionID {
+			return versionSelection{Version: v, File: versionFileByName(v, installed.FileName)}, nil
+		}
+		for _, f := range orderedFiles(v) {
+			formats, probed, err := probePackFormats(client, v, f, log)
+			if err != nil {
+				log.Warnw("Failed to check resource pack format", zap.String("file", f.Filename), zap.Error(err))
+				continue
+			}
+			selection := versionSelection{Version: v, File: &f, Probed: probed}
+			if formats.overlaps(wanted) {
+				return selection, nil
+			}
+			selection.discard()
+			log.Infow("Skipping resource pack file with incompatible pack_format",
+				zap.String("file", f.Filename),
+				zap.Int("min_format", formats.Min),
+				zap.Int("max_format", formats.Max),
+			)
+		}
+	}
+
+	return versionSelection{}, fmt.Errorf("no version matches RESOURCEPACK_FORMAT %s", cfg.ResourcePackFormat)
+}
+
+// probePackFormats returns the pack formats a resource pack file declares, from the cache or by downloading
+// the file to a temporary directory. probed is the downloaded copy, empty when the cache was used.
+// Files without a readable pack.mcmeta are cached as format 0, which no RESOURCEPACK_FORMAT matches.
+func probePackFormats(client *modrinth.Client, v modrinth.Version, f modrinth.File, log *zap.SugaredLogger) (formats packFormatRange, probed string, err error) {
+	var cached db.ResourcePackFormat
+	if err := db.DB.Where("version_id = ? AND file_name = ?", v.ID, f.Filename).First(&cached).Error; err == nil {
+		return packFormatRange{Min: cached.MinFormat, Max: cached.MaxFormat}, "", nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "resourcepack-check-")
+	if err != nil {
+		return packFormatRange{}, "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	probed = filepath.Join(tmpDir, f.Filename)
+	if err := client.DownloadModFile(log, probed, f.URL); err != nil {
+		os.RemoveAll(tmpDir)
+		return packFormatRange{}, "", fmt.Errorf("failed to download resource pack: %w", err)
+	}
+	formats, err = readPackFormats(probed)
+	if err != nil {
+		log.Warnw("Failed to read resource pack format", zap.String("file", f.Filename), zap.Error(err))
+	}
+
+	cached = db.ResourcePackFormat{VersionID: v.ID, FileName: f.Filename, MinFormat: formats.Min, MaxFormat: formats.Max}
+	if err := db.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&cached).Error; err != nil {
+		log.Warnw("Failed to cache resource pack format", zap.String("file", f.Filename), zap.Error(err))
+	}
+	return formats, probed, nil
+}
+
+// installSelectedFile puts the selected file at downloadPath, moving the copy probed by selectVersion into
+// place instead of downloading the file again.
+func installSelectedFile(client *modrinth.Client, s versionSelection, downloadPath string, log *zap.SugaredLogger) error {
+	if s.Probed != "" {
+		if err := os.Rename(s.Probed, downloadPath); err == nil {
+			return nil
+		}
+		// Renaming fails when the temporary directory is on another file system.
+		if err := copyFile(s.Probed, downloadPath); err == nil {
+			return nil
+		}
+	}
+	return client.DownloadModFile(log, downloadPath, s.File.URL)
+}
+
+// orderedFiles returns the files of a version with the primary file first.
+func orderedFiles(v modrinth.Version) []modrinth.File {
+	files := make([]modrinth.File, 0, len(v.Files))
+	if primary := findPrimaryFile(v); primary != nil {
+		files = append(files, *primary)
+	}
+	for _, f := range v.Files {
+		if len(files) > 0 && f.Filename == files[0].Filename {
+			continue
+		}
+		files = append(files, f)
+	}
+	return files
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/modrinth"
+
+	"go.uber.org/zap"
+)
+
+func writeTestZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create zip: %v", err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, content := range files {
+		entry, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to add %s to zip: %v", name, err)
+		}
+		if _, err := entry.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+}
+
+func TestParsePackFormatRange(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected packFormatRange
+		wantErr  bool
+	}{
+		{"34", packFormatRange{34, 34}, false},
+		{"32-34", packFormatRange{32, 34}, false},
+		{" 34 - 32 ", packFormatRange{32, 34}, false},
+		{"abc", packFormatRange{}, true},
+		{"32-", packFormatRange{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			result, err := parsePackFormatRange(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePackFormatRange(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("parsePackFormatRange(%q) = %+v, want %+v", tt.value, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReadPackFormats(t *testing.T) {
+	tests := []struct {
+		name     string
+		mcmeta   string
+		expected packFormatRange
+	}{
+		{"pack_format only", `{"pack":{"pack_format":34}}`, packFormatRange{34, 34}},
+		{"supported_formats array", `{"pack":{"pack_format":34,"supported_formats":[32,42]}}`, packFormatRange{32, 42}},
+		{"supported_formats object", `{"pack":{"pack_format":15,"supported_formats":{"min_inclusive":15,"max_inclusive":18}}}`, packFormatRange{15, 18}},
+		{"min and max format", `{"pack":{"min_format":[64,0],"max_format":65}}`, packFormatRange{64, 65}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "pack.zip")
+			writeTestZip(t, path, map[string]string{"pack.mcmeta": tt.mcmeta})
+
+			result, err := readPackFormats(path)
+			if err != nil {
+				t.Fatalf("readPackFormats() returned error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("readPackFormats() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+
+	t.Run("missing pack.mcmeta", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "pack.zip")
+		writeTestZip(t, path, map[string]string{"assets/readme.txt": "hi"})
+		if _, err := readPackFormats(path); err == nil {
+			t.Error("Expected error for missing pack.mcmeta")
+		}
+	})
+}
+
+func TestSelectVersionKeepsInstalledVersion(t *testing.T) {
+	cfg := config.Config{ResourcePackFormat: "34"}
+	versions := []modrinth.Version{{ID: "v1", Files: []modrinth.File{
+		{Filename: "pack-x.zip", Primary: true},
+		{Filename: "pack.zip"},
+	}}}
+	installed := &db.Mod{VersionID: "v1", FileName: "pack.zip"}
+
+	// The installed version is the latest, so nothing is downloaded to check its pack_format.
+	selected, err := selectVersion(nil, &cfg, "resourcepack", versions, installed, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatalf("selectVersion() returned error: %v", err)
+	}
+	if selected.Version.ID != "v1" || selected.File.Filename != "pack.zip" || selected.Probed != "" {
+		t.Errorf("Unexpected selection: %+v", selected)
+	}
+}
@@ -37,6 +37,8 @@ func init() {
 
 // rollbackMod handles the rollback process for a specific mod
 func rollbackMod(projectSlug string) {
+	cfg, _ := bootstrapLocal(".")
+
 	// Find the current mod
 	var currentMod db.Mod
 	result := db.DB.Where("project_slug = ?", projectSlug).First(&currentMod)
@@ -75,9 +77,10 @@ func rollbackMod(projectSlug string) {
 	modsDir := filepath.Dir(currentMod.InstallPath)
 
 	// Delete the current file
-	log.Infow(ui.Colorize("Removing current version", currentMod.Color), zap.String("file", currentMod.InstallPath))
-	if err := os.Remove(currentMod.InstallPath); err != nil && !os.IsNotExist(err) {
-		log.Warnw("Failed to remove current version", zap.String("file", currentMod.InstallPath), zap.Error(err))
+	currentPath := installedFilePath(currentMod)
+	log.Infow(ui.Colorize("Removing current version", currentMod.Color), zap.String("file", currentPath))
+	if err := os.Remove(currentPath); err != nil && !os.IsNotExist(err) {
+		log.Warnw("Failed to remove current version", zap.String("file", currentPath), zap.Error(err))
 	}
 
 	// Copy the previous version to the mods directory
@@ -98,11 +101,16 @@ func rollbackMod(projectSlug string) {
 	if err := os.WriteFile(targetPath, sourceBytes, 0644); err != nil {
 		log.Fatalw("Failed to write file", zap.String("file", targetPath), zap.Error(err))
 	}
+	if err := keepDisabled(currentMod, targetPath); err != nil {
+		log.Fatalw("Failed to keep mod disabled", zap.String("file", targetPath), zap.Error(err))
+	}
 
 	// Update the current mod record in the database
 	currentMod.VersionID = previousVersion.VersionID
+	currentMod.VersionNumber = previousVersion.VersionNumber
 	currentMod.FileName = previousVersion.FileName
 	currentMod.InstallPath = targetPath
+	applyFileMetadata(&currentMod, cfg.MinecraftLoader)
 
 	if err := db.DB.Save(&currentMod).Error; err != nil {
 		log.Fatalw("Failed to update database record", zap.Error(err))
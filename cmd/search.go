@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/logger"
+	"modrinth-mod-updater/modrinth"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// searchFlags holds the filters given on the command line. Nil slices fall back to the configuration.
+type searchFlags struct {
+	ProjectType  string
+	Categories   []string
+	GameVersions []string
+	Loaders      []string
+	ClientSide   []string
+	ServerSide   []string
+	NoDefaults   bool
+	Sort         string
+	Limit        int
+	Page         int
+}
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search Modrinth for projects",
+	Long: `Search Modrinth for projects compatible with the configured instance.
+
+By default, results are filtered by MINECRAFT_VERSION, the loader used for the project type
+(MINECRAFT_LOADER for mods, SHADER_LOADER for shaders) and the side required by
+MINECRAFT_INSTALLATION_TYPE. Flags override each filter; --any drops the configured defaults.
+
+Example: modrinth-mod-updater search sodium
+Example: modrinth-mod-updater search --type shader --category realistic --page 2
+Example: modrinth-mod-updater search minimap --any --json`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := searchFlags{}
+		flags.ProjectType, _ = cmd.Flags().GetString("type")
+		flags.Categories, _ = cmd.Flags().GetStringSlice("category")
+		flags.NoDefaults, _ = cmd.Flags().GetBool("any")
+		flags.Sort, _ = cmd.Flags().GetString("sort")
+		flags.Limit, _ = cmd.Flags().GetInt("limit")
+		flags.Page, _ = cmd.Flags().GetInt("page")
+		for name, target := range map[string]*[]string{
+			"version":     &flags.GameVersions,
+			"loader":      &flags.Loaders,
+			"client-side": &flags.ClientSide,
+			"server-side": &flags.ServerSide,
+		} {
+			if cmd.Flags().Changed(name) {
+				*target, _ = cmd.Flags().GetStringSlice(name)
+				if *target == nil {
+					*target = []string{}
+				}
+			}
+		}
+		asJSON, _ := cmd.Flags().GetBool("json")
+		searchProjects(strings.Join(args, " "), flags, asJSON)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	searchCmd.Flags().String("type", "", "Project type: mod, modpack, resourcepack, shader, datapack or plugin (default: mod, or plugin on plugin platforms)")
+	searchCmd.Flags().StringSlice("category", nil, "Categories that must all match (e.g. optimization)")
+	searchCmd.Flags().StringSlice("version", nil, "Minecraft versions (default: MINECRAFT_VERSION)")
+	searchCmd.Flags().StringSlice("loader", nil, "Loaders (default: the configured loader for the project type)")
+	searchCmd.Flags().StringSlice("client-side", nil, "Accepted client_side values (required, optional, unsupported)")
+	searchCmd.Flags().StringSlice("server-side", nil, "Accepted server_side values (required, optional, unsupported)")
+	searchCmd.Flags().Bool("any", false, "Do not apply the configured version, loader and side filters")
+	searchCmd.Flags().String("sort", "relevance", "Sort order: relevance, downloads, follows, newest or updated")
+	searchCmd.Flags().IntP("limit", "n", 10, "Results per page (max 100)")
+	searchCmd.Flags().IntP("page", "p", 1, "Page of results to show")
+	searchCmd.Flags().Bool("json", false, "Print the raw search result as JSON")
+}
+
+func searchProjects(query string, flags searchFlags, asJSON bool) {
+	cfg, client := bootstrapClient(".")
+
+	opts := buildSearchOptions(&cfg, query, flags)
+	result, err := client.Search(opts)
+	if err != nil {
+		logger.Log.Fatalw("Search failed", zap.Error(err))
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			logger.Log.Fatalw("Failed to encode search result", zap.Error(err))
+		}
+		return
+	}
+	printSearchResult(result, opts)
+}
+
+// buildSearchOptions combines the command-line filters with defaults derived from the configuration.
+func buildSearchOptions(cfg *config.Config, query string, flags searchFlags) modrinth.SearchOptions {
+	opts := modrinth.SearchOptions{
+		Query:        query,
+		Categories:   flags.Categories,
+		ProjectType:  flags.ProjectType,
+		GameVersions: flags.GameVersions,
+		Loaders:      flags.Loaders,
+		ClientSide:   flags.ClientSide,
+		ServerSide:   flags.ServerSide,
+		Index:        flags.Sort,
+		Limit:        min(max(flags.Limit, 1), 100),
+	}
+	opts.Offset = (max(flags.Page, 1) - 1) * opts.Limit
+
+	if opts.ProjectType == "" {
+		opts.ProjectType = "mod"
+		if cfg.IsPluginPlatform() {
+			opts.ProjectType = "plugin"
+		}
+	}
+	if flags.NoDefaults {
+		return opts
+	}
+
+	if opts.GameVersions == nil {
+		opts.GameVersions = []string{cfg.MinecraftVersion}
+	}
+	if opts.Loaders == nil {
+		opts.Loaders = projectLoaders(cfg, opts.ProjectType)
+	}
+	// Plugins have no meaningful client support, and plugin projects run on servers and proxies only.
+	if opts.ProjectType != "plugin" && opts.ClientSide == nil && opts.ServerSide == nil {
+		switch strings.ToLower(cfg.MinecraftInstallationType) {
+		case "client":
+			opts.ClientSide = []string{"required", "optional"}
+		case "server":
+			opts.ServerSide = []string{"required", "optional"}
+		}
+	}
+	return opts
+}
+
+func printSearchResult(result *modrinth.SearchResult, opts modrinth.SearchOptions) {
+	if len(result.Hits) == 0 {
+		fmt.Println("No projects found.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SLUG\tTITLE\tTYPE\tDOWNLOADS\tAUTHOR\tDESCRIPTION")
+	for _, hit := range result.Hits {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			hit.Slug, truncate(hit.Title, 30), hit.ProjectType, formatCount(hit.Downloads), hit.Author, truncate(hit.Description, 60))
+	}
+	w.Flush()
+
+	page := opts.Offset/opts.Limit + 1
+	pages := (result.TotalHits + opts.Limit - 1) / opts.Limit
+	fmt.Printf("\nPage %d of %d (%d results)\n", page, pages, result.TotalHits)
+}
+
+// formatCount abbreviates large counts, e.g. 1234567 as 1.2M.
+func formatCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return strconv.FormatFloat(float64(n)/1_000_000, 'f', 1, 64) + "M"
+	case n >= 1_000:
+		return strconv.FormatFloat(float64(n)/1_000, 'f', 1, 64) + "k"
+	default:
+		return strconv.Itoa(n)
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"modrinth-mod-updater/config"
+)
+
+func TestBuildSearchOptions(t *testing.T) {
+	cfg := &config.Config{MinecraftVersion: "1.21.1", MinecraftLoader: "fabric", MinecraftInstallationType: "server", ShaderLoader: "iris"}
+
+	opts := buildSearchOptions(cfg, "sodium", searchFlags{Limit: 10, Page: 3})
+	if opts.ProjectType != "mod" || !reflect.DeepEqual(opts.GameVersions, []string{"1.21.1"}) || !reflect.DeepEqual(opts.Loaders, []string{"fabric"}) {
+		t.Errorf("default options = %+v", opts)
+	}
+	if !reflect.DeepEqual(opts.ServerSide, []string{"required", "optional"}) || opts.ClientSide != nil {
+		t.Errorf("default sides = %v / %v, want server side filter", opts.ClientSide, opts.ServerSide)
+	}
+	if opts.Offset != 20 || opts.Limit != 10 {
+		t.Errorf("paging = offset %d limit %d, want 20 and 10", opts.Offset, opts.Limit)
+	}
+
+	opts = buildSearchOptions(cfg, "", searchFlags{ProjectType: "shader", Loaders: []string{}, Limit: 500})
+	if opts.Loaders == nil || len(opts.Loaders) != 0 {
+		t.Errorf("explicit empty loaders = %v, want empty", opts.Loaders)
+	}
+	if opts.Limit != 100 || opts.Offset != 0 {
+		t.Errorf("clamped paging = offset %d limit %d, want 0 and 100", opts.Offset, opts.Limit)
+	}
+
+	opts = buildSearchOptions(cfg, "", searchFlags{ProjectType: "shader", Limit: 10})
+	if !reflect.DeepEqual(opts.Loaders, []string{"iris"}) {
+		t.Errorf("shader loaders = %v, want [iris]", opts.Loaders)
+	}
+
+	opts = buildSearchOptions(cfg, "", searchFlags{NoDefaults: true, Limit: 10})
+	if opts.GameVersions != nil || opts.Loaders != nil || opts.ServerSide != nil {
+		t.Errorf("--any options = %+v, want no config filters", opts)
+	}
+
+	plugin := &config.Config{MinecraftVersion: "1.21.1", MinecraftLoader: "paper", MinecraftInstallationType: "server"}
+	opts = buildSearchOptions(plugin, "", searchFlags{Limit: 10})
+	if opts.ProjectType != "plugin" || opts.ServerSide != nil {
+		t.Errorf("plugin options = %+v, want plugin type without side filter", opts)
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{999, "999"},
+		{1500, "1.5k"},
+		{2_345_678, "2.3M"},
+	}
+
+	for _, tt := range tests {
+		if got := formatCount(tt.n); got != tt.want {
+			t.Errorf("formatCount(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
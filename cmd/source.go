@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/jarmeta"
+	"modrinth-mod-updater/logger"
+	"modrinth-mod-updater/modrinth"
+	"modrinth-mod-updater/provider"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// sourceCmd groups the commands managing mods from non-Modrinth providers
+var sourceCmd = &cobra.Command{
+	Use:   "source",
+	Short: "Manage mods installed from GitHub Releases, Maven repositories or CurseForge",
+	Long: `Track mods that are not on Modrinth. Tracked sources are checked and installed by the update command
+alongside the followed Modrinth projects.`,
+}
+
+// sourceAddCmd represents the source add command
+var sourceAddCmd = &cobra.Command{
+	Use:   "add <github|maven|curseforge> <ref>",
+	Short: "Track a mod from a GitHub repository, Maven repository or CurseForge",
+	Long: `Track a mod from a non-Modrinth provider. The next update run installs its newest release.
+
+GitHub references have the form owner/repo; release assets ending in .jar are installed.
+Maven references have the form <repository-url>/<group>:<artifact>.
+CurseForge references are numeric project IDs and require CURSEFORGE_API_KEY.
+
+Example: modrinth-mod-updater source add github CaffeineMC/sodium
+Example: modrinth-mod-updater source add maven https://maven.example.com/releases/com.example:mymod --slug mymod`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		slug, _ := cmd.Flags().GetString("slug")
+		title, _ := cmd.Flags().GetString("title")
+		projectType, _ := cmd.Flags().GetString("type")
+		addSource(strings.ToLower(args[0]), args[1], slug, title, projectType)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(sourceCmd)
+	sourceCmd.AddCommand(sourceAddCmd)
+
+	sourceAddCmd.Flags().String("slug", "", "Slug to track the mod under (default: provider-prefixed repository name, artifact ID or project ID)")
+	sourceAddCmd.Flags().String("title", "", "Display title (default: slug)")
+	sourceAddCmd.Flags().String("type", "mod", "Project type: mod, shader, resourcepack, datapack or plugin")
+}
+
+// newProviders creates the configured providers in identification order, Modrinth first.
+// CurseForge is only available when CURSEFORGE_API_KEY is set.
+func newProviders(cfg *config.Config, client *modrinth.Client) []provider.Provider {
+	providers := []provider.Provider{
+		provider.NewModrinth(client),
+		provider.NewGitHub(cfg.GitHubAPIURL, cfg.GitHubToken, cfg.UserAgent),
+		provider.NewMaven(cfg.UserAgent),
+	}
+	if cfg.CurseForgeAPIKey != "" {
+		providers = append(providers, provider.NewCurseForge(cfg.CurseForgeAPIURL, cfg.CurseForgeAPIKey, cfg.UserAgent))
+	}
+	return providers
+}
+
+// providersByName indexes providers by their name.
+func providersByName(providers []provider.Provider) map[string]provider.Provider {
+	byName := make(map[string]provider.Provider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+	return byName
+}
+
+// isModrinthMod reports whether a mod is managed through Modrinth. Records created before providers
+// were introduced have no provider set.
+func isModrinthMod(mod db.Mod) bool {
+	return mod.Provider == "" || mod.Provider == provider.ModrinthName
+}
+
+// sourceSlug derives the slug a project is tracked under from its provider reference (db.Mod.Source):
+// the slug itself for Modrinth, and the repository name for GitHub, the artifact ID for Maven and the
+// project ID for CurseForge, prefixed with the provider name so they cannot collide with Modrinth slugs.
+// Both source add and the import of identified files use it, so they agree on the slug of a project.
+func sourceSlug(providerName, ref string) (string, error) {
+	switch providerName {
+	case provider.ModrinthName:
+		return ref, nil
+	case provider.GitHubName:
+		_, repo, ok := strings.Cut(ref, "/")
+		if !ok || repo == "" {
+			return "", fmt.Errorf("invalid GitHub reference %q, expected owner/repo", ref)
+		}
+		return "github-" + strings.ToLower(repo), nil
+	case provider.MavenName:
+		_, _, artifact, err := provider.ParseMavenRef(ref)
+		if err != nil {
+			return "", err
+		}
+		return "maven-" + strings.ToLower(artifact), nil
+	case provider.CurseForgeName:
+		if _, err := strconv.Atoi(ref); err != nil {
+			return "", fmt.Errorf("invalid CurseForge reference %q, expected a numeric project ID", ref)
+		}
+		return "curseforge-" + ref, nil
+	default:
+		return "", fmt.Errorf("unknown provider %q", providerName)
+	}
+}
+
+// findProviderMod loads the record of a project from the given provider by slug. It returns
+// gorm.ErrRecordNotFound when the project is not tracked, and another error when the slug is tracked from
+// another provider, e.g. a source added with --slug, whose record must not be treated as this project's.
+func findProviderMod(providerName, slug string, mod *db.Mod) error {
+	providers := []string{providerName}
+	if providerName == provider.ModrinthName {
+		providers = append(providers, "") // See isModrinthMod
+	}
+	err := db.DB.Where("project_slug = ? AND provider IN ?", slug, providers).First(mod).Error
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	var other db.Mod
+	if db.DB.Where("project_slug = ?", slug).First(&other).Error == nil {
+		return fmt.Errorf("slug %s is already tracked from %s", slug, other.Provider)
+	}
+	return err
+}
+
+// findModrinthMod loads the record of a Modrinth project by slug, see findProviderMod.
+func findModrinthMod(slug string, mod *db.Mod) error {
+	return findProviderMod(provider.ModrinthName, slug, mod)
+}
+
+func addSource(providerName, ref, slug, title, projectType string) {
+	cfg, client := bootstrap(".")
+
+	if providerName == provider.ModrinthName {
+		logger.Log.Fatal("Modrinth projects are managed by following them on Modrinth.")
+	}
+	p, ok := providersByName(newProviders(&cfg, client))[providerName]
+	if !ok {
+		logger.Log.Fatalw("Unknown or unconfigured provider, expected github, maven or curseforge (with CURSEFORGE_API_KEY)", zap.String("provider", providerName))
+	}
+	if !isSupportedProjectType(projectType) {
+		logger.Log.Fatalw("Unsupported project type", zap.String("type", projectType))
+	}
+
+	if slug == "" {
+		var err error
+		if slug, err = sourceSlug(providerName, ref); err != nil {
+			logger.Log.Fatalw("Invalid source reference", zap.String("ref", ref), zap.Error(err))
+		}
+	}
+	if title == "" {
+		title = slug
+	}
+
+	versions, err := p.ListVersions(ref, provider.Filter{})
+	if err != nil {
+		logger.Log.Fatalw("Failed to list versions", zap.String("provider", providerName), zap.String("ref", ref), zap.Error(err))
+	}
+	if len(versions) == 0 {
+		logger.Log.Fatalw("Source has no installable versions", zap.String("ref", ref))
+	}
+
+	var count int64
+	db.DB.Model(&db.Mod{}).Where("project_slug = ?", slug).Count(&count)
+	if count > 0 {
+		logger.Log.Fatalw("A mod with this slug is already tracked, choose another with --slug", zap.String("slug", slug))
+	}
+
+	mod := db.Mod{ProjectSlug: slug, Title: title, ProjectType: projectType, Provider: providerName, Source: ref}
+	if err := db.DB.Create(&mod).Error; err != nil {
+		logger.Log.Fatalw("Failed to save source to database", zap.Error(err))
+	}
+	fmt.Printf("Tracking %s from %s (%s). Run update to install it.\n", slug, providerName, ref)
+}
+
+// providerFilter returns the version filter for a mod, adding the fallback game versions when allowed.
+// Quilt also accepts files built for Fabric, which it loads.
+func providerFilter(cfg *config.Config, mod db.Mod) provider.Filter {
+	gameVersions := []string{cfg.MinecraftVersion}
+	if cfg.AllowsVersionFallback(mod.ProjectSlug) {
+		gameVersions = append(gameVersions, cfg.GameVersionFallbacks...)
+	}
+	loaders := projectLoaders(cfg, mod.ProjectType)
+	if slices.Contains(loaders, jarmeta.Quilt) {
+		loaders = append(loaders, jarmeta.Fabric)
+	}
+	return provider.Filter{GameVersions: gameVersions, Loaders: loaders}
+}
+
+// providerPlan is the version update would install for a mod tracked from a non-Modrinth provider.
+type providerPlan struct {
+	Version provider.Version
+	File    *provider.File
+	FileErr error // Why no file can be installed, e.g. provider.ErrDistributionNotAllowed
+}
+
+// planProviderUpdate selects the newest version of a mod from its provider. It returns nil without
+// an error when the provider has no matching versions.
+func planProviderUpdate(mod db.Mod, p provider.Provider, cfg *config.Config) (*providerPlan, error) {
+	versions, err := p.ListVersions(mod.Source, providerFilter(cfg, mod))
+	if err != nil || len(versions) == 0 {
+		return nil, err
+	}
+	plan := &providerPlan{Version: versions[0]}
+	plan.File, plan.FileErr = p.ResolveFile(plan.Version)
+	return plan, nil
+}
+
+// processProviderMod checks a mod tracked from a non-Modrinth provider and installs its newest version.
+func processProviderMod(mod db.Mod, p provider.Provider, cfg *config.Config, forceUpdate bool, sendMsg func(UpdateProgressMsg), downloadedCount, updatedCount *atomic.Int64) {
+	log := logger.Log.With(zap.String("project_slug", mod.ProjectSlug), zap.String("provider", mod.Provider))
+	log.Info("Checking project")
+	if mod.Pinned {
+		log.Infow("Skipping pinned project", zap.String("version", mod.VersionNumber))
+		return
+	}
+
+	plan, err := planProviderUpdate(mod, p, cfg)
+	if err != nil {
+		log.Errorw("Failed to get project versions", zap.Error(err))
+		sendMsg(UpdateProgressMsg{Type: "error", ProjectName: mod.Title, Message: "Failed to get versions"})
+		return
+	}
+	if plan == nil {
+		log.Info("No compatible version found")
+		sendMsg(UpdateProgressMsg{Type: "status", Message: fmt.Sprintf("Skipped %s, no compatible version", mod.Title)})
+		return
+	}
+	latest := plan.Version
+	if errors.Is(plan.FileErr, provider.ErrDistributionNotAllowed) {
+		if mod.VersionID != latest.ID {
+			log.Warnw("Update available, but the project only allows downloads from its own site", zap.String("version", latest.VersionNumber))
+			sendMsg(UpdateProgressMsg{Type: "error", ProjectName: mod.Title, Message: "Update " + latest.VersionNumber + " must be downloaded manually"})
+		}
+		return
+	}
+	if plan.FileErr != nil {
+		log.Errorw("Failed to resolve file", zap.String("version", latest.VersionNumber), zap.Error(plan.FileErr))
+		sendMsg(UpdateProgressMsg{Type: "error", ProjectName: mod.Title, Message: "No files found for version"})
+		return
+	}
+	file := plan.File
+	if latest.Unconfirmed {
+		log.Warnw("No release mentions the game version, installing the newest one without confirmed compatibility",
+			zap.String("version", latest.VersionNumber), zap.String("file", file.Filename))
+	}
+
+	baseDir := projectBaseDir(cfg, mod.ProjectType)
+	fileMissing := mod.FileName == ""
+	if !fileMissing {
+		if _, err := os.Stat(filepath.Join(baseDir, installedFileName(mod))); os.IsNotExist(err) {
+			fileMissing = true
+		}
+	}
+	if !forceUpdate && !fileMissing && mod.VersionID == latest.ID {
+		log.Infow("Mod is already up to date", zap.String("version", mod.VersionNumber))
+		return
+	}
+
+	sendMsg(UpdateProgressMsg{Type: "download_start", ProjectName: mod.Title, Version: latest.VersionNumber, Color: mod.Color})
+	isNew := mod.VersionID == ""
+	if err := installProviderFile(&mod, p, latest, *file, baseDir, cfg, fileMissing, log); err != nil {
+		log.Errorw("Failed to install file", zap.String("filename", file.Filename), zap.Error(err))
+		sendMsg(UpdateProgressMsg{Type: "error", ProjectName: mod.Title, Message: "Download failed"})
+		return
+	}
+
+	if isNew {
+		downloadedCount.Add(1)
+	} else {
+		updatedCount.Add(1)
+	}
+	sendMsg(UpdateProgressMsg{Type: "download_success", ProjectName: mod.Title, Version: latest.VersionNumber})
+}
+
+// installProviderFile replaces the installed file of mod with the given version and saves the record.
+func installProviderFile(mod *db.Mod, p provider.Provider, version provider.Version, file provider.File, baseDir string, cfg *config.Config, fileMissing bool, log *zap.SugaredLogger) error {
+	if cfg.KeepOldVersions {
+		_ = os.MkdirAll(filepath.Join(baseDir, "versions"), 0755)
+	}
+	if !fileMissing {
+		archiveAndCleanupOld(*mod, baseDir, cfg, log)
+	}
+
+	downloadPath := filepath.Join(baseDir, file.Filename)
+	if err := p.Download(log, downloadPath, file); err != nil {
+		return err
+	}
+	if len(file.Hashes) > 0 {
+		if err := verifyFileHashes(downloadPath, file.Hashes); err != nil {
+			os.Remove(downloadPath)
+			return err
+		}
+	}
+	if mod.Disabled {
+		if err := keepDisabled(*mod, downloadPath); err != nil {
+			return err
+		}
+	} else if mod.ProjectType == "datapack" {
+		syncDatapackCopies(cfg, downloadPath, mod.FileName, log)
+	}
+
+	mod.VersionID = version.ID
+	mod.VersionNumber = version.VersionNumber
+	mod.FileName = file.Filename
+	mod.InstallPath = downloadPath
+	if !version.Published.IsZero() {
+		mod.Updated = version.Published
+	}
+	applyFileMetadata(mod, cfg.MinecraftLoader)
+	return db.DB.Save(mod).Error
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+)
+
+func TestSourceSlug(t *testing.T) {
+	tests := []struct {
+		provider string
+		ref      string
+		want     string
+		wantErr  bool
+	}{
+		{"modrinth", "sodium", "sodium", false},
+		{"github", "CaffeineMC/Sodium", "github-sodium", false},
+		{"github", "sodium", "", true},
+		{"maven", "https://maven.example.com/releases/com.example:MyMod", "maven-mymod", false},
+		{"maven", "https://maven.example.com/releases/com.example", "", true},
+		{"curseforge", "238222", "curseforge-238222", false},
+		{"curseforge", "jei", "", true},
+		{"unknown", "ref", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := sourceSlug(tt.provider, tt.ref)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("sourceSlug(%q, %q) error = %v, wantErr %v", tt.provider, tt.ref, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("sourceSlug(%q, %q) = %q, want %q", tt.provider, tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestProviderFilter(t *testing.T) {
+	cfg := &config.Config{
+		MinecraftVersion:        "1.21.5",
+		MinecraftLoader:         "fabric",
+		GameVersionFallbacks:    []string{"1.21.4"},
+		VersionFallbackProjects: []string{"lenient"},
+	}
+
+	filter := providerFilter(cfg, db.Mod{ProjectSlug: "lenient", ProjectType: "mod"})
+	if !slices.Equal(filter.GameVersions, []string{"1.21.5", "1.21.4"}) || !slices.Equal(filter.Loaders, []string{"fabric"}) {
+		t.Errorf("providerFilter(lenient) = %+v, want fallback versions and fabric", filter)
+	}
+
+	filter = providerFilter(cfg, db.Mod{ProjectSlug: "strict", ProjectType: "mod"})
+	if !slices.Equal(filter.GameVersions, []string{"1.21.5"}) {
+		t.Errorf("providerFilter(strict) = %+v, want only 1.21.5", filter)
+	}
+
+	cfg.MinecraftLoader = "quilt"
+	filter = providerFilter(cfg, db.Mod{ProjectSlug: "strict", ProjectType: "mod"})
+	if !slices.Equal(filter.Loaders, []string{"quilt", "fabric"}) {
+		t.Errorf("providerFilter(quilt) = %+v, want quilt and fabric", filter)
+	}
+}
+
+func TestIsModrinthMod(t *testing.T) {
+	tests := []struct {
+		provider string
+		want     bool
+	}{
+		{"", true},
+		{"modrinth", true},
+		{"github", false},
+		{"maven", false},
+		{"curseforge", false},
+	}
+
+	for _, tt := range tests {
+		if got := isModrinthMod(db.Mod{Provider: tt.provider}); got != tt.want {
+			t.Errorf("isModrinthMod(%q) = %v, want %v", tt.provider, got, tt.want)
+		}
+	}
+}
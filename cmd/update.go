@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -12,11 +14,13 @@ import (
 	"modrinth-mod-updater/db"
 	"modrinth-mod-updater/logger"
 	"modrinth-mod-updater/modrinth"
+	"modrinth-mod-updater/provider"
 	"modrinth-mod-updater/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // updateCmd represents the update command
@@ -24,19 +28,34 @@ var updateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Checks for and downloads updates for followed mods",
 	Long: `Checks Modrinth for new compatible versions of followed mods
-and downloads them to the 'mods' directory.`,
+and downloads them to the 'mods' directory.
+
+With --check or CHECK_AFTER_UPDATE, the dependencies declared by the installed
+jars are checked afterwards like the check command does, and the command exits
+with status 1 when a constraint is not satisfied.`,
 	Run: func(cmd *cobra.Command, _ []string) {
 		logger.Log.Info("Running update command...")
 
 		// Get the force flag value
 		forceUpdate, _ := cmd.Flags().GetBool("force")
+		runCheck, _ := cmd.Flags().GetBool("check")
 
 		// Run update with TUI
 		p := tea.NewProgram(initialUpdateModel(forceUpdate))
-		if _, err := p.Run(); err != nil {
+		final, err := p.Run()
+		if err != nil {
 			logger.Log.Fatalw("Failed to run update UI", zap.Error(err))
 			os.Exit(1)
 		}
+
+		// The check needs the finished update; it is skipped when the update was interrupted.
+		m, _ := final.(UpdateModel)
+		if m.done && (runCheck || m.cfg.CheckAfterUpdate) {
+			fmt.Println()
+			if !checkInstalledMods(m.cfg) {
+				os.Exit(1)
+			}
+		}
 	},
 }
 
@@ -45,9 +64,11 @@ func init() {
 
 	// Add flags for the update command
 	updateCmd.Flags().BoolP("force", "f", false, "Force redownload of all mods regardless of version")
+	updateCmd.Flags().Bool("check", false, "Check the dependencies of the installed mods after updating (default: CHECK_AFTER_UPDATE)")
 }
 
-func runUpdate(forceUpdate bool, progressChan chan<- UpdateProgressMsg) {
+// runUpdate updates the followed projects and tracked sources and returns the configuration it loaded.
+func runUpdate(forceUpdate bool, progressChan chan<- UpdateProgressMsg) config.Config {
 	sendMsg := func(msg UpdateProgressMsg) {
 		if progressChan != nil {
 			progressChan <- msg
@@ -59,29 +80,43 @@ func runUpdate(forceUpdate bool, progressChan chan<- UpdateProgressMsg) {
 	cfg, client := bootstrap(".")
 
 	sendMsg(UpdateProgressMsg{Type: "status", Message: "Fetching followed projects..."})
-	followedProjects, err := client.GetFollowedProjects()
+	followedProjects, err := followedProjects(&cfg, client)
 	if err != nil {
 		logger.Log.Fatalw("Failed to get followed projects", zap.Error(err))
 	}
 
-	if len(followedProjects) == 0 {
+	followedIDs := make([]string, 0, len(followedProjects))
+	for _, p := range followedProjects {
+		followedIDs = append(followedIDs, p.ID)
+	}
+	if err := markFollowed(followedIDs); err != nil {
+		logger.Log.Warnw("Failed to mark followed projects", zap.Error(err))
+	}
+	followedProjects = append(followedProjects, handleUnfollowed(&cfg, client, followedProjects, sendMsg)...)
+
+	var sourceMods []db.Mod
+	if err := db.DB.Where("provider NOT IN ?", []string{provider.ModrinthName, localProvider}).Find(&sourceMods).Error; err != nil {
+		logger.Log.Warnw("Failed to load mods from other providers", zap.Error(err))
+	}
+
+	if len(followedProjects) == 0 && len(sourceMods) == 0 {
 		logger.Log.Info("No followed projects found.")
 		sendMsg(UpdateProgressMsg{Type: "summary", Message: "No followed projects found."})
-		return
+		return cfg
 	}
 
 	logger.Log.Infof("Found %d followed projects. Checking for updates for Minecraft %s (%s)...",
-		len(followedProjects), cfg.MinecraftVersion, cfg.MinecraftLoader)
+		len(followedProjects)+len(sourceMods), cfg.MinecraftVersion, cfg.MinecraftLoader)
 
-	sendMsg(UpdateProgressMsg{Type: "status", Message: fmt.Sprintf("Checking %d projects...", len(followedProjects))})
+	sendMsg(UpdateProgressMsg{Type: "status", Message: fmt.Sprintf("Checking %d projects...", len(followedProjects)+len(sourceMods))})
 
 	var downloadedCount atomic.Int64
 	var updatedCount atomic.Int64
 	var wg sync.WaitGroup
 
 	for _, project := range followedProjects {
-		if project.ProjectType != "mod" && project.ProjectType != "shader" && project.ProjectType != "resourcepack" {
-			logger.Log.Infow("Skipping non-mod/shader/resourcepack project",
+		if !isSupportedProjectType(project.ProjectType) {
+			logger.Log.Infow("Skipping unsupported project type",
 				zap.String("title", project.Title),
 				zap.String("type", project.ProjectType),
 			)
@@ -97,56 +132,164 @@ func runUpdate(forceUpdate bool, progressChan chan<- UpdateProgressMsg) {
 		}(project)
 	}
 
+	providers := providersByName(newProviders(&cfg, client))
+	for _, mod := range sourceMods {
+		p, ok := providers[mod.Provider]
+		if !ok {
+			logger.Log.Warnw("Skipping mod from unknown provider", zap.String("slug", mod.ProjectSlug), zap.String("provider", mod.Provider))
+			continue
+		}
+
+		sendMsg(UpdateProgressMsg{Type: "check", ProjectName: mod.Title, Color: mod.Color})
+
+		wg.Add(1)
+		go func(m db.Mod) {
+			defer wg.Done()
+			processProviderMod(m, p, &cfg, forceUpdate, sendMsg, &downloadedCount, &updatedCount)
+		}(mod)
+	}
+
 	wg.Wait()
 
 	summary := fmt.Sprintf("Finished. Downloaded %d new mods, updated %d existing mods.", downloadedCount.Load(), updatedCount.Load())
 	logger.Log.Info(summary)
 	sendMsg(UpdateProgressMsg{Type: "summary", Message: summary})
+	return cfg
+}
+
+// handleUnfollowed applies UNFOLLOWED_POLICY to installed projects that are no longer followed, see
+// policyUnfollowed. With the update policy it returns their projects, to be updated together with the
+// followed ones.
+func handleUnfollowed(cfg *config.Config, client *modrinth.Client, followed []modrinth.Project, sendMsg func(UpdateProgressMsg)) []modrinth.Project {
+	var mods []db.Mod
+	if err := db.DB.Find(&mods).Error; err != nil {
+		logger.Log.Warnw("Failed to load mods from database", zap.Error(err))
+		return nil
+	}
+	mods = policyUnfollowed(mods, followed)
+
+	var ids []string
+	for _, mod := range mods {
+		log := logger.Log.With(zap.String("project_slug", mod.ProjectSlug), zap.String("policy", cfg.UnfollowedPolicy))
+		switch cfg.UnfollowedPolicy {
+		case config.UnfollowedUpdate:
+			ids = append(ids, mod.ProjectID)
+		case config.UnfollowedArchive, config.UnfollowedRemove:
+			if err := pruneMod(cfg, mod, cfg.UnfollowedPolicy == config.UnfollowedArchive, log); err != nil {
+				log.Errorw("Failed to prune unfollowed project", zap.Error(err))
+				sendMsg(UpdateProgressMsg{Type: "error", ProjectName: mod.Title, Message: "Failed to prune unfollowed project"})
+				continue
+			}
+			log.Infow("Pruned unfollowed project")
+			sendMsg(UpdateProgressMsg{Type: "status", Message: fmt.Sprintf("Pruned unfollowed project %s", mod.Title)})
+		default:
+			log.Infow("Project is no longer followed and will not be updated")
+		}
+	}
+
+	projects, err := client.GetProjects(ids)
+	if err != nil {
+		logger.Log.Warnw("Failed to get unfollowed projects", zap.Error(err))
+	}
+	return projects
+}
+
+// policyUnfollowed returns the mods UNFOLLOWED_POLICY applies to: unfollowed mods whose project was
+// followed before. Mods that were never followed, e.g. imported jars or files installed from a modpack
+// before it followed its projects, are left alone; follows sync or prune --action adopt follows them.
+func policyUnfollowed(mods []db.Mod, followed []modrinth.Project) []db.Mod {
+	return slices.DeleteFunc(mods, func(m db.Mod) bool { return !m.Followed || !isUnfollowed(m, followed) })
 }
 
 func processProject(p modrinth.Project, cfg *config.Config, client *modrinth.Client, forceUpdate bool, sendMsg func(UpdateProgressMsg), downloadedCount, updatedCount *atomic.Int64) {
 	goroutineLogger := logger.Log.With(zap.String("project_slug", p.Slug), zap.String("project_title", p.Title))
 	goroutineLogger.Info(ui.Colorize("Checking project", p.Color))
 
-	if !shouldProcessProject(p, cfg, goroutineLogger) {
+	plan, err := planProjectUpdate(p, cfg, client, goroutineLogger)
+	if err != nil {
+		sendMsg(UpdateProgressMsg{Type: "error", ProjectName: p.Title, Message: err.Error()})
 		return
 	}
-
-	versions, err := client.GetProjectVersions(p.Slug, p.ProjectType, cfg.MinecraftVersion, cfg.MinecraftLoader)
-	if err != nil {
-		goroutineLogger.Errorw("Failed to get project versions", zap.Error(err))
-		sendMsg(UpdateProgressMsg{Type: "error", ProjectName: p.Title, Message: "Failed to get versions"})
+	if plan == nil {
 		return
 	}
+	defer plan.selection().discard()
 
-	if len(versions) == 0 {
-		goroutineLogger.Info("  No compatible versions found.")
-		return
+	if cfg.KeepOldVersions {
+		_ = os.MkdirAll(filepath.Join(plan.BaseDir, "versions"), 0755)
 	}
 
-	latestVersion := versions[0]
-	primaryFile := findPrimaryFile(latestVersion)
-	if primaryFile == nil {
-		goroutineLogger.Errorw("Latest version has no files at all!", zap.String("version_id", latestVersion.ID))
-		sendMsg(UpdateProgressMsg{Type: "error", ProjectName: p.Title, Message: "No files found for version"})
-		return
+	if plan.Existing != nil {
+		handleExistingMod(plan.Project, *plan.Existing, plan.selection(), plan.BaseDir, cfg, client, forceUpdate, sendMsg, updatedCount, goroutineLogger)
+	} else {
+		handleNewMod(plan.Project, plan.selection(), plan.BaseDir, cfg, client, sendMsg, downloadedCount, goroutineLogger)
 	}
+}
 
-	targetSubDir := getTargetSubDir(p.ProjectType)
-	projectBaseDir := filepath.Join(cfg.MinecraftDir, targetSubDir)
+// projectPlan is the version update would install for a followed project.
+type projectPlan struct {
+	Project  modrinth.Project // Project with its effective project type
+	Version  modrinth.Version
+	File     *modrinth.File
+	Probed   string // Copy of File downloaded by selectVersion, see versionSelection
+	BaseDir  string
+	Existing *db.Mod // Installed record, nil when the project is not installed yet
+}
 
-	if cfg.KeepOldVersions {
-		_ = os.MkdirAll(filepath.Join(projectBaseDir, "versions"), 0755)
+func (p *projectPlan) selection() versionSelection {
+	return versionSelection{Version: p.Version, File: p.File, Probed: p.Probed}
+}
+
+// UpToDate reports whether the planned version is already installed.
+func (p *projectPlan) UpToDate() bool {
+	return p.Existing != nil && p.Existing.VersionID == p.Version.ID
+}
+
+// planProjectUpdate selects the version update would install for a project. It returns nil without an
+// error when the project is skipped or has no compatible versions; errors carry a short message for the UI.
+func planProjectUpdate(p modrinth.Project, cfg *config.Config, client *modrinth.Client, goroutineLogger *zap.SugaredLogger) (*projectPlan, error) {
+	p.ProjectType = effectiveProjectType(p, cfg)
+
+	if !shouldProcessProject(p, cfg, goroutineLogger) {
+		return nil, nil
 	}
 
+	var existing *db.Mod
 	var existingMod db.Mod
-	result := db.DB.Where("project_slug = ?", p.Slug).First(&existingMod)
+	switch err := findModrinthMod(p.Slug, &existingMod); {
+	case err == nil:
+		if existingMod.Pinned {
+			goroutineLogger.Infow(ui.Colorize("Skipping pinned project", p.Color), zap.String("version", existingMod.VersionNumber))
+			return nil, nil
+		}
+		existing = &existingMod
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		goroutineLogger.Errorw("Failed to load installed record", zap.Error(err))
+		return nil, err
+	}
 
-	if result.Error == nil {
-		handleExistingMod(p, existingMod, latestVersion, primaryFile, projectBaseDir, cfg, client, forceUpdate, sendMsg, updatedCount, goroutineLogger)
-	} else {
-		handleNewMod(p, latestVersion, primaryFile, projectBaseDir, client, sendMsg, downloadedCount, goroutineLogger)
+	versions, err := fetchCompatibleVersions(client, cfg, p.Slug, p.ProjectType)
+	if err != nil {
+		goroutineLogger.Errorw("Failed to get project versions", zap.Error(err))
+		return nil, errors.New("failed to get versions")
+	}
+
+	if len(versions) == 0 {
+		goroutineLogger.Info("  No compatible versions found.")
+		return nil, nil
+	}
+
+	selected, err := selectVersion(client, cfg, p.ProjectType, versions, existing, goroutineLogger)
+	if err != nil {
+		goroutineLogger.Warnw("No suitable version found", zap.Error(err))
+		return nil, err
+	}
+	if selected.File == nil {
+		goroutineLogger.Errorw("Latest version has no files at all!", zap.String("version_id", selected.Version.ID))
+		return nil, errors.New("no files found for version")
 	}
+
+	return &projectPlan{Project: p, Version: selected.Version, File: selected.File, Probed: selected.Probed, BaseDir: projectBaseDir(cfg, p.ProjectType), Existing: existing}, nil
 }
 
 func shouldProcessProject(p modrinth.Project, cfg *config.Config, goroutineLogger *zap.SugaredLogger) bool {
@@ -158,11 +301,16 @@ func shouldProcessProject(p modrinth.Project, cfg *config.Config, goroutineLogge
 		)
 		return false
 	}
+	if p.ProjectType == "datapack" && len(cfg.DatapackDirs()) == 0 {
+		goroutineLogger.Infow(ui.Colorize("Skipping datapack, no worlds configured in DATAPACK_WORLDS", p.Color))
+		return false
+	}
 	return true
 }
 
-func handleExistingMod(p modrinth.Project, existingMod db.Mod, latestVersion modrinth.Version, primaryFile *modrinth.File, projectBaseDir string, cfg *config.Config, client *modrinth.Client, forceUpdate bool, sendMsg func(UpdateProgressMsg), updatedCount *atomic.Int64, goroutineLogger *zap.SugaredLogger) {
-	oldFilePath := filepath.Join(projectBaseDir, existingMod.FileName)
+func handleExistingMod(p modrinth.Project, existingMod db.Mod, selected versionSelection, projectBaseDir string, cfg *config.Config, client *modrinth.Client, forceUpdate bool, sendMsg func(UpdateProgressMsg), updatedCount *atomic.Int64, goroutineLogger *zap.SugaredLogger) {
+	latestVersion, primaryFile := selected.Version, selected.File
+	oldFilePath := filepath.Join(projectBaseDir, installedFileName(existingMod))
 	fileMissing := false
 	if _, err := os.Stat(oldFilePath); os.IsNotExist(err) {
 		fileMissing = true
@@ -201,11 +349,18 @@ func handleExistingMod(p modrinth.Project, existingMod db.Mod, latestVersion mod
 
 	downloadPath := filepath.Join(projectBaseDir, primaryFile.Filename)
 	goroutineLogger.Infow(ui.Colorize("Downloading file...", p.Color), zap.String("file", primaryFile.Filename))
-	if err := client.DownloadModFile(goroutineLogger, downloadPath, primaryFile.URL); err != nil {
+	if err := installSelectedFile(client, selected, downloadPath, goroutineLogger); err != nil {
 		goroutineLogger.Errorw("Failed to download mod", zap.String("filename", primaryFile.Filename), zap.Error(err))
 		sendMsg(UpdateProgressMsg{Type: "error", ProjectName: p.Title, Message: "Download failed"})
 		return
 	}
+	if existingMod.Disabled {
+		if err := keepDisabled(existingMod, downloadPath); err != nil {
+			goroutineLogger.Warnw("Failed to keep updated mod disabled", zap.String("file", downloadPath), zap.Error(err))
+		}
+	} else if p.ProjectType == "datapack" {
+		syncDatapackCopies(cfg, downloadPath, existingMod.FileName, goroutineLogger)
+	}
 
 	updatedTime, _ := time.Parse(time.RFC3339Nano, p.Updated)
 	existingMod.VersionID = latestVersion.ID
@@ -214,9 +369,13 @@ func handleExistingMod(p modrinth.Project, existingMod db.Mod, latestVersion mod
 	existingMod.InstallPath = downloadPath
 	existingMod.ProjectID = p.ID
 	existingMod.Title = p.Title
+	existingMod.ProjectType = p.ProjectType
+	existingMod.ClientSide = p.ClientSide
+	existingMod.ServerSide = p.ServerSide
 	existingMod.IconURL = p.IconURL
 	existingMod.Color = p.Color
 	existingMod.Updated = updatedTime
+	applyFileMetadata(&existingMod, cfg.MinecraftLoader)
 
 	if err := db.DB.Save(&existingMod).Error; err != nil {
 		goroutineLogger.Warnw("Failed to update database record", zap.Error(err))
@@ -225,7 +384,8 @@ func handleExistingMod(p modrinth.Project, existingMod db.Mod, latestVersion mod
 	sendMsg(UpdateProgressMsg{Type: "download_success", ProjectName: p.Title, Version: latestVersion.VersionNumber})
 }
 
-func handleNewMod(p modrinth.Project, latestVersion modrinth.Version, primaryFile *modrinth.File, projectBaseDir string, client *modrinth.Client, sendMsg func(UpdateProgressMsg), downloadedCount *atomic.Int64, goroutineLogger *zap.SugaredLogger) {
+func handleNewMod(p modrinth.Project, selected versionSelection, projectBaseDir string, cfg *config.Config, client *modrinth.Client, sendMsg func(UpdateProgressMsg), downloadedCount *atomic.Int64, goroutineLogger *zap.SugaredLogger) {
+	latestVersion, primaryFile := selected.Version, selected.File
 	goroutineLogger.Infow(ui.Colorize("New project found - downloading", p.Color), zap.String("version", latestVersion.VersionNumber))
 
 	sendMsg(UpdateProgressMsg{
@@ -236,17 +396,23 @@ func handleNewMod(p modrinth.Project, latestVersion modrinth.Version, primaryFil
 	})
 
 	downloadPath := filepath.Join(projectBaseDir, primaryFile.Filename)
-	if err := client.DownloadModFile(goroutineLogger, downloadPath, primaryFile.URL); err != nil {
+	if err := installSelectedFile(client, selected, downloadPath, goroutineLogger); err != nil {
 		goroutineLogger.Errorw("Failed to download file", zap.String("filename", primaryFile.Filename), zap.Error(err))
 		sendMsg(UpdateProgressMsg{Type: "error", ProjectName: p.Title, Message: "Download failed"})
 		return
 	}
+	if p.ProjectType == "datapack" {
+		syncDatapackCopies(cfg, downloadPath, "", goroutineLogger)
+	}
 
 	updatedTime, _ := time.Parse(time.RFC3339Nano, p.Updated)
 	newMod := db.Mod{
 		ProjectSlug:   p.Slug,
 		ProjectID:     p.ID,
 		Title:         p.Title,
+		ProjectType:   p.ProjectType,
+		ClientSide:    p.ClientSide,
+		ServerSide:    p.ServerSide,
 		IconURL:       p.IconURL,
 		Color:         p.Color,
 		Updated:       updatedTime,
@@ -254,7 +420,9 @@ func handleNewMod(p modrinth.Project, latestVersion modrinth.Version, primaryFil
 		VersionNumber: latestVersion.VersionNumber,
 		FileName:      primaryFile.Filename,
 		InstallPath:   downloadPath,
+		Followed:      true,
 	}
+	applyFileMetadata(&newMod, cfg.MinecraftLoader)
 
 	if err := db.DB.Create(&newMod).Error; err != nil {
 		goroutineLogger.Warnw("Failed to save mod to database", zap.Error(err))
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/modrinth"
 )
 
 // TestOldVersionRemoval tests that old mod files are removed when KeepOldVersions is false
@@ -289,3 +290,18 @@ func TestConcurrentModUpdates(t *testing.T) {
 		}
 	}
 }
+
+func TestPolicyUnfollowed(t *testing.T) {
+	followed := []modrinth.Project{{ID: "AANobbMI"}}
+	mods := []db.Mod{
+		{ProjectSlug: "sodium", Provider: "modrinth", ProjectID: "AANobbMI", Followed: true},
+		{ProjectSlug: "lithium", Provider: "modrinth", ProjectID: "gvQqBUqZ", Followed: true},
+		// Imported on bootstrap and never followed, so archive and remove must not touch it.
+		{ProjectSlug: "imported", Provider: "modrinth", ProjectID: "P7dR8mSH"},
+	}
+
+	got := policyUnfollowed(mods, followed)
+	if len(got) != 1 || got[0].ProjectSlug != "lithium" {
+		t.Errorf("policyUnfollowed() = %+v, want only lithium", got)
+	}
+}
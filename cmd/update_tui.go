@@ -3,6 +3,8 @@ package cmd
 import (
 	"fmt"
 
+	"modrinth-mod-updater/config"
+
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -23,6 +25,7 @@ type UpdateModel struct {
 	spinner      spinner.Model
 	progressChan chan UpdateProgressMsg
 	forceUpdate  bool
+	cfg          *config.Config // Configuration loaded by the update, set once it is done
 
 	// State
 	status      string
@@ -48,6 +51,7 @@ func initialUpdateModel(forceUpdate bool) UpdateModel {
 		spinner:      s,
 		progressChan: make(chan UpdateProgressMsg, 100), // Buffer slightly to avoid blocking
 		forceUpdate:  forceUpdate,
+		cfg:          &config.Config{},
 		status:       "Initializing...",
 		checking:     []string{},
 		downloading:  []string{},
@@ -69,7 +73,7 @@ func (m UpdateModel) startUpdate() tea.Cmd {
 		// Run update in a separate goroutine
 		go func() {
 			defer close(m.progressChan)
-			runUpdate(m.forceUpdate, m.progressChan)
+			*m.cfg = runUpdate(m.forceUpdate, m.progressChan)
 		}()
 		return nil
 	}
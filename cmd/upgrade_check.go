@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/logger"
+	"modrinth-mod-updater/modrinth"
+	"modrinth-mod-updater/provider"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// Upgrade readiness of a project.
+const (
+	upgradeReady   = "ready"   // A compatible build exists and its required dependencies have one too
+	upgradeBlocked = "blocked" // A compatible build exists, but a required dependency has none
+	upgradeMissing = "missing" // No compatible build exists
+	upgradeUnknown = "unknown" // The versions could not be fetched
+)
+
+// upgradeEntry is the readiness of one project for a target Minecraft version.
+type upgradeEntry struct {
+	Slug      string    `json:"slug"`
+	ProjectID string    `json:"project_id,omitempty"`
+	Title     string    `json:"title"`
+	Provider  string    `json:"provider"`
+	Status    string    `json:"status"`
+	Version   string    `json:"version,omitempty"`
+	VersionID string    `json:"version_id,omitempty"`
+	Published time.Time `json:"published,omitzero"`
+	BlockedBy []string  `json:"blocked_by,omitempty"` // Titles of required dependencies without a compatible build
+	Error     string    `json:"error,omitempty"`
+
+	requires  []string          // Project IDs of the compatible build's required dependencies
+	project   *modrinth.Project // Checked Modrinth project, nil for other providers
+	sourceMod *db.Mod           // Checked mod from another provider
+}
+
+// upgradeCheckCmd represents the upgrade-check command
+var upgradeCheckCmd = &cobra.Command{
+	Use:   "upgrade-check <target-version>",
+	Short: "Report which projects have builds for another Minecraft version",
+	Long: `Check every followed project, installed mod and tracked source for a build compatible with the
+target Minecraft version, and report the newest compatible version and its publish date.
+
+Projects whose compatible build requires a dependency without one are reported as blocked.
+Nothing is downloaded. Exits with status 1 when any project is not ready for the target version.
+
+Example: modrinth-mod-updater upgrade-check 1.21.5
+Example: modrinth-mod-updater upgrade-check 1.21.5 --loader neoforge --json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		loader, _ := cmd.Flags().GetString("loader")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		cfg, client := bootstrapLocal(".")
+		target := upgradeConfig(&cfg, args[0], loader)
+		entries := checkUpgradeReadiness(&target, client)
+		if err := reportUpgradeReadiness(os.Stdout, entries, &target, asJSON); err != nil {
+			logger.Log.Fatalw("Failed to write report", zap.Error(err))
+		}
+		if slices.ContainsFunc(entries, func(e upgradeEntry) bool { return e.Status != upgradeReady }) {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(upgradeCheckCmd)
+
+	upgradeCheckCmd.Flags().String("loader", "", "Loader to check against (default: MINECRAFT_LOADER)")
+	upgradeCheckCmd.Flags().Bool("json", false, "Print the report as JSON")
+}
+
+// upgradeConfig returns a copy of the configuration targeting another Minecraft version and optionally
+// another loader. Fallback game versions are dropped, so only exact builds count.
+func upgradeConfig(cfg *config.Config, version, loader string) config.Config {
+	target := *cfg
+	target.MinecraftVersion = version
+	if loader != "" {
+		target.MinecraftLoader = loader
+	}
+	target.MinecraftVersionFallback = ""
+	target.GameVersionFallbacks = nil
+	return target
+}
+
+// checkUpgradeReadiness checks every followed project, installed Modrinth mod and tracked source
+// against the target configuration.
+func checkUpgradeReadiness(cfg *config.Config, client *modrinth.Client) []upgradeEntry {
+	projects, err := upgradeProjects(cfg, client)
+	if err != nil {
+		logger.Log.Fatalw("Failed to get projects", zap.Error(err))
+	}
+	projects = slices.DeleteFunc(projects, func(p modrinth.Project) bool {
+		return !isSupportedProjectType(p.ProjectType) || !projectSupportsInstallationType(p, cfg.MinecraftInstallationType)
+	})
+	entries := checkProjectUpgrades(cfg, client, projects)
+
+	var sourceMods []db.Mod
+	if err := db.DB.Where("provider <> ?", provider.ModrinthName).Find(&sourceMods).Error; err != nil {
+		logger.Log.Warnw("Failed to load mods from other providers", zap.Error(err))
+	}
+	providers := providersByName(newProviders(cfg, client))
+	for _, mod := range sourceMods {
+		if p, ok := providers[mod.Provider]; ok {
+			entries = append(entries, checkSourceUpgrade(mod, p, cfg))
+		}
+	}
+
+	applyDependencyBlockers(entries, dependencyUpgrades(cfg, client, entries))
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Title < entries[j].Title })
+	return entries
+}
+
+// upgradeProjects returns the followed projects together with installed Modrinth mods that are no longer followed.
+func upgradeProjects(cfg *config.Config, client *modrinth.Client) ([]modrinth.Project, error) {
+	projects, err := followedProjects(cfg, client)
+	if err != nil {
+		return nil, err
+	}
+	var mods []db.Mod
+	if err := db.DB.Where("provider = ?", provider.ModrinthName).Find(&mods).Error; err != nil {
+		return nil, fmt.Errorf("failed to load installed mods: %w", err)
+	}
+	var ids []string
+	for _, mod := range mods {
+		if mod.ProjectID != "" && !slices.ContainsFunc(projects, func(p modrinth.Project) bool { return p.ID == mod.ProjectID }) {
+			ids = append(ids, mod.ProjectID)
+		}
+	}
+	installed, err := client.GetProjects(ids)
+	if err != nil {
+		return nil, err
+	}
+	return append(projects, installed...), nil
+}
+
+// checkProjectUpgrades checks the given Modrinth projects concurrently.
+func checkProjectUpgrades(cfg *config.Config, client *modrinth.Client, projects []modrinth.Project) []upgradeEntry {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		entries []upgradeEntry
+	)
+	for _, project := range projects {
+		wg.Add(1)
+		go func(p modrinth.Project) {
+			defer wg.Done()
+			entry := checkProjectUpgrade(cfg, client, p)
+			mu.Lock()
+			defer mu.Unlock()
+			entries = append(entries, entry)
+		}(project)
+	}
+	wg.Wait()
+	return entries
+}
+
+func checkProjectUpgrade(cfg *config.Config, client *modrinth.Client, p modrinth.Project) upgradeEntry {
+	entry := upgradeEntry{Slug: p.Slug, ProjectID: p.ID, Title: p.Title, Provider: provider.ModrinthName, project: &p}
+	versions, err := fetchCompatibleVersions(client, cfg, p.Slug, effectiveProjectType(p, cfg))
+	if err != nil {
+		logger.Log.Warnw("Failed to get project versions", zap.String("project_slug", p.Slug), zap.Error(err))
+		entry.Status, entry.Error = upgradeUnknown, err.Error()
+		return entry
+	}
+	if len(versions) == 0 {
+		entry.Status = upgradeMissing
+		return entry
+	}
+
+	latest := versions[0]
+	entry.Status = upgradeReady
+	entry.Version, entry.VersionID = latest.VersionNumber, latest.ID
+	entry.Published, _ = time.Parse(time.RFC3339Nano, latest.DatePublished)
+	for _, dep := range latest.Dependencies {
+		if dep.DependencyType == "required" && dep.ProjectID != "" {
+			entry.requires = append(entry.requires, dep.ProjectID)
+		}
+	}
+	return entry
+}
+
+func checkSourceUpgrade(mod db.Mod, p provider.Provider, cfg *config.Config) upgradeEntry {
+	entry := upgradeEntry{Slug: mod.ProjectSlug, ProjectID: mod.ProjectID, Title: mod.Title, Provider: mod.Provider, sourceMod: &mod}
+	plan, err := planProviderUpdate(mod, p, cfg)
+	switch {
+	case err != nil:
+		logger.Log.Warnw("Failed to get project versions", zap.String("project_slug", mod.ProjectSlug), zap.Error(err))
+		entry.Status, entry.Error = upgradeUnknown, err.Error()
+	case plan == nil:
+		entry.Status = upgradeMissing
+	default:
+		entry.Status = upgradeReady
+		entry.Version, entry.VersionID, entry.Published = plan.Version.VersionNumber, plan.Version.ID, plan.Version.Published
+	}
+	return entry
+}
+
+// dependencyUpgrades checks the required dependencies of ready projects that are not checked already,
+// keyed by project ID.
+func dependencyUpgrades(cfg *config.Config, client *modrinth.Client, entries []upgradeEntry) map[string]upgradeEntry {
+	checked := make(map[string]upgradeEntry)
+	for _, e := range entries {
+		if e.ProjectID != "" {
+			checked[e.ProjectID] = e
+		}
+	}
+
+	var missing []string
+	for _, e := range entries {
+		for _, id := range e.requires {
+			if _, ok := checked[id]; !ok && !slices.Contains(missing, id) {
+				missing = append(missing, id)
+			}
+		}
+	}
+	projects, err := client.GetProjects(missing)
+	if err != nil {
+		logger.Log.Warnw("Failed to get dependency projects", zap.Error(err))
+		return checked
+	}
+	for _, e := range checkProjectUpgrades(cfg, client, projects) {
+		checked[e.ProjectID] = e
+	}
+	return checked
+}
+
+// applyDependencyBlockers marks ready entries as blocked when a required dependency has no compatible build.
+func applyDependencyBlockers(entries []upgradeEntry, dependencies map[string]upgradeEntry) {
+	for i := range entries {
+		e := &entries[i]
+		if e.Status != upgradeReady {
+			continue
+		}
+		for _, id := range e.requires {
+			if dep, ok := dependencies[id]; ok && dep.Status == upgradeMissing {
+				e.BlockedBy = append(e.BlockedBy, dep.Title)
+			}
+		}
+		if len(e.BlockedBy) > 0 {
+			e.Status = upgradeBlocked
+		}
+	}
+}
+
+// reportUpgradeReadiness writes the readiness report as a table with a summary line, or as JSON.
+func reportUpgradeReadiness(out io.Writer, entries []upgradeEntry, cfg *config.Config, asJSON bool) error {
+	if asJSON {
+		if entries == nil {
+			entries = []upgradeEntry{}
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROJECT\tSTATUS\tVERSION\tPUBLISHED\tNOTES")
+	counts := make(map[string]int)
+	for _, e := range entries {
+		counts[e.Status]++
+		published := "-"
+		if !e.Published.IsZero() {
+			published = e.Published.Format(time.DateOnly)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.Title, e.Status, valueOr(e.Version, "-"), published, upgradeNotes(e, cfg))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(out, "\n%d of %d projects are ready for Minecraft %s (%s): %d blocked, %d missing, %d unknown.\n",
+		counts[upgradeReady], len(entries), cfg.MinecraftVersion, cfg.MinecraftLoader,
+		counts[upgradeBlocked], counts[upgradeMissing], counts[upgradeUnknown])
+	return err
+}
+
+func upgradeNotes(e upgradeEntry, cfg *config.Config) string {
+	switch e.Status {
+	case upgradeBlocked:
+		return "requires " + strings.Join(e.BlockedBy, ", ")
+	case upgradeMissing:
+		return "no build for " + cfg.MinecraftVersion
+	case upgradeUnknown:
+		return e.Error
+	}
+	return ""
+}
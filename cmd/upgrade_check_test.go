@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"modrinth-mod-updater/config"
+)
+
+func TestUpgradeConfig(t *testing.T) {
+	cfg := &config.Config{
+		MinecraftVersion:         "1.21.4",
+		MinecraftLoader:          "fabric",
+		MinecraftVersionFallback: "1.21.x",
+		GameVersionFallbacks:     []string{"1.21.3"},
+	}
+
+	target := upgradeConfig(cfg, "1.21.5", "")
+	if target.MinecraftVersion != "1.21.5" || target.MinecraftLoader != "fabric" || target.GameVersionFallbacks != nil || target.MinecraftVersionFallback != "" {
+		t.Errorf("upgradeConfig(1.21.5, \"\") = %+v", target)
+	}
+	if target := upgradeConfig(cfg, "1.21.5", "neoforge"); target.MinecraftLoader != "neoforge" {
+		t.Errorf("MinecraftLoader = %q, want neoforge", target.MinecraftLoader)
+	}
+	if cfg.MinecraftVersion != "1.21.4" {
+		t.Errorf("upgradeConfig modified the original configuration")
+	}
+}
+
+func TestApplyDependencyBlockers(t *testing.T) {
+	entries := []upgradeEntry{
+		{Title: "Sodium Extra", ProjectID: "extra", Status: upgradeReady, requires: []string{"sodium"}},
+		{Title: "Sodium", ProjectID: "sodium", Status: upgradeReady},
+		{Title: "Create Addon", ProjectID: "addon", Status: upgradeReady, requires: []string{"create", "api"}},
+		{Title: "Lithium", ProjectID: "lithium", Status: upgradeMissing, requires: []string{"create"}},
+	}
+	dependencies := map[string]upgradeEntry{
+		"sodium": entries[1],
+		"create": {Title: "Create", Status: upgradeMissing},
+		"api":    {Title: "Fabric API", Status: upgradeReady},
+	}
+
+	applyDependencyBlockers(entries, dependencies)
+
+	want := []struct {
+		status    string
+		blockedBy []string
+	}{
+		{upgradeReady, nil},
+		{upgradeReady, nil},
+		{upgradeBlocked, []string{"Create"}},
+		{upgradeMissing, nil},
+	}
+	for i, w := range want {
+		if entries[i].Status != w.status || !slices.Equal(entries[i].BlockedBy, w.blockedBy) {
+			t.Errorf("%s: status = %q, blocked by %v, want %q, %v", entries[i].Title, entries[i].Status, entries[i].BlockedBy, w.status, w.blockedBy)
+		}
+	}
+}
+
+func TestReportUpgradeReadiness(t *testing.T) {
+	cfg := &config.Config{MinecraftVersion: "1.21.5", MinecraftLoader: "fabric"}
+	entries := []upgradeEntry{
+		{Title: "Sodium", Status: upgradeReady, Version: "0.6.13"},
+		{Title: "Create Addon", Status: upgradeBlocked, Version: "1.2", BlockedBy: []string{"Create"}},
+		{Title: "Lithium", Status: upgradeMissing},
+	}
+
+	var buf bytes.Buffer
+	if err := reportUpgradeReadiness(&buf, entries, cfg, false); err != nil {
+		t.Fatalf("reportUpgradeReadiness() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"requires Create", "no build for 1.21.5", "1 of 3 projects are ready for Minecraft 1.21.5 (fabric): 1 blocked, 1 missing, 0 unknown."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report does not contain %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := reportUpgradeReadiness(&buf, nil, cfg, true); err != nil {
+		t.Fatalf("reportUpgradeReadiness(json) error = %v", err)
+	}
+	var decoded []upgradeEntry
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded == nil {
+		t.Errorf("JSON report = %q, want an empty array", buf.String())
+	}
+}
@@ -0,0 +1,521 @@
+package cmd
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/logger"
+	"modrinth-mod-updater/modrinth"
+	"modrinth-mod-updater/provider"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// Kinds of integrity problems found by verify.
+const (
+	integrityMissing    = "missing"    // A tracked file or archive is gone
+	integrityCorrupted  = "corrupted"  // The file cannot be read as an archive
+	integrityModified   = "modified"   // The file is readable, but not the recorded one
+	integrityUnexpected = "unexpected" // A file in a managed or versions directory that is not tracked
+)
+
+// checkedFile is a file verify checks: the file of a tracked mod, a copy of a datapack in another world,
+// or an archived version.
+type checkedFile struct {
+	Path      string
+	Title     string
+	Hash      string // Recorded SHA-1, empty for records from before hashes were stored
+	VersionID string
+	Modrinth  bool           // Whether VersionID is a Modrinth version, whose hashes Modrinth knows
+	Mod       *db.Mod        // Tracked mod the file belongs to, nil for archives of forgotten mods
+	Archive   *db.ModVersion // Archived version, nil for installed files
+	Copy      bool           // Whether the file is a copy of a datapack in another world
+}
+
+// integrityIssue is a problem verify found with a file.
+type integrityIssue struct {
+	checkedFile
+	Kind   string
+	Detail string
+}
+
+func (i integrityIssue) label() string {
+	name := valueOr(i.Title, filepath.Base(i.Path))
+	switch {
+	case i.Archive != nil:
+		name += " (archived " + i.Archive.VersionNumber + ")"
+	case i.Copy:
+		name += " (copy in " + filepath.Base(filepath.Dir(filepath.Dir(i.Path))) + ")"
+	}
+	return fmt.Sprintf("%-10s %s", i.Kind, name)
+}
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check installed and archived files against their recorded hashes",
+	Long: `Hash every tracked file, datapack copy and archived version, and compare it with the hash recorded
+when it was installed and with the files Modrinth knows for the recorded version. Reports files that are:
+
+  missing     gone from disk
+  corrupted   not readable as a jar or zip archive
+  modified    readable, but not the recorded file
+  unexpected  in a managed or versions directory without being tracked
+
+Files recorded before hashes were stored get their hash recorded once they check out. The directories
+are not scanned for new files first, so unknown files show up as unexpected.
+
+Exits with status 1 when a problem is found. Run repair to fix them.
+
+Example: modrinth-mod-updater verify`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		cfg, client := bootstrapLocal(".")
+		issues, checked := verifyInstallation(&cfg, client)
+		for _, i := range issues {
+			mark := "✗"
+			if i.Kind == integrityUnexpected {
+				mark = "!"
+			}
+			detail := ""
+			if i.Detail != "" {
+				detail = " (" + i.Detail + ")"
+			}
+			fmt.Printf("  %s %s  %s%s\n", mark, i.label(), i.Path, detail)
+		}
+		if len(issues) == 0 {
+			fmt.Printf("All %d files are intact.\n", checked)
+			return
+		}
+		fmt.Printf("Checked %d files, found %d problem(s). Run repair to fix them.\n", checked, len(issues))
+		os.Exit(1)
+	},
+}
+
+// repairCmd represents the repair command
+var repairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Restore the files verify reports as missing, modified or corrupted",
+	Long: `Run verify and restore the files it flags. A tracked file is copied back from an archived copy of the
+same version when one is intact, and downloaded again from its provider otherwise. Datapack copies are
+copied from the installed datapack, and archives are downloaded again. The database records are updated
+to match the restored files; missing archives that cannot be downloaded again are removed from the history.
+
+The files are shown as a checklist first (skipped with --yes). Unexpected files are left alone, see
+prune --only untracked.
+
+Example: modrinth-mod-updater repair
+Example: modrinth-mod-updater repair --yes`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		assumeYes, _ := cmd.Flags().GetBool("yes")
+		repairInstallation(assumeYes)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(repairCmd)
+
+	repairCmd.Flags().BoolP("yes", "y", false, "Repair every flagged file without confirmation")
+}
+
+// verifyInstallation checks the tracked files and archives and the managed directories. It returns the
+// problems found and the number of files checked.
+func verifyInstallation(cfg *config.Config, client *modrinth.Client) ([]integrityIssue, int) {
+	var mods []db.Mod
+	if err := db.DB.Find(&mods).Error; err != nil {
+		logger.Log.Fatalw("Failed to load mods from database", zap.Error(err))
+	}
+	// Rollback deletes history records but leaves their archives, so deleted records still claim their files.
+	var archives []db.ModVersion
+	if err := db.DB.Unscoped().Find(&archives).Error; err != nil {
+		logger.Log.Fatalw("Failed to load version history from database", zap.Error(err))
+	}
+
+	files := checkedFiles(cfg, mods, archives)
+	issues := verifyFiles(client, files)
+	return append(issues, unexpectedFiles(cfg, mods, archives)...), len(files)
+}
+
+// checkedFiles lists the files verify checks, each datapack copy right after its datapack.
+func checkedFiles(cfg *config.Config, mods []db.Mod, archives []db.ModVersion) []checkedFile {
+	var files []checkedFile
+	bySlug := make(map[string]*db.Mod)
+	for i := range mods {
+		mod := &mods[i]
+		if mod.InstallPath == "" {
+			continue
+		}
+		bySlug[mod.ProjectSlug] = mod
+		f := checkedFile{
+			Path: installedFilePath(*mod), Title: mod.Title, Hash: mod.FileHash, VersionID: mod.VersionID, Modrinth: isModrinthMod(*mod), Mod: mod,
+		}
+		files = append(files, f)
+		if mod.ProjectType != "datapack" || mod.Disabled {
+			continue
+		}
+		for _, dir := range cfg.DatapackDirs() {
+			if path := filepath.Join(dir, mod.FileName); path != mod.InstallPath {
+				c := f
+				c.Path, c.Copy = path, true
+				files = append(files, c)
+			}
+		}
+	}
+
+	for i := range archives {
+		a := &archives[i]
+		if a.ArchivePath == "" || a.DeletedAt.Valid {
+			continue
+		}
+		f := checkedFile{Path: a.ArchivePath, Title: a.ProjectSlug, Hash: a.FileHash, VersionID: a.VersionID, Modrinth: true, Archive: a}
+		if mod := bySlug[a.ProjectSlug]; mod != nil {
+			f.Title, f.Modrinth, f.Mod = mod.Title, isModrinthMod(*mod), mod
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// verifyFiles hashes the files and classifies them, looking up the hashes that differ from the recorded
+// ones on Modrinth in a single request.
+func verifyFiles(client *modrinth.Client, files []checkedFile) []integrityIssue {
+	hashes := make([]string, len(files))
+	errs := make([]error, len(files))
+	var lookup []string
+	for i, f := range files {
+		hashes[i], errs[i] = calculateSHA1(f.Path)
+		if errs[i] == nil && hashes[i] != f.Hash && f.Modrinth {
+			lookup = append(lookup, hashes[i])
+		}
+	}
+	known, err := client.GetVersionsByHashes(lookup, "sha1")
+	if err != nil {
+		logger.Log.Warnw("Failed to look up file hashes on Modrinth, comparing with the recorded hashes only", zap.Error(err))
+	}
+
+	var issues []integrityIssue
+	for i, f := range files {
+		switch {
+		case errors.Is(errs[i], os.ErrNotExist):
+			issues = append(issues, integrityIssue{checkedFile: f, Kind: integrityMissing})
+			continue
+		case errs[i] != nil:
+			issues = append(issues, integrityIssue{checkedFile: f, Kind: integrityCorrupted, Detail: errs[i].Error()})
+			continue
+		}
+		kind, detail := classifyFile(f, hashes[i], known)
+		if kind != "" {
+			issues = append(issues, integrityIssue{checkedFile: f, Kind: kind, Detail: detail})
+			continue
+		}
+		if v, ok := known[hashes[i]]; f.Hash == "" && !f.Copy && (!f.Modrinth || (ok && v.ID == f.VersionID)) {
+			recordVerifiedHash(f, hashes[i])
+		}
+	}
+	return issues
+}
+
+// classifyFile compares a file's hash with the recorded one and, for Modrinth files, with the versions
+// Modrinth knows for it. It returns an empty kind for intact files, and for files recorded without a
+// hash that cannot be compared with anything.
+func classifyFile(f checkedFile, hash string, known map[string]modrinth.Version) (kind, detail string) {
+	if f.Hash != "" && hash == f.Hash {
+		return "", ""
+	}
+	v, isKnown := known[hash]
+	if isKnown && v.ID == f.VersionID {
+		return "", ""
+	}
+	if !readableArchive(f.Path) {
+		return integrityCorrupted, "not a readable archive"
+	}
+	switch {
+	case isKnown:
+		return integrityModified, "is version " + v.VersionNumber + " on Modrinth"
+	case f.Hash == "" && (!f.Modrinth || known == nil):
+		return "", ""
+	case f.Hash == "":
+		return integrityModified, "not a file of the recorded version"
+	default:
+		return integrityModified, "differs from the installed file"
+	}
+}
+
+// readableArchive reports whether a jar or zip file can be read completely, which checks the CRC of every
+// entry. Other files are not archives and count as readable.
+func readableArchive(path string) bool {
+	ext := strings.ToLower(filepath.Ext(strings.TrimSuffix(path, disabledSuffix)))
+	if ext != ".jar" && ext != ".zip" {
+		return true
+	}
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return false
+	}
+	defer r.Close()
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			return false
+		}
+		_, err = io.Copy(io.Discard, rc)
+		rc.Close()
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// recordVerifiedHash stores the hash of a file recorded before hashes were stored.
+func recordVerifiedHash(f checkedFile, hash string) {
+	var err error
+	if f.Archive != nil {
+		f.Archive.FileHash = hash
+		err = db.DB.Model(f.Archive).Update("file_hash", hash).Error
+	} else {
+		f.Mod.FileHash = hash
+		err = db.DB.Model(f.Mod).Update("file_hash", hash).Error
+	}
+	if err != nil {
+		logger.Log.Warnw("Failed to record file hash", zap.String("path", f.Path), zap.Error(err))
+	}
+}
+
+// unexpectedFiles returns the files in the managed directories and their versions directories that no
+// mod or history record claims.
+func unexpectedFiles(cfg *config.Config, mods []db.Mod, archives []db.ModVersion) []integrityIssue {
+	tracked := trackedPaths(cfg, mods)
+	for _, a := range archives {
+		if a.ArchivePath != "" {
+			tracked[a.ArchivePath] = true
+		}
+	}
+
+	var issues []integrityIssue
+	for _, dir := range managedDirs(cfg) {
+		for _, path := range append(managedFiles(dir), managedFiles(filepath.Join(dir, "versions"))...) {
+			if !tracked[path] {
+				issues = append(issues, integrityIssue{checkedFile: checkedFile{Path: path}, Kind: integrityUnexpected})
+			}
+		}
+	}
+	return issues
+}
+
+func repairInstallation(assumeYes bool) {
+	cfg, client := bootstrapLocal(".")
+	issues, _ := verifyInstallation(&cfg, client)
+
+	var repairable []integrityIssue
+	for _, i := range issues {
+		if i.Kind != integrityUnexpected {
+			repairable = append(repairable, i)
+		}
+	}
+	if unexpected := len(issues) - len(repairable); unexpected > 0 {
+		fmt.Printf("Leaving %d unexpected file(s) alone, see prune --only untracked.\n", unexpected)
+	}
+	if len(repairable) == 0 {
+		fmt.Println("Nothing to repair.")
+		return
+	}
+
+	items := make([]checklistItem, len(repairable))
+	for i, issue := range repairable {
+		items[i] = checklistItem{Label: issue.label(), Checked: true}
+	}
+	selected, err := confirmChecklist("Select the files to repair", items, assumeYes)
+	if err != nil {
+		logger.Log.Fatalw("Failed to run confirmation UI", zap.Error(err))
+	}
+
+	repaired := 0
+	for _, i := range selected {
+		log := logger.Log.With(zap.String("kind", repairable[i].Kind), zap.String("path", repairable[i].Path))
+		if err := repairFile(&cfg, client, repairable[i], log); err != nil {
+			log.Errorw("Failed to repair file", zap.Error(err))
+			fmt.Printf("  ✗ %s: %v\n", items[i].Label, err)
+			continue
+		}
+		fmt.Printf("  ✓ %s\n", items[i].Label)
+		repaired++
+	}
+	fmt.Printf("Repaired %d of %d.\n", repaired, len(repairable))
+}
+
+func repairFile(cfg *config.Config, client *modrinth.Client, issue integrityIssue, log *zap.SugaredLogger) error {
+	switch {
+	case issue.Archive != nil:
+		return repairArchive(cfg, client, issue, log)
+	case issue.Copy:
+		return repairDatapackCopy(*issue.Mod, issue.Path)
+	default:
+		return repairModFile(cfg, client, issue.Mod, log)
+	}
+}
+
+// repairModFile restores a tracked file from an intact archive of the same version, or downloads it again.
+func repairModFile(cfg *config.Config, client *modrinth.Client, mod *db.Mod, log *zap.SugaredLogger) error {
+	archivePath := intactArchive(*mod)
+	if archivePath == "" {
+		return downloadRecordedVersion(cfg, client, mod, log)
+	}
+	if err := copyFile(archivePath, installedFilePath(*mod)); err != nil {
+		return err
+	}
+	log.Infow("Restored file from archive", zap.String("archive_path", archivePath))
+	applyFileMetadata(mod, cfg.MinecraftLoader)
+	if mod.ProjectType == "datapack" && !mod.Disabled {
+		syncDatapackCopies(cfg, mod.InstallPath, mod.FileName, log)
+	}
+	return db.DB.Save(mod).Error
+}
+
+// intactArchive returns the path of an archived copy of a mod's installed version whose hash matches the
+// recorded one, or an empty string.
+func intactArchive(mod db.Mod) string {
+	if mod.FileHash == "" {
+		return ""
+	}
+	var archives []db.ModVersion
+	if err := db.DB.Where("project_slug = ? AND version_id = ? AND archive_path <> ''", mod.ProjectSlug, mod.VersionID).Find(&archives).Error; err != nil {
+		logger.Log.Warnw("Failed to load version history", zap.String("slug", mod.ProjectSlug), zap.Error(err))
+		return ""
+	}
+	for _, a := range archives {
+		if hash, err := calculateSHA1(a.ArchivePath); err == nil && hash == mod.FileHash {
+			return a.ArchivePath
+		}
+	}
+	return ""
+}
+
+// repairDatapackCopy copies the installed datapack over its copy in another world, once the installed
+// datapack itself is intact.
+func repairDatapackCopy(mod db.Mod, path string) error {
+	if hash, err := calculateSHA1(mod.InstallPath); err != nil || (mod.FileHash != "" && hash != mod.FileHash) {
+		return errors.New("the installed datapack is not intact, repair it first")
+	}
+	return copyFile(mod.InstallPath, path)
+}
+
+// repairArchive downloads an archived version again. A missing archive that cannot be downloaded is
+// removed from the history, so the record matches the disk.
+func repairArchive(cfg *config.Config, client *modrinth.Client, issue integrityIssue, log *zap.SugaredLogger) error {
+	a := issue.Archive
+	rec := recordedFile{VersionID: a.VersionID, FileName: a.FileName}
+	if issue.Mod != nil {
+		rec.Provider, rec.Source = issue.Mod.Provider, issue.Mod.Source
+	}
+	if _, err := downloadRecordedFile(cfg, client, rec, func(string) string { return a.ArchivePath }, log); err != nil {
+		if issue.Kind != integrityMissing {
+			return err
+		}
+		a.ArchivePath = ""
+		if saveErr := db.DB.Save(a).Error; saveErr != nil {
+			return saveErr
+		}
+		return fmt.Errorf("removed the archive from the history, it cannot be downloaded again: %w", err)
+	}
+
+	hash, err := calculateSHA1(a.ArchivePath)
+	if err != nil {
+		return err
+	}
+	a.FileHash = hash
+	return db.DB.Save(a).Error
+}
+
+// downloadRecordedVersion downloads the version a record points to again, replacing its file, and updates
+// the record to match the downloaded file.
+func downloadRecordedVersion(cfg *config.Config, client *modrinth.Client, mod *db.Mod, log *zap.SugaredLogger) error {
+	rec := recordedFile{Provider: mod.Provider, Source: mod.Source, VersionID: mod.VersionID, FileName: mod.FileName}
+	dir := filepath.Dir(mod.InstallPath)
+	target := func(fileName string) string {
+		return installedFilePath(db.Mod{InstallPath: filepath.Join(dir, fileName), Disabled: mod.Disabled})
+	}
+	fileName, err := downloadRecordedFile(cfg, client, rec, target, log)
+	if err != nil {
+		return err
+	}
+	if old := installedFilePath(*mod); old != target(fileName) {
+		if err := os.Remove(old); err != nil && !os.IsNotExist(err) {
+			log.Warnw("Failed to remove replaced file", zap.String("file", old), zap.Error(err))
+		}
+	}
+
+	oldFileName := mod.FileName
+	mod.FileName, mod.InstallPath = fileName, filepath.Join(dir, fileName)
+	applyFileMetadata(mod, cfg.MinecraftLoader)
+	if mod.ProjectType == "datapack" && !mod.Disabled {
+		syncDatapackCopies(cfg, mod.InstallPath, oldFileName, log)
+	}
+	return db.DB.Save(mod).Error
+}
+
+// recordedFile identifies the file of a recorded version at its provider.
+type recordedFile struct {
+	Provider  string // Empty for Modrinth
+	Source    string
+	VersionID string
+	FileName  string // Preferred file when the version has several
+}
+
+// downloadRecordedFile downloads the file of a recorded version to the path target returns for its file
+// name and checks its hashes. It returns the file name.
+func downloadRecordedFile(cfg *config.Config, client *modrinth.Client, rec recordedFile, target func(string) string, log *zap.SugaredLogger) (string, error) {
+	var p provider.Provider
+	var file *provider.File
+	if rec.Provider == "" || rec.Provider == provider.ModrinthName {
+		version, err := client.GetVersion(rec.VersionID)
+		if err != nil {
+			return "", err
+		}
+		f := versionFileByName(*version, rec.FileName)
+		if f == nil {
+			return "", errors.New("no files found for version")
+		}
+		p, file = provider.NewModrinth(client), &provider.File{Filename: f.Filename, URL: f.URL, Hashes: f.Hashes}
+	} else {
+		var ok bool
+		if p, ok = providersByName(newProviders(cfg, client))[rec.Provider]; !ok {
+			return "", fmt.Errorf("cannot download %s files again", rec.Provider)
+		}
+		versions, err := p.ListVersions(rec.Source, provider.Filter{})
+		if err != nil {
+			return "", err
+		}
+		i := slices.IndexFunc(versions, func(v provider.Version) bool { return v.ID == rec.VersionID })
+		if i < 0 {
+			return "", fmt.Errorf("version %s is no longer available", rec.VersionID)
+		}
+		if file, err = p.ResolveFile(versions[i]); err != nil {
+			return "", err
+		}
+	}
+
+	path := target(file.Filename)
+	tmp := path + ".download"
+	if err := p.Download(log, tmp, *file); err != nil {
+		return "", err
+	}
+	if len(file.Hashes) > 0 {
+		if err := verifyFileHashes(tmp, file.Hashes); err != nil {
+			os.Remove(tmp)
+			return "", err
+		}
+	}
+	return file.Filename, os.Rename(tmp, path)
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"modrinth-mod-updater/config"
+	"modrinth-mod-updater/db"
+	"modrinth-mod-updater/modrinth"
+)
+
+func TestClassifyFile(t *testing.T) {
+	dir := t.TempDir()
+	jar := filepath.Join(dir, "sodium.jar")
+	writeTestZip(t, jar, map[string]string{"fabric.mod.json": "{}"})
+	broken := filepath.Join(dir, "broken.jar")
+	if err := os.WriteFile(broken, []byte("not a zip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	known := map[string]modrinth.Version{
+		"current": {ID: "v2", VersionNumber: "0.6.0"},
+		"older":   {ID: "v1", VersionNumber: "0.5.0"},
+	}
+	tests := []struct {
+		name       string
+		file       checkedFile
+		hash       string
+		known      map[string]modrinth.Version
+		wantKind   string
+		wantDetail string
+	}{
+		{"recorded hash", checkedFile{Path: broken, Hash: "abc"}, "abc", nil, "", ""},
+		{"known on Modrinth", checkedFile{Path: jar, VersionID: "v2", Modrinth: true}, "current", known, "", ""},
+		{"unreadable", checkedFile{Path: broken, Hash: "abc"}, "def", known, integrityCorrupted, "not a readable archive"},
+		{"other version", checkedFile{Path: jar, Hash: "abc", VersionID: "v2", Modrinth: true}, "older", known, integrityModified, "is version 0.5.0 on Modrinth"},
+		{"unknown content", checkedFile{Path: jar, VersionID: "v2", Modrinth: true}, "edited", known, integrityModified, "not a file of the recorded version"},
+		{"changed", checkedFile{Path: jar, Hash: "abc"}, "def", nil, integrityModified, "differs from the installed file"},
+		{"nothing to compare", checkedFile{Path: jar}, "def", nil, "", ""},
+		{"lookup failed", checkedFile{Path: jar, Modrinth: true}, "def", nil, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, detail := classifyFile(tt.file, tt.hash, tt.known)
+			if kind != tt.wantKind || detail != tt.wantDetail {
+				t.Errorf("classifyFile() = %q, %q, want %q, %q", kind, detail, tt.wantKind, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestCheckedFiles(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{MinecraftDir: dir, DatapackWorlds: []string{"world", "world_creative"}}
+	mods := []db.Mod{
+		{ProjectSlug: "sodium", Provider: "modrinth", FileName: "sodium.jar", InstallPath: filepath.Join(dir, "mods", "sodium.jar"), Disabled: true},
+		{ProjectSlug: "terralith", Provider: "github", ProjectType: "datapack", FileName: "terralith.zip", InstallPath: filepath.Join(dir, "world", "datapacks", "terralith.zip")},
+	}
+	archives := []db.ModVersion{
+		{ProjectSlug: "sodium", VersionID: "v1", ArchivePath: filepath.Join(dir, "mods", "versions", "v1-sodium.jar")},
+		{ProjectSlug: "sodium", VersionID: "v0"},
+		{ProjectSlug: "gone", VersionID: "v3", ArchivePath: filepath.Join(dir, "mods", "versions", "v3-gone.jar")},
+	}
+
+	var got []string
+	for _, f := range checkedFiles(cfg, mods, archives) {
+		rel, _ := filepath.Rel(dir, f.Path)
+		if f.Modrinth {
+			rel += " (modrinth)"
+		}
+		got = append(got, filepath.ToSlash(rel))
+	}
+	want := []string{
+		"mods/sodium.jar.disabled (modrinth)",
+		"world/datapacks/terralith.zip",
+		"world_creative/datapacks/terralith.zip",
+		"mods/versions/v1-sodium.jar (modrinth)",
+		"mods/versions/v3-gone.jar (modrinth)",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("checkedFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestUnexpectedFiles(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{MinecraftDir: dir}
+	files := []string{
+		"mods/sodium.jar",
+		"mods/manual.jar",
+		"mods/versions/v1-sodium.jar",
+		"mods/versions/v0-rolled-back.jar",
+		"mods/versions/stray.jar",
+		"resourcepacks/notes.txt",
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mods := []db.Mod{{ProjectSlug: "sodium", FileName: "sodium.jar", InstallPath: filepath.Join(dir, "mods", "sodium.jar")}}
+	archives := []db.ModVersion{
+		{ProjectSlug: "sodium", ArchivePath: filepath.Join(dir, "mods", "versions", "v1-sodium.jar")},
+		{ProjectSlug: "sodium", ArchivePath: filepath.Join(dir, "mods", "versions", "v0-rolled-back.jar")},
+	}
+
+	var got []string
+	for _, issue := range unexpectedFiles(cfg, mods, archives) {
+		rel, _ := filepath.Rel(dir, issue.Path)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	want := []string{"mods/manual.jar", "mods/versions/stray.jar"}
+	if !slices.Equal(got, want) {
+		t.Errorf("unexpectedFiles() = %v, want %v", got, want)
+	}
+}
@@ -1,10 +1,13 @@
 package config
 
 import (
+	"bufio"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -15,12 +18,47 @@ type Config struct {
 	MinecraftInstallationType string `mapstructure:"minecraft_installation_type"`
 	MinecraftLoader           string `mapstructure:"minecraft_loader"`
 	MinecraftVersion          string `mapstructure:"minecraft_version"`
+	LoaderVersion             string `mapstructure:"loader_version"`
 	ModrinthAPIKey            string `mapstructure:"modrinth_api_key"`
 	UserAgent                 string `mapstructure:"useragent"`
 	ModrinthUser              string `mapstructure:"modrinth_user"`
 	MinecraftDir              string `mapstructure:"minecraft_dir"`
 	DatabasePath              string `mapstructure:"-"`
 	KeepOldVersions           bool   `mapstructure:"keep_old_versions"`
+
+	// MinecraftVersionFallback is an optional list or range expression (e.g. "1.21.4-1.21.5", "1.21.x")
+	// of game versions that may be used when a project has no build for MinecraftVersion.
+	MinecraftVersionFallback string `mapstructure:"minecraft_version_fallback"`
+	// VersionFallbackProjects lists the project slugs allowed to use the fallback versions ("*" for all).
+	VersionFallbackProjects []string `mapstructure:"version_fallback_projects"`
+	// ShaderLoader selects the shader loader (iris, optifine, canvas, vanilla) used to filter shader versions.
+	ShaderLoader string `mapstructure:"shader_loader"`
+	// ResourcePackFormat constrains resource packs to a pack_format or range of formats (e.g. "34" or "32-34").
+	ResourcePackFormat string `mapstructure:"resourcepack_format"`
+
+	// DatapackWorlds lists the world directories (relative to MinecraftDir or absolute) that receive datapacks.
+	// When empty, the world named by level-name in server.properties is used.
+	DatapackWorlds []string `mapstructure:"datapack_worlds"`
+	// DatapackProjects lists mod slugs that should be installed using their datapack variant.
+	DatapackProjects []string `mapstructure:"datapack_projects"`
+
+	// UnfollowedPolicy sets what update does with installed projects that are no longer followed
+	// (keep, update, archive or remove).
+	UnfollowedPolicy string `mapstructure:"unfollowed_policy"`
+	// CheckAfterUpdate runs the dependency check of the check command at the end of every update.
+	CheckAfterUpdate bool `mapstructure:"check_after_update"`
+
+	// GitHubToken is an optional token for the GitHub Releases provider.
+	GitHubToken string `mapstructure:"github_token"`
+	// GitHubAPIURL overrides the GitHub API base URL (e.g. for GitHub Enterprise).
+	GitHubAPIURL string `mapstructure:"github_api_url"`
+	// CurseForgeAPIKey enables the CurseForge provider.
+	CurseForgeAPIKey string `mapstructure:"curseforge_api_key"`
+	// CurseForgeAPIURL overrides the CurseForge API base URL.
+	CurseForgeAPIURL string `mapstructure:"curseforge_api_url"`
+
+	// GameVersionFallbacks holds the concrete versions MinecraftVersionFallback resolved to.
+	GameVersionFallbacks []string `mapstructure:"-"`
 }
 
 // LoadConfig reads configuration from file and environment variables.
@@ -46,10 +84,21 @@ func LoadConfig(path string) (config Config, err error) {
 
 	processConfigDefaults(&config)
 
+	if err := validateShaderLoader(config.ShaderLoader); err != nil {
+		return Config{}, err
+	}
+	if err := validateUnfollowedPolicy(config.UnfollowedPolicy); err != nil {
+		return Config{}, err
+	}
+
 	if err := validateAndEnsureDirectories(&config); err != nil {
 		return Config{}, err
 	}
 
+	if len(config.DatapackWorlds) == 0 {
+		config.DatapackWorlds = discoverDatapackWorlds(config.MinecraftDir)
+	}
+
 	config.DatabasePath = filepath.Join(config.MinecraftDir, "mods.db")
 	return config, nil
 }
@@ -65,7 +114,20 @@ func bindEnvVars() {
 		"minecraft_installation_type": "MINECRAFT_INSTALLATION_TYPE",
 		"minecraft_loader":            "MINECRAFT_LOADER",
 		"minecraft_version":           "MINECRAFT_VERSION",
+		"loader_version":              "LOADER_VERSION",
 		"modrinth_user":               "MODRINTH_USER",
+		"minecraft_version_fallback":  "MINECRAFT_VERSION_FALLBACK",
+		"version_fallback_projects":   "VERSION_FALLBACK_PROJECTS",
+		"shader_loader":               "SHADER_LOADER",
+		"resourcepack_format":         "RESOURCEPACK_FORMAT",
+		"datapack_worlds":             "DATAPACK_WORLDS",
+		"datapack_projects":           "DATAPACK_PROJECTS",
+		"unfollowed_policy":           "UNFOLLOWED_POLICY",
+		"check_after_update":          "CHECK_AFTER_UPDATE",
+		"github_token":                "GITHUB_TOKEN",
+		"github_api_url":              "GITHUB_API_URL",
+		"curseforge_api_key":          "CURSEFORGE_API_KEY",
+		"curseforge_api_url":          "CURSEFORGE_API_URL",
 	}
 	for key, env := range vars {
 		_ = viper.BindEnv(key, env)
@@ -73,6 +135,8 @@ func bindEnvVars() {
 }
 
 func processConfigDefaults(config *Config) {
+	// Loader names are compared with Modrinth's lowercase tags and the plugin compatibility lists.
+	config.MinecraftLoader = strings.ToLower(strings.TrimSpace(config.MinecraftLoader))
 	if config.MinecraftLoader == "" {
 		config.MinecraftLoader = "fabric"
 	}
@@ -80,12 +144,58 @@ func processConfigDefaults(config *Config) {
 		config.MinecraftInstallationType = "server"
 	}
 
+	config.ShaderLoader = strings.ToLower(strings.TrimSpace(config.ShaderLoader))
+
+	config.UnfollowedPolicy = strings.ToLower(strings.TrimSpace(config.UnfollowedPolicy))
+	if config.UnfollowedPolicy == "" {
+		config.UnfollowedPolicy = UnfollowedKeep
+	}
+
 	if config.UserAgent == "" {
-		config.UserAgent = "modrinth-mod-updater/dev (unknown-user)"
+		config.UserAgent = DefaultUserAgent
 		slog.Warn("USERAGENT not set, using default.")
 	}
 }
 
+// DefaultUserAgent is used when USERAGENT is not set. Modrinth asks clients to identify themselves with contact details.
+const DefaultUserAgent = "modrinth-mod-updater/dev (unknown-user)"
+
+// PluginLoaders lists the server and proxy platforms that load plugins instead of mods.
+var PluginLoaders = []string{"paper", "purpur", "spigot", "velocity", "bungeecord"}
+
+// IsPluginPlatform reports whether the configured loader is a plugin platform.
+func (c Config) IsPluginPlatform() bool {
+	return slices.Contains(PluginLoaders, strings.ToLower(c.MinecraftLoader))
+}
+
+// ShaderLoaders lists the shader loaders accepted for SHADER_LOADER.
+var ShaderLoaders = []string{"iris", "optifine", "canvas", "vanilla"}
+
+func validateShaderLoader(loader string) error {
+	if loader == "" || slices.Contains(ShaderLoaders, loader) {
+		return nil
+	}
+	return fmt.Errorf("invalid SHADER_LOADER %q, expected one of %s", loader, strings.Join(ShaderLoaders, ", "))
+}
+
+// Policies for installed projects that are no longer followed, selected with UNFOLLOWED_POLICY.
+const (
+	UnfollowedKeep    = "keep"    // Leave the file installed without updating it
+	UnfollowedUpdate  = "update"  // Keep updating the project as if it were followed
+	UnfollowedArchive = "archive" // Move the file to the versions directory and forget the project
+	UnfollowedRemove  = "remove"  // Delete the file and forget the project
+)
+
+// UnfollowedPolicies lists the policies accepted for UNFOLLOWED_POLICY.
+var UnfollowedPolicies = []string{UnfollowedKeep, UnfollowedUpdate, UnfollowedArchive, UnfollowedRemove}
+
+func validateUnfollowedPolicy(policy string) error {
+	if slices.Contains(UnfollowedPolicies, policy) {
+		return nil
+	}
+	return fmt.Errorf("invalid UNFOLLOWED_POLICY %q, expected one of %s", policy, strings.Join(UnfollowedPolicies, ", "))
+}
+
 func validateAndEnsureDirectories(config *Config) error {
 	if config.MinecraftDir == "" {
 		return fmt.Errorf("MINECRAFT_DIR is required")
@@ -97,6 +207,13 @@ func validateAndEnsureDirectories(config *Config) error {
 		filepath.Join(config.MinecraftDir, "shaderpacks"),
 		filepath.Join(config.MinecraftDir, "resourcepacks"),
 	}
+	if config.IsPluginPlatform() {
+		// Plugin servers and proxies have no use for mod, shader or resource pack folders.
+		dirs = []string{
+			config.MinecraftDir,
+			filepath.Join(config.MinecraftDir, "plugins"),
+		}
+	}
 
 	for _, dir := range dirs {
 		if _, err := os.Stat(dir); os.IsNotExist(err) {
@@ -111,3 +228,91 @@ func validateAndEnsureDirectories(config *Config) error {
 
 	return nil
 }
+
+// discoverDatapackWorlds returns the world configured by level-name in server.properties.
+// Instances without a server.properties (e.g. clients) have no default world.
+func discoverDatapackWorlds(minecraftDir string) []string {
+	f, err := os.Open(filepath.Join(minecraftDir, "server.properties"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	levelName := "world"
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && strings.TrimSpace(key) == "level-name" && strings.TrimSpace(value) != "" {
+			levelName = strings.TrimSpace(value)
+		}
+	}
+	return []string{levelName}
+}
+
+// DatapackDirs returns the datapacks directory of every configured world.
+func (c Config) DatapackDirs() []string {
+	var dirs []string
+	for _, world := range c.DatapackWorlds {
+		world = strings.TrimSpace(world)
+		if world == "" {
+			continue
+		}
+		if !filepath.IsAbs(world) {
+			world = filepath.Join(c.MinecraftDir, world)
+		}
+		dirs = append(dirs, filepath.Join(world, "datapacks"))
+	}
+	return dirs
+}
+
+// PrefersDatapack reports whether the given mod should be installed using its datapack variant.
+func (c Config) PrefersDatapack(slug string) bool {
+	for _, s := range c.DatapackProjects {
+		if strings.EqualFold(strings.TrimSpace(s), slug) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsVersionFallback reports whether the given project has opted in to fallback game versions.
+func (c Config) AllowsVersionFallback(slug string) bool {
+	for _, allowed := range c.VersionFallbackProjects {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == "*" || strings.EqualFold(allowed, slug) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetEnvValue sets a variable in the .env file in path, replacing its existing assignment or appending
+// one. Other lines, including comments, are kept as they are.
+func SetEnvValue(path, key, value string) error {
+	file := filepath.Join(path, ".env")
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	found := false
+	for i, line := range lines {
+		name, _, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if ok && strings.TrimSpace(strings.TrimPrefix(name, "export ")) == key {
+			lines[i] = key + "=" + value
+			found = true
+		}
+	}
+	if !found {
+		lines = append(lines, key+"="+value)
+	}
+	return os.WriteFile(file, []byte(strings.Join(lines, "\n")+"\n"), info.Mode().Perm())
+}
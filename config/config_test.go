@@ -23,6 +23,9 @@ func TestProcessConfigDefaults(t *testing.T) {
 		if cfg.UserAgent == "" {
 			t.Error("Expected UserAgent to have a default value")
 		}
+		if cfg.UnfollowedPolicy != UnfollowedKeep {
+			t.Errorf("Expected UnfollowedPolicy to be keep, got %s", cfg.UnfollowedPolicy)
+		}
 	})
 
 	t.Run("respects existing values", func(t *testing.T) {
@@ -44,6 +47,16 @@ func TestProcessConfigDefaults(t *testing.T) {
 			t.Errorf("Expected UserAgent to stay custom-agent, got %s", cfg.UserAgent)
 		}
 	})
+
+	t.Run("normalizes loader", func(t *testing.T) {
+		viper.Reset()
+		cfg := Config{MinecraftLoader: " Paper "}
+		processConfigDefaults(&cfg)
+
+		if cfg.MinecraftLoader != "paper" {
+			t.Errorf("Expected MinecraftLoader to be paper, got %q", cfg.MinecraftLoader)
+		}
+	})
 }
 
 func TestValidateAndEnsureDirectories(t *testing.T) {
@@ -74,3 +87,143 @@ func TestValidateAndEnsureDirectories(t *testing.T) {
 		}
 	})
 }
+
+func TestValidateAndEnsureDirectoriesPluginPlatform(t *testing.T) {
+	mcDir := filepath.Join(t.TempDir(), "paper")
+	cfg := Config{MinecraftDir: mcDir, MinecraftLoader: "paper"}
+	if err := validateAndEnsureDirectories(&cfg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(mcDir, "plugins")); os.IsNotExist(err) {
+		t.Error("Directory plugins was not created")
+	}
+	for _, sub := range []string{"mods", "shaderpacks", "resourcepacks"} {
+		if _, err := os.Stat(filepath.Join(mcDir, sub)); !os.IsNotExist(err) {
+			t.Errorf("Directory %s should not be created for plugin platforms", sub)
+		}
+	}
+}
+
+func TestAllowsVersionFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		projects []string
+		slug     string
+		expected bool
+	}{
+		{"no projects configured", nil, "sodium", false},
+		{"listed project", []string{"lithium", "sodium"}, "sodium", true},
+		{"case insensitive", []string{"Sodium"}, "sodium", true},
+		{"unlisted project", []string{"lithium"}, "sodium", false},
+		{"wildcard", []string{"*"}, "sodium", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{VersionFallbackProjects: tt.projects}
+			if result := cfg.AllowsVersionFallback(tt.slug); result != tt.expected {
+				t.Errorf("AllowsVersionFallback(%q) = %v, want %v", tt.slug, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateShaderLoader(t *testing.T) {
+	for _, loader := range []string{"", "iris", "optifine", "canvas", "vanilla"} {
+		if err := validateShaderLoader(loader); err != nil {
+			t.Errorf("validateShaderLoader(%q) returned unexpected error: %v", loader, err)
+		}
+	}
+	if err := validateShaderLoader("sodium"); err == nil {
+		t.Error("Expected error for unknown shader loader")
+	}
+}
+
+func TestValidateUnfollowedPolicy(t *testing.T) {
+	for _, policy := range UnfollowedPolicies {
+		if err := validateUnfollowedPolicy(policy); err != nil {
+			t.Errorf("validateUnfollowedPolicy(%q) returned unexpected error: %v", policy, err)
+		}
+	}
+	if err := validateUnfollowedPolicy("delete"); err == nil {
+		t.Error("Expected error for unknown unfollowed policy")
+	}
+}
+
+func TestDiscoverDatapackWorlds(t *testing.T) {
+	t.Run("no server.properties", func(t *testing.T) {
+		if worlds := discoverDatapackWorlds(t.TempDir()); worlds != nil {
+			t.Errorf("Expected no worlds, got %v", worlds)
+		}
+	})
+
+	t.Run("level-name", func(t *testing.T) {
+		dir := t.TempDir()
+		props := "#Minecraft server properties\nmotd=hello\nlevel-name=survival\n"
+		if err := os.WriteFile(filepath.Join(dir, "server.properties"), []byte(props), 0644); err != nil {
+			t.Fatalf("Failed to write server.properties: %v", err)
+		}
+		worlds := discoverDatapackWorlds(dir)
+		if len(worlds) != 1 || worlds[0] != "survival" {
+			t.Errorf("Expected [survival], got %v", worlds)
+		}
+	})
+
+	t.Run("default level-name", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "server.properties"), []byte("motd=hello\n"), 0644); err != nil {
+			t.Fatalf("Failed to write server.properties: %v", err)
+		}
+		worlds := discoverDatapackWorlds(dir)
+		if len(worlds) != 1 || worlds[0] != "world" {
+			t.Errorf("Expected [world], got %v", worlds)
+		}
+	})
+}
+
+func TestDatapackDirs(t *testing.T) {
+	cfg := Config{MinecraftDir: "/srv/mc", DatapackWorlds: []string{"world", "/data/other", " "}}
+	dirs := cfg.DatapackDirs()
+	expected := []string{filepath.Join("/srv/mc", "world", "datapacks"), filepath.Join("/data/other", "datapacks")}
+	if len(dirs) != len(expected) {
+		t.Fatalf("DatapackDirs() = %v, want %v", dirs, expected)
+	}
+	for i := range expected {
+		if dirs[i] != expected[i] {
+			t.Errorf("DatapackDirs()[%d] = %q, want %q", i, dirs[i], expected[i])
+		}
+	}
+}
+
+func TestSetEnvValue(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, ".env")
+	content := "# Minecraft settings\nMINECRAFT_VERSION=1.21.4\nMINECRAFT_LOADER=fabric\n"
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetEnvValue(dir, "MINECRAFT_VERSION", "1.21.5"); err != nil {
+		t.Fatalf("SetEnvValue() error = %v", err)
+	}
+	if err := SetEnvValue(dir, "KEEP_OLD_VERSIONS", "true"); err != nil {
+		t.Fatalf("SetEnvValue() error = %v", err)
+	}
+
+	got, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Minecraft settings\nMINECRAFT_VERSION=1.21.5\nMINECRAFT_LOADER=fabric\nKEEP_OLD_VERSIONS=true\n"
+	if string(got) != want {
+		t.Errorf(".env = %q, want %q", got, want)
+	}
+	if info, _ := os.Stat(file); info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	if err := SetEnvValue(t.TempDir(), "MINECRAFT_VERSION", "1.21.5"); !os.IsNotExist(err) {
+		t.Errorf("SetEnvValue() without .env error = %v, want not exist", err)
+	}
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"time"
@@ -13,8 +14,15 @@ import (
 
 var DB *gorm.DB
 
-// InitDatabase initializes the SQLite database connection and migrates models.
+// InitDatabase initializes the SQLite database connection and migrates models, exiting on failure.
 func InitDatabase(dbPath string) {
+	if err := Open(dbPath); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Open initializes the SQLite database connection as DB and migrates models.
+func Open(dbPath string) error {
 	var err error
 
 	// Configure GORM logger
@@ -33,12 +41,13 @@ func InitDatabase(dbPath string) {
 		Logger: newLogger, // Use the configured logger
 	})
 	if err != nil {
-		log.Fatalf("failed to connect database: %v", err)
+		return fmt.Errorf("failed to connect database: %w", err)
 	}
 
-	// Auto-migrate the Mod and ModVersion schema
-	err = DB.AutoMigrate(&Mod{}, &ModVersion{})
+	// Auto-migrate the Mod, ModVersion, LocalFollow and bisect schema
+	err = DB.AutoMigrate(&Mod{}, &ModVersion{}, &ResourcePackFormat{}, &LocalFollow{}, &BisectSession{}, &BisectMod{})
 	if err != nil {
-		log.Fatalf("failed to migrate database schema: %v", err)
+		return fmt.Errorf("failed to migrate database schema: %w", err)
 	}
+	return nil
 }
@@ -3,6 +3,8 @@ package db
 import (
 	"time"
 
+	"modrinth-mod-updater/jarmeta"
+
 	"gorm.io/gorm"
 )
 
@@ -11,14 +13,32 @@ type Mod struct {
 	gorm.Model
 	ProjectSlug   string    `gorm:"uniqueIndex"` // Modrinth Project Slug (unique identifier)
 	ProjectID     string    // Modrinth Project ID
+	Provider      string    `gorm:"default:modrinth"` // Source of the mod: modrinth, github, maven or curseforge
+	Source        string    // Provider-specific project reference, e.g. "owner/repo" for GitHub
 	Title         string    // Mod Title
+	ProjectType   string    // Effective project type: mod, shader, resourcepack, datapack or plugin
+	ClientSide    string    // Client support: required, optional, unsupported, unknown
+	ServerSide    string    // Server support: required, optional, unsupported, unknown
 	IconURL       string    // Mod Icon URL
 	Color         int       // Mod Color
 	Updated       time.Time // Last time the mod was updated on Modrinth
 	VersionID     string    // Modrinth Version ID
 	VersionNumber string    // Human-readable version number
 	FileName      string    // Downloaded file name
-	InstallPath   string    // Path where the mod is currently installed
+	FileHash      string    // SHA-1 of the file when it was installed, checked by verify
+	InstallPath   string    // Path where the mod is installed, without the .disabled suffix of disabled mods
+	Pinned        bool      // Pinned mods keep their installed version during updates
+	Disabled      bool      // Disabled mods are kept on disk with a .disabled suffix, see InstallPath
+	Followed      bool      // Whether the project was followed while installed, see UNFOLLOWED_POLICY
+
+	// Loader metadata read from the jar itself, empty for other files and jars without metadata
+	JarModID       string               // Mod ID declared in the jar
+	JarLoader      string               // Loader whose metadata was read: fabric, quilt, forge or neoforge
+	JarVersion     string               // Version declared in the jar, which may differ from VersionNumber
+	JarEnvironment string               // Environment declared in the jar: client, server or * for both
+	JarDepends     []jarmeta.Dependency `gorm:"serializer:json"` // Mods the jar requires
+	JarBreaks      []jarmeta.Dependency `gorm:"serializer:json"` // Mods the jar is incompatible with
+	JarProvides    []jarmeta.Provided   `gorm:"serializer:json"` // Other mod IDs the jar provides, including bundled mods
 }
 
 // ModVersion represents a historical version of a mod
@@ -28,5 +48,39 @@ type ModVersion struct {
 	VersionID     string // Modrinth Version ID
 	VersionNumber string // Human-readable version number
 	FileName      string // Original file name
+	FileHash      string // SHA-1 of the file, checked by verify
 	ArchivePath   string // Path to the archived file (if kept)
 }
+
+// ResourcePackFormat caches the pack formats a resource pack file declares, so RESOURCEPACK_FORMAT checks
+// download each file only once
+type ResourcePackFormat struct {
+	gorm.Model
+	VersionID string `gorm:"uniqueIndex:idx_resource_pack_format_file"` // Modrinth Version ID
+	FileName  string `gorm:"uniqueIndex:idx_resource_pack_format_file"` // File of the version
+	MinFormat int    // Lowest supported pack format, 0 when the file declares none
+	MaxFormat int    // Highest supported pack format
+}
+
+// LocalFollow is a project followed locally instead of on Modrinth, used when no API key is configured
+type LocalFollow struct {
+	gorm.Model
+	ProjectID   string `gorm:"uniqueIndex"` // Modrinth Project ID
+	ProjectSlug string // Modrinth Project Slug at the time it was added
+}
+
+// BisectSession is the running bisect, if any. Only one session exists at a time.
+type BisectSession struct {
+	gorm.Model
+	Step int // Number of the step being tested, starting at 1
+}
+
+// BisectMod is a mod taking part in the running bisect
+type BisectMod struct {
+	gorm.Model
+	ProjectSlug string `gorm:"uniqueIndex"` // References Mod.ProjectSlug
+	Requires    string // Comma-separated slugs of the tracked mods this mod requires
+	WasDisabled bool   // Disabled state before the bisect, restored by bisect reset
+	Suspect     bool   // Whether the mod may still cause the problem
+	Active      bool   // Whether the mod is enabled in the current step
+}
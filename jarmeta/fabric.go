@@ -0,0 +1,205 @@
+package jarmeta
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// fabricModJSON represents the parts of fabric.mod.json that are read.
+type fabricModJSON struct {
+	ID          string                     `json:"id"`
+	Version     string                     `json:"version"`
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	Environment string                     `json:"environment"`
+	Provides    []string                   `json:"provides"`
+	Depends     map[string]json.RawMessage `json:"depends"`
+	Breaks      map[string]json.RawMessage `json:"breaks"`
+}
+
+// quiltModJSON represents the parts of quilt.mod.json that are read.
+type quiltModJSON struct {
+	QuiltLoader struct {
+		ID       string `json:"id"`
+		Version  string `json:"version"`
+		Metadata struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		} `json:"metadata"`
+		Provides []json.RawMessage `json:"provides"`
+		Depends  []json.RawMessage `json:"depends"`
+		Breaks   []json.RawMessage `json:"breaks"`
+	} `json:"quilt_loader"`
+	Minecraft struct {
+		Environment string `json:"environment"`
+	} `json:"minecraft"`
+}
+
+// quiltDependency is the object form of a quilt.mod.json dependency.
+type quiltDependency struct {
+	ID       string          `json:"id"`
+	Versions json.RawMessage `json:"versions"`
+	Optional bool            `json:"optional"`
+}
+
+func parseFabric(data []byte) (*Metadata, error) {
+	var f fabricModJSON
+	if err := unmarshalLenient(data, &f); err != nil {
+		return nil, err
+	}
+	env := f.Environment
+	if env == "" {
+		env = EnvironmentBoth
+	}
+	var provides []Provided
+	for _, id := range f.Provides {
+		provides = append(provides, Provided{ID: id, Version: f.Version})
+	}
+	return &Metadata{
+		Loader:      Fabric,
+		ID:          f.ID,
+		Name:        f.Name,
+		Version:     f.Version,
+		Description: f.Description,
+		Environment: env,
+		Depends:     fabricDependencies(f.Depends),
+		Breaks:      fabricDependencies(f.Breaks),
+		Provides:    provides,
+	}, nil
+}
+
+// fabricDependencies converts a fabric.mod.json dependency map, whose values are a version predicate or
+// a list of alternatives, sorted by mod ID.
+func fabricDependencies(deps map[string]json.RawMessage) []Dependency {
+	result := make([]Dependency, 0, len(deps))
+	for id, raw := range deps {
+		result = append(result, Dependency{ID: id, Versions: versionList(raw)})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+func parseQuilt(data []byte) (*Metadata, error) {
+	var q quiltModJSON
+	if err := unmarshalLenient(data, &q); err != nil {
+		return nil, err
+	}
+	env := q.Minecraft.Environment
+	switch env {
+	case "", EnvironmentBoth:
+		env = EnvironmentBoth
+	case "dedicated_server":
+		env = EnvironmentServer
+	}
+	loader := q.QuiltLoader
+	return &Metadata{
+		Loader:      Quilt,
+		ID:          loader.ID,
+		Name:        loader.Metadata.Name,
+		Version:     loader.Version,
+		Description: loader.Metadata.Description,
+		Environment: env,
+		Depends:     quiltDependencies(loader.Depends, false),
+		Breaks:      quiltDependencies(loader.Breaks, true),
+		Provides:    quiltProvides(loader.Provides, loader.Version),
+	}, nil
+}
+
+// quiltProvides converts quilt.mod.json provides entries, which are either a mod ID or an object with an
+// optional version that defaults to the mod's own.
+func quiltProvides(entries []json.RawMessage, version string) []Provided {
+	var provides []Provided
+	for _, raw := range entries {
+		var id string
+		if json.Unmarshal(raw, &id) == nil {
+			provides = append(provides, Provided{ID: quiltModID(id), Version: version})
+			continue
+		}
+		var p Provided
+		if json.Unmarshal(raw, &p) != nil || p.ID == "" {
+			continue
+		}
+		p.ID, p.Version = quiltModID(p.ID), valueOr(p.Version, version)
+		provides = append(provides, p)
+	}
+	return provides
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// quiltDependencies converts quilt.mod.json dependencies, which are either a mod ID or an object. Optional
+// dependencies are left out of required ones.
+func quiltDependencies(deps []json.RawMessage, includeOptional bool) []Dependency {
+	var result []Dependency
+	for _, raw := range deps {
+		var id string
+		if json.Unmarshal(raw, &id) == nil {
+			result = append(result, Dependency{ID: quiltModID(id)})
+			continue
+		}
+		var dep quiltDependency
+		if json.Unmarshal(raw, &dep) != nil || dep.ID == "" || (dep.Optional && !includeOptional) {
+			continue
+		}
+		result = append(result, Dependency{ID: quiltModID(dep.ID), Versions: versionList(dep.Versions)})
+	}
+	return result
+}
+
+// quiltModID strips the optional Maven group from a Quilt dependency ID ("group:id").
+func quiltModID(id string) string {
+	if _, modID, ok := strings.Cut(id, ":"); ok {
+		return modID
+	}
+	return id
+}
+
+// versionList reads a version predicate that is a single string or a list of alternatives. Other forms,
+// like Quilt's {"all": [...]} objects, are treated as matching every version.
+func versionList(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var single string
+	if json.Unmarshal(raw, &single) == nil {
+		if single == "" || single == "*" {
+			return nil
+		}
+		return []string{single}
+	}
+	var list []string
+	if json.Unmarshal(raw, &list) == nil && !slices.Contains(list, "*") {
+		return list
+	}
+	return nil
+}
+
+// unmarshalLenient decodes JSON and retries with raw control characters replaced by spaces, since the
+// loaders accept line breaks inside strings that encoding/json rejects.
+func unmarshalLenient(data []byte, v any) error {
+	err := json.Unmarshal(data, v)
+	if err == nil {
+		return nil
+	}
+	cleaned := bytes.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == '\t' {
+			return ' '
+		}
+		return r
+	}, data)
+	if json.Unmarshal(cleaned, v) == nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,69 @@
+package jarmeta
+
+import (
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// forgeModsToml represents the parts of mods.toml and neoforge.mods.toml that are read.
+type forgeModsToml struct {
+	ClientSideOnly bool `toml:"clientSideOnly"`
+	Mods           []struct {
+		ModID       string `toml:"modId"`
+		Version     string `toml:"version"`
+		DisplayName string `toml:"displayName"`
+		Description string `toml:"description"`
+	} `toml:"mods"`
+	Dependencies map[string][]forgeDependency `toml:"dependencies"`
+}
+
+// forgeDependency is a [[dependencies.<modId>]] entry. Forge marks required dependencies with mandatory,
+// NeoForge with type, which defaults to required.
+type forgeDependency struct {
+	ModID        string `toml:"modId"`
+	Mandatory    *bool  `toml:"mandatory"`
+	Type         string `toml:"type"`
+	VersionRange string `toml:"versionRange"`
+}
+
+func parseForgeToml(loader string) func([]byte) (*Metadata, error) {
+	return func(data []byte) (*Metadata, error) {
+		var f forgeModsToml
+		if err := toml.Unmarshal(data, &f); err != nil {
+			return nil, err
+		}
+		if len(f.Mods) == 0 {
+			return nil, ErrNoMetadata
+		}
+		mod := f.Mods[0]
+		meta := &Metadata{
+			Loader:      loader,
+			ID:          mod.ModID,
+			Name:        mod.DisplayName,
+			Version:     mod.Version,
+			Description: strings.TrimSpace(mod.Description),
+			Environment: EnvironmentBoth,
+		}
+		if f.ClientSideOnly {
+			meta.Environment = EnvironmentClient
+		}
+		for _, dep := range f.Dependencies[mod.ModID] {
+			d := Dependency{ID: dep.ModID}
+			if dep.VersionRange != "" && dep.VersionRange != "*" {
+				d.Versions = []string{dep.VersionRange}
+			}
+			switch {
+			case strings.EqualFold(dep.Type, "incompatible"):
+				meta.Breaks = append(meta.Breaks, d)
+			case dep.Type != "":
+				if strings.EqualFold(dep.Type, "required") {
+					meta.Depends = append(meta.Depends, d)
+				}
+			case dep.Mandatory == nil || *dep.Mandatory:
+				meta.Depends = append(meta.Depends, d)
+			}
+		}
+		return meta, nil
+	}
+}
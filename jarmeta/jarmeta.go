@@ -0,0 +1,243 @@
+// Package jarmeta reads the metadata mod loaders expect inside mod jars: fabric.mod.json, quilt.mod.json,
+// META-INF/mods.toml (Forge) and META-INF/neoforge.mods.toml (NeoForge).
+package jarmeta
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Loaders whose metadata is read.
+const (
+	Fabric   = "fabric"
+	Quilt    = "quilt"
+	Forge    = "forge"
+	NeoForge = "neoforge"
+)
+
+// Environments a mod declares it runs in.
+const (
+	EnvironmentBoth   = "*"
+	EnvironmentClient = "client"
+	EnvironmentServer = "server"
+)
+
+// Metadata file names inside a jar.
+const (
+	FabricFile   = "fabric.mod.json"
+	QuiltFile    = "quilt.mod.json"
+	ForgeFile    = "META-INF/mods.toml"
+	NeoForgeFile = "META-INF/neoforge.mods.toml"
+	manifestFile = "META-INF/MANIFEST.MF"
+)
+
+// metadataFiles lists the metadata files in the order Read returns them.
+var metadataFiles = []string{QuiltFile, FabricFile, NeoForgeFile, ForgeFile}
+
+var parsers = map[string]func([]byte) (*Metadata, error){
+	QuiltFile:    parseQuilt,
+	FabricFile:   parseFabric,
+	NeoForgeFile: parseForgeToml(NeoForge),
+	ForgeFile:    parseForgeToml(Forge),
+}
+
+// ErrNoMetadata is returned when a jar contains none of the supported metadata files.
+var ErrNoMetadata = errors.New("no mod metadata found")
+
+// Metadata describes the mod declared by one metadata file of a jar.
+type Metadata struct {
+	Loader      string       `json:"loader"` // Fabric, Quilt, Forge or NeoForge
+	ID          string       `json:"id"`
+	Name        string       `json:"name,omitempty"`
+	Version     string       `json:"version,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Environment string       `json:"environment,omitempty"` // EnvironmentBoth, EnvironmentClient or EnvironmentServer
+	Depends     []Dependency `json:"depends,omitempty"`
+	Breaks      []Dependency `json:"breaks,omitempty"`
+	// Provides lists the other mod IDs the jar satisfies: aliases it declares and the mods bundled in it.
+	Provides []Provided `json:"provides,omitempty"`
+}
+
+// Provided is a mod ID a jar provides besides its own, with the version it provides.
+type Provided struct {
+	ID      string `json:"id"`
+	Version string `json:"version,omitempty"`
+}
+
+// Dependency is a mod another mod requires or is incompatible with.
+type Dependency struct {
+	ID string `json:"id"`
+	// Versions lists the accepted version ranges in the loader's syntax: Fabric/Quilt version predicates or
+	// Maven version ranges for Forge/NeoForge. Any one of them matches; none matches every version.
+	Versions []string `json:"versions,omitempty"`
+}
+
+// Read returns the metadata of every supported metadata file in a jar. When a file declares several mods,
+// the first one is returned.
+func Read(path string) ([]Metadata, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	files := make(map[string]*zip.File)
+	for _, f := range r.File {
+		files[f.Name] = f
+	}
+
+	var metas []Metadata
+	parseErr := ErrNoMetadata
+	for _, name := range metadataFiles {
+		f, ok := files[name]
+		if !ok {
+			continue
+		}
+		data, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		meta, err := parsers[name](data)
+		if err != nil {
+			// A broken file for one loader does not hide the metadata for the others.
+			parseErr = fmt.Errorf("failed to parse %s: %w", name, err)
+			continue
+		}
+		if meta.Version == jarVersionPlaceholder {
+			meta.Version = manifestVersion(files[manifestFile])
+		}
+		meta.Provides = append(meta.Provides, bundledMods(r.File, meta.Loader)...)
+		metas = append(metas, *meta)
+	}
+	if len(metas) == 0 {
+		return nil, parseErr
+	}
+	return metas, nil
+}
+
+// Select picks the metadata the given loader uses. Quilt loads Fabric mods and NeoForge loads mods.toml
+// from older Forge-compatible jars, so those are used when the loader's own file is missing. Without a
+// match, or without a loader, the first metadata is returned.
+func Select(metas []Metadata, loader string) *Metadata {
+	if len(metas) == 0 {
+		return nil
+	}
+	preferred := map[string][]string{
+		Quilt:    {Quilt, Fabric},
+		NeoForge: {NeoForge, Forge},
+	}[loader]
+	if preferred == nil {
+		preferred = []string{loader}
+	}
+	for _, l := range preferred {
+		for i := range metas {
+			if metas[i].Loader == l {
+				return &metas[i]
+			}
+		}
+	}
+	return &metas[0]
+}
+
+// bundledDirs maps each loader to the directory holding its jar-in-jar mods.
+var bundledDirs = map[string]string{
+	Fabric:   "META-INF/jars/",
+	Quilt:    "META-INF/jars/",
+	Forge:    "META-INF/jarjar/",
+	NeoForge: "META-INF/jarjar/",
+}
+
+// bundledMods returns the mods, and the IDs they provide, of the jars bundled for a loader. Only one level
+// of nesting is read.
+func bundledMods(files []*zip.File, loader string) []Provided {
+	dir := bundledDirs[loader]
+	var provided []Provided
+	for _, f := range files {
+		rest, ok := strings.CutPrefix(f.Name, dir)
+		if !ok || strings.Contains(rest, "/") || !strings.HasSuffix(strings.ToLower(rest), ".jar") {
+			continue
+		}
+		data, err := readZipFile(f)
+		if err != nil {
+			continue
+		}
+		metas, err := readNested(data)
+		if err != nil {
+			continue
+		}
+		for _, meta := range metas {
+			provided = append(provided, Provided{ID: meta.ID, Version: meta.Version})
+			provided = append(provided, meta.Provides...)
+		}
+	}
+	return provided
+}
+
+// readNested reads the metadata files of a jar held in memory, without looking at its own bundled jars.
+func readNested(data []byte) ([]Metadata, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	var manifest *zip.File
+	for _, f := range r.File {
+		if f.Name == manifestFile {
+			manifest = f
+		}
+	}
+
+	var metas []Metadata
+	for _, f := range r.File {
+		parse, ok := parsers[f.Name]
+		if !ok {
+			continue
+		}
+		content, err := readZipFile(f)
+		if err != nil {
+			continue
+		}
+		if meta, err := parse(content); err == nil {
+			if meta.Version == jarVersionPlaceholder {
+				meta.Version = manifestVersion(manifest)
+			}
+			metas = append(metas, *meta)
+		}
+	}
+	return metas, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// jarVersionPlaceholder is the version a Forge mods.toml declares to use the manifest's Implementation-Version.
+const jarVersionPlaceholder = "${file.jarVersion}"
+
+// manifestVersion returns the Implementation-Version of a jar manifest, which Forge substitutes for
+// ${file.jarVersion}.
+func manifestVersion(f *zip.File) string {
+	if f == nil {
+		return ""
+	}
+	data, err := readZipFile(f)
+	if err != nil {
+		return ""
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "Implementation-Version:"); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,289 @@
+package jarmeta
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeJar(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "mod.jar")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create jar: %v", err)
+	}
+	defer f.Close()
+	w := zip.NewWriter(f)
+	for name, content := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		if _, err := fw.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close jar: %v", err)
+	}
+	return path
+}
+
+func TestReadFabric(t *testing.T) {
+	path := writeJar(t, map[string]string{FabricFile: `{
+		"schemaVersion": 1,
+		"id": "sodium",
+		"version": "0.6.0",
+		"name": "Sodium",
+		"description": "Rendering engine
+replacement",
+		"environment": "client",
+		"depends": {"minecraft": ["1.21", "1.21.1"], "fabricloader": ">=0.16.0", "java": "*"},
+		"breaks": {"optifabric": "*"}
+	}`})
+
+	metas, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := Metadata{
+		Loader:      Fabric,
+		ID:          "sodium",
+		Name:        "Sodium",
+		Version:     "0.6.0",
+		Description: "Rendering engine replacement",
+		Environment: EnvironmentClient,
+		Depends: []Dependency{
+			{ID: "fabricloader", Versions: []string{">=0.16.0"}},
+			{ID: "java"},
+			{ID: "minecraft", Versions: []string{"1.21", "1.21.1"}},
+		},
+		Breaks: []Dependency{{ID: "optifabric"}},
+	}
+	if len(metas) != 1 || !reflect.DeepEqual(metas[0], want) {
+		t.Errorf("Read() = %+v, want %+v", metas, want)
+	}
+}
+
+func TestReadQuilt(t *testing.T) {
+	path := writeJar(t, map[string]string{QuiltFile: `{
+		"schema_version": 1,
+		"quilt_loader": {
+			"id": "qsl_mod",
+			"version": "1.0.0",
+			"metadata": {"name": "QSL Mod"},
+			"depends": ["quilt_loader", {"id": "org.quiltmc:qsl", "versions": ">=7.0"}, {"id": "modmenu", "optional": true}],
+			"breaks": [{"id": "optifine", "versions": "*"}]
+		},
+		"minecraft": {"environment": "dedicated_server"}
+	}`})
+
+	metas, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := Metadata{
+		Loader:      Quilt,
+		ID:          "qsl_mod",
+		Name:        "QSL Mod",
+		Version:     "1.0.0",
+		Environment: EnvironmentServer,
+		Depends:     []Dependency{{ID: "quilt_loader"}, {ID: "qsl", Versions: []string{">=7.0"}}},
+		Breaks:      []Dependency{{ID: "optifine"}},
+	}
+	if len(metas) != 1 || !reflect.DeepEqual(metas[0], want) {
+		t.Errorf("Read() = %+v, want %+v", metas, want)
+	}
+}
+
+func TestReadForge(t *testing.T) {
+	path := writeJar(t, map[string]string{
+		ForgeFile: `
+modLoader = "javafml"
+loaderVersion = "[47,)"
+clientSideOnly = true
+
+[[mods]]
+modId = "jei"
+version = "${file.jarVersion}"
+displayName = "Just Enough Items"
+description = '''
+Item and recipe viewing mod
+'''
+
+[[dependencies.jei]]
+modId = "forge"
+mandatory = true
+versionRange = "[47.1,)"
+
+[[dependencies.jei]]
+modId = "minecraft"
+mandatory = true
+versionRange = "[1.20.1,1.20.2)"
+
+[[dependencies.jei]]
+modId = "rei"
+mandatory = false
+versionRange = "*"
+`,
+		manifestFile: "Manifest-Version: 1.0\r\nImplementation-Version: 15.2.0.27\r\n",
+	})
+
+	metas, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := Metadata{
+		Loader:      Forge,
+		ID:          "jei",
+		Name:        "Just Enough Items",
+		Version:     "15.2.0.27",
+		Description: "Item and recipe viewing mod",
+		Environment: EnvironmentClient,
+		Depends: []Dependency{
+			{ID: "forge", Versions: []string{"[47.1,)"}},
+			{ID: "minecraft", Versions: []string{"[1.20.1,1.20.2)"}},
+		},
+	}
+	if len(metas) != 1 || !reflect.DeepEqual(metas[0], want) {
+		t.Errorf("Read() = %+v, want %+v", metas, want)
+	}
+}
+
+func TestReadNeoForge(t *testing.T) {
+	path := writeJar(t, map[string]string{NeoForgeFile: `
+[[mods]]
+modId = "create"
+version = "6.0.0"
+
+[[dependencies.create]]
+modId = "neoforge"
+type = "required"
+versionRange = "[21.1,)"
+
+[[dependencies.create]]
+modId = "ponder"
+
+[[dependencies.create]]
+modId = "jei"
+type = "optional"
+
+[[dependencies.create]]
+modId = "optifine"
+type = "incompatible"
+`})
+
+	metas, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	got := metas[0]
+	wantDepends := []Dependency{{ID: "neoforge", Versions: []string{"[21.1,)"}}, {ID: "ponder"}}
+	if got.Loader != NeoForge || got.Environment != EnvironmentBoth || !reflect.DeepEqual(got.Depends, wantDepends) ||
+		!reflect.DeepEqual(got.Breaks, []Dependency{{ID: "optifine"}}) {
+		t.Errorf("Read() = %+v", got)
+	}
+}
+
+func TestReadWithoutMetadata(t *testing.T) {
+	path := writeJar(t, map[string]string{"com/example/Main.class": ""})
+	if _, err := Read(path); !errors.Is(err, ErrNoMetadata) {
+		t.Errorf("Read() error = %v, want ErrNoMetadata", err)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	metas := []Metadata{{Loader: Fabric, ID: "a"}, {Loader: Forge, ID: "b"}}
+
+	tests := []struct {
+		loader string
+		want   string
+	}{
+		{Fabric, "a"},
+		{Quilt, "a"},
+		{Forge, "b"},
+		{NeoForge, "b"},
+		{"paper", "a"},
+	}
+	for _, tt := range tests {
+		if got := Select(metas, tt.loader); got.ID != tt.want {
+			t.Errorf("Select(%q) = %s, want %s", tt.loader, got.ID, tt.want)
+		}
+	}
+	if got := Select(nil, Fabric); got != nil {
+		t.Errorf("Select(nil) = %+v, want nil", got)
+	}
+}
+
+func TestReadProvides(t *testing.T) {
+	var nested bytes.Buffer
+	w := zip.NewWriter(&nested)
+	fw, err := w.Create(FabricFile)
+	if err != nil {
+		t.Fatalf("Failed to add nested metadata: %v", err)
+	}
+	if _, err := fw.Write([]byte(`{"id": "fabric-api-base", "version": "0.4.42"}`)); err != nil {
+		t.Fatalf("Failed to write nested metadata: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close nested jar: %v", err)
+	}
+
+	path := writeJar(t, map[string]string{
+		FabricFile: `{"id": "fabric-api", "version": "0.100.0", "provides": ["fabric"]}`,
+		"META-INF/jars/fabric-api-base-0.4.42.jar": nested.String(),
+		"META-INF/jars/readme.txt":                 "",
+	})
+
+	metas, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := []Provided{{ID: "fabric", Version: "0.100.0"}, {ID: "fabric-api-base", Version: "0.4.42"}}
+	if len(metas) != 1 || !reflect.DeepEqual(metas[0].Provides, want) {
+		t.Errorf("Read() provides = %+v, want %+v", metas, want)
+	}
+}
+
+func TestReadProvidesNestedJarVersion(t *testing.T) {
+	nested, err := os.ReadFile(writeJar(t, map[string]string{
+		ForgeFile: `
+modLoader = "javafml"
+loaderVersion = "[47,)"
+
+[[mods]]
+modId = "mixinextras"
+version = "${file.jarVersion}"
+`,
+		"META-INF/MANIFEST.MF": "Manifest-Version: 1.0\nImplementation-Version: 0.4.1\n",
+	}))
+	if err != nil {
+		t.Fatalf("Failed to read nested jar: %v", err)
+	}
+
+	path := writeJar(t, map[string]string{
+		ForgeFile: `
+modLoader = "javafml"
+loaderVersion = "[47,)"
+
+[[mods]]
+modId = "create"
+version = "0.5.1"
+`,
+		"META-INF/jarjar/mixinextras-forge-0.4.1.jar": string(nested),
+	})
+
+	metas, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := []Provided{{ID: "mixinextras", Version: "0.4.1"}}
+	if len(metas) != 1 || !reflect.DeepEqual(metas[0].Provides, want) {
+		t.Errorf("Read() provides = %+v, want %+v", metas, want)
+	}
+}
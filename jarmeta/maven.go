@@ -0,0 +1,168 @@
+package jarmeta
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MatchesMavenRange reports whether a version lies in a Maven version range like "[1.0,2.0)", "[47,)",
+// "(,1.20.1]" or "[1.2]", or in any of a comma-separated list of ranges. A bare version is a soft
+// requirement that every version satisfies, as in Forge.
+func MatchesMavenRange(spec, version string) (bool, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || spec == "*" || !strings.ContainsAny(spec[:1], "[(") {
+		return true, nil
+	}
+
+	for spec != "" {
+		end := strings.IndexAny(spec, "])")
+		if end < 0 || !strings.ContainsAny(spec[:1], "[(") {
+			return false, fmt.Errorf("invalid version range %q", spec)
+		}
+		ok, err := inMavenRange(spec[:end+1], version)
+		if err != nil || ok {
+			return ok, err
+		}
+		spec = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(spec[end+1:]), ","))
+	}
+	return false, nil
+}
+
+// inMavenRange checks a single bracketed range.
+func inMavenRange(r, version string) (bool, error) {
+	lowerInclusive, upperInclusive := r[0] == '[', r[len(r)-1] == ']'
+	lower, upper, isRange := strings.Cut(r[1:len(r)-1], ",")
+	lower, upper = strings.TrimSpace(lower), strings.TrimSpace(upper)
+	if !isRange {
+		// [1.2] matches exactly 1.2
+		if !lowerInclusive || !upperInclusive {
+			return false, fmt.Errorf("invalid version range %q", r)
+		}
+		return CompareMavenVersions(version, lower) == 0, nil
+	}
+
+	if lower != "" {
+		c := CompareMavenVersions(version, lower)
+		if c < 0 || (c == 0 && !lowerInclusive) {
+			return false, nil
+		}
+	}
+	if upper != "" {
+		c := CompareMavenVersions(version, upper)
+		if c > 0 || (c == 0 && !upperInclusive) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// releaseRank is the rank of a release, which has no qualifier.
+const releaseRank = 6
+
+// Ranks of the qualifiers Maven knows; other qualifiers sort after them, alphabetically.
+var mavenQualifiers = map[string]int{
+	"alpha": 1, "a": 1,
+	"beta": 2, "b": 2,
+	"milestone": 3, "m": 3,
+	"rc": 4, "cr": 4,
+	"snapshot": 5,
+
+	"": releaseRank, "ga": releaseRank, "final": releaseRank, "release": releaseRank,
+	"sp": releaseRank + 1,
+}
+
+// mavenItem is a number or a qualifier of a Maven version.
+type mavenItem struct {
+	number    int
+	qualifier string
+	isNumber  bool
+}
+
+// CompareMavenVersions compares two versions the way Maven orders them, simplified: numbers compare by
+// value, qualifiers by their known rank, and numbers sort after qualifiers. "1.0" equals "1".
+func CompareMavenVersions(a, b string) int {
+	x, y := parseMavenVersion(a), parseMavenVersion(b)
+	for i := range max(len(x), len(y)) {
+		if c := compareMavenItems(mavenItemAt(x, i, y), mavenItemAt(y, i, x)); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// mavenItemAt returns the item at i, or the padding that compares equal to a trailing "0" or release.
+func mavenItemAt(items []mavenItem, i int, other []mavenItem) mavenItem {
+	if i < len(items) {
+		return items[i]
+	}
+	return mavenItem{isNumber: other[i].isNumber}
+}
+
+func compareMavenItems(a, b mavenItem) int {
+	switch {
+	case a.isNumber && b.isNumber:
+		return compareInts(a.number, b.number)
+	case a.isNumber:
+		return 1
+	case b.isNumber:
+		return -1
+	}
+	rankA, knownA := mavenQualifiers[a.qualifier]
+	rankB, knownB := mavenQualifiers[b.qualifier]
+	switch {
+	case knownA && knownB:
+		return compareInts(rankA, rankB)
+	case knownA:
+		return -1
+	case knownB:
+		return 1
+	default:
+		return strings.Compare(a.qualifier, b.qualifier)
+	}
+}
+
+// parseMavenVersion splits a version at dots, dashes and transitions between digits and letters.
+func parseMavenVersion(version string) []mavenItem {
+	var items []mavenItem
+	var current strings.Builder
+	flush := func() {
+		if current.Len() == 0 {
+			return
+		}
+		s := strings.ToLower(current.String())
+		if n, err := strconv.Atoi(s); err == nil {
+			items = append(items, mavenItem{number: n, isNumber: true})
+		} else {
+			items = append(items, mavenItem{qualifier: s})
+		}
+		current.Reset()
+	}
+
+	var last rune
+	for _, r := range version {
+		switch {
+		case r == '.' || r == '-' || r == '_' || r == '+':
+			flush()
+		case current.Len() > 0 && unicode.IsDigit(r) != unicode.IsDigit(last):
+			flush()
+			current.WriteRune(r)
+		default:
+			current.WriteRune(r)
+		}
+		last = r
+	}
+	flush()
+
+	// Trailing zeros and release qualifiers do not change the version: 1.0.0 equals 1.
+	for len(items) > 1 {
+		tail := items[len(items)-1]
+		if rank, known := mavenQualifiers[tail.qualifier]; (tail.isNumber && tail.number == 0) || (!tail.isNumber && known && rank == releaseRank) {
+			items = items[:len(items)-1]
+			continue
+		}
+		break
+	}
+	return items
+}
@@ -0,0 +1,177 @@
+package jarmeta
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Matches reports whether a version satisfies the dependency's version ranges, read in the syntax of the
+// loader that declared them. A dependency without ranges matches every version.
+func (d Dependency) Matches(loader, version string) (bool, error) {
+	if len(d.Versions) == 0 {
+		return true, nil
+	}
+	var firstErr error
+	for _, r := range d.Versions {
+		var ok bool
+		var err error
+		if loader == Forge || loader == NeoForge {
+			ok, err = MatchesMavenRange(r, version)
+		} else {
+			ok, err = MatchesFabricPredicate(r, version)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, firstErr
+}
+
+// MatchesFabricPredicate reports whether a version satisfies a Fabric version predicate: space-separated
+// terms like ">=1.2.0", "<2", "~1.2", "^1.2", "1.21.x" or "*", all of which must match.
+func MatchesFabricPredicate(predicate, version string) (bool, error) {
+	for _, term := range strings.Fields(predicate) {
+		ok, err := matchesFabricTerm(term, version)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchesFabricTerm(term, version string) (bool, error) {
+	if term == "*" {
+		return true, nil
+	}
+	op := ""
+	for _, candidate := range []string{">=", "<=", ">", "<", "=", "~", "^"} {
+		if rest, ok := strings.CutPrefix(term, candidate); ok {
+			op, term = candidate, rest
+			break
+		}
+	}
+
+	want, wantErr := parseSemver(term)
+	have, haveErr := parseSemver(version)
+	if wantErr != nil || haveErr != nil {
+		// Fabric compares versions that are not semantic versions as plain strings, for equality only.
+		if op == "" || op == "=" {
+			return term == version, nil
+		}
+		return false, fmt.Errorf("cannot compare %q with %q", version, term)
+	}
+
+	cmp := compareSemver(have, want)
+	switch op {
+	case ">=":
+		return cmp >= 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "~":
+		return cmp >= 0 && have.component(0) == want.component(0) && have.component(1) == want.component(1), nil
+	case "^":
+		return cmp >= 0 && have.component(0) == want.component(0), nil
+	default:
+		return cmp == 0, nil
+	}
+}
+
+// semver is a parsed semantic version. Wildcard components ("x", "X" or "*") are -1 and match anything.
+type semver struct {
+	components []int
+	pre        []string
+}
+
+func (v semver) component(i int) int {
+	if i < len(v.components) {
+		return v.components[i]
+	}
+	return 0
+}
+
+func parseSemver(s string) (semver, error) {
+	s, _, _ = strings.Cut(s, "+")
+	core, pre, hasPre := strings.Cut(s, "-")
+	var v semver
+	for _, part := range strings.Split(core, ".") {
+		if part == "x" || part == "X" || part == "*" {
+			v.components = append(v.components, -1)
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return semver{}, fmt.Errorf("invalid version %q", s)
+		}
+		v.components = append(v.components, n)
+	}
+	if hasPre {
+		v.pre = strings.Split(pre, ".")
+	}
+	return v, nil
+}
+
+// compareSemver compares two versions. Missing components count as 0, and a wildcard in either version
+// matches the rest. Pre-releases sort before their release.
+func compareSemver(a, b semver) int {
+	for i := range max(len(a.components), len(b.components)) {
+		x, y := a.component(i), b.component(i)
+		if x == -1 || y == -1 {
+			return 0
+		}
+		if x != y {
+			return compareInts(x, y)
+		}
+	}
+	switch {
+	case len(a.pre) == 0 && len(b.pre) == 0:
+		return 0
+	case len(a.pre) == 0:
+		return 1
+	case len(b.pre) == 0:
+		return -1
+	}
+	for i := range min(len(a.pre), len(b.pre)) {
+		if c := comparePreRelease(a.pre[i], b.pre[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(a.pre), len(b.pre))
+}
+
+// comparePreRelease compares pre-release identifiers: numeric ones by value and before alphanumeric ones.
+func comparePreRelease(a, b string) int {
+	x, errA := strconv.Atoi(a)
+	y, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return compareInts(x, y)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
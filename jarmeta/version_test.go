@@ -0,0 +1,94 @@
+package jarmeta
+
+import "testing"
+
+func TestMatchesFabricPredicate(t *testing.T) {
+	tests := []struct {
+		predicate string
+		version   string
+		want      bool
+	}{
+		{"*", "1.0.0", true},
+		{">=0.16.0", "0.16.9", true},
+		{">=0.16.0", "0.15.11", false},
+		{">=1.21 <1.22", "1.21.4", true},
+		{">=1.21 <1.22", "1.22", false},
+		{"<1.21.2", "1.21.2-rc.1", true},
+		{"1.21.x", "1.21.4", true},
+		{"1.21.x", "1.20.1", false},
+		{"1.21.1", "1.21.1", true},
+		{"=1.21", "1.21.0", true},
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3.0", false},
+		{"^1.2.3", "1.9.0", true},
+		{"^1.2.3", "2.0.0", false},
+		{">1.0.0-beta.2", "1.0.0-beta.10", true},
+		{"1.0.0+build.5", "1.0.0", true},
+		{"24w14a", "24w14a", true},
+	}
+
+	for _, tt := range tests {
+		got, err := MatchesFabricPredicate(tt.predicate, tt.version)
+		if err != nil {
+			t.Errorf("MatchesFabricPredicate(%q, %q) error = %v", tt.predicate, tt.version, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("MatchesFabricPredicate(%q, %q) = %v, want %v", tt.predicate, tt.version, got, tt.want)
+		}
+	}
+
+	if _, err := MatchesFabricPredicate(">=1.0", "24w14a"); err == nil {
+		t.Error("MatchesFabricPredicate() comparing a snapshot should fail")
+	}
+}
+
+func TestMatchesMavenRange(t *testing.T) {
+	tests := []struct {
+		spec    string
+		version string
+		want    bool
+	}{
+		{"[47,)", "47.2.0", true},
+		{"[47,)", "46.0.14", false},
+		{"[1.20.1,1.20.2)", "1.20.1", true},
+		{"[1.20.1,1.20.2)", "1.20.2", false},
+		{"(,1.20.1]", "1.20.1", true},
+		{"(1.0,2.0)", "1.0", false},
+		{"[1.2]", "1.2.0", true},
+		{"[1.0,1.5),[2.0,)", "2.1", true},
+		{"[1.0,1.5),[2.0,)", "1.7", false},
+		{"1.0", "0.1", true},
+		{"[21.1.0-beta,)", "21.1.0", true},
+		{"[1.0-rc1,)", "1.0-beta", false},
+	}
+
+	for _, tt := range tests {
+		got, err := MatchesMavenRange(tt.spec, tt.version)
+		if err != nil {
+			t.Errorf("MatchesMavenRange(%q, %q) error = %v", tt.spec, tt.version, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("MatchesMavenRange(%q, %q) = %v, want %v", tt.spec, tt.version, got, tt.want)
+		}
+	}
+
+	if _, err := MatchesMavenRange("[1.0", "1.0"); err == nil {
+		t.Error("MatchesMavenRange() with an unclosed range should fail")
+	}
+}
+
+func TestDependencyMatches(t *testing.T) {
+	fabric := Dependency{ID: "minecraft", Versions: []string{"1.20.1", "1.21.x"}}
+	if ok, _ := fabric.Matches(Fabric, "1.21.4"); !ok {
+		t.Error("Fabric dependency should match any of its alternatives")
+	}
+	forge := Dependency{ID: "forge", Versions: []string{"[47,)"}}
+	if ok, _ := forge.Matches(Forge, "46.0.1"); ok {
+		t.Error("Forge dependency should use Maven ranges")
+	}
+	if ok, _ := (Dependency{ID: "any"}).Matches(Quilt, "0.1"); !ok {
+		t.Error("Dependency without versions should match every version")
+	}
+}
@@ -3,6 +3,7 @@ package logger
 import (
 	"log"
 	"os"
+	"path/filepath"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -13,6 +14,17 @@ var (
 	ZapLogger *zap.Logger // Expose the raw zap Logger
 )
 
+// FileName is the log file, written to the working directory.
+const FileName = "modrinth-updater.log"
+
+// FilePath returns the absolute path of the log file.
+func FilePath() string {
+	if path, err := filepath.Abs(FileName); err == nil {
+		return path
+	}
+	return FileName
+}
+
 func InitLogger() {
 	// Configure the encoder
 	encoderCfg := zapcore.EncoderConfig{
@@ -33,7 +45,7 @@ func InitLogger() {
 	}
 
 	// Configure the core for file logging
-	logFile, err := os.OpenFile("modrinth-updater.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	logFile, err := os.OpenFile(FileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		log.Fatalf("can't open log file: %v", err)
 	}
@@ -50,7 +62,7 @@ func InitLogger() {
 	ZapLogger = zap.New(core) // Removed AddCaller() and AddStacktrace(zap.ErrorLevel) for cleaner output
 
 	Log = ZapLogger.Sugar()
-	Log.Info("Logger initialized, logging to modrinth-updater.log") // Log initialization message
+	Log.Infof("Logger initialized, logging to %s", FilePath()) // Log initialization message
 
 	// Ensure the deferred Sync happens
 	// The Sync function remains the same, but it should be called on shutdown (e.g., in main.go)
@@ -1,6 +1,7 @@
 package modrinth
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -50,26 +51,44 @@ func NewClient(cfg config.Config) (*Client, error) {
 }
 
 func (c *Client) makeRequest(method, path string, queryParams url.Values, target interface{}, requiresAuth bool, isBinary bool) (*http.Response, error) {
+	return c.makeRequestWithBody(method, path, queryParams, nil, target, requiresAuth, isBinary)
+}
+
+// makeRequestWithBody is makeRequest with a request body, which is encoded as JSON when not nil.
+func (c *Client) makeRequestWithBody(method, path string, queryParams url.Values, body, target interface{}, requiresAuth bool, isBinary bool) (*http.Response, error) {
 	fullURL := c.BaseURL + path
 	if isBinary {
 		// For binary downloads, the 'path' is expected to be the full URL already
 		fullURL = path
 	}
 
-	req, err := http.NewRequest(method, fullURL, nil)
+	var bodyReader io.Reader
+	if body != nil {
+		bodyJSON, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request body: %w", err)
+		}
+		bodyReader = bytes.NewReader(bodyJSON)
+	}
+
+	req, err := http.NewRequest(method, fullURL, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	if queryParams != nil {
 		req.URL.RawQuery = queryParams.Encode()
 	}
 
 	req.Header.Set("User-Agent", c.UserAgent)
-	if requiresAuth {
-		if c.APIKey == "" {
-			return nil, fmt.Errorf("authentication required, but MODRINTH_API_KEY is not set")
-		}
+	if requiresAuth && c.APIKey == "" {
+		return nil, fmt.Errorf("authentication required, but MODRINTH_API_KEY is not set")
+	}
+	// Public endpoints also return private or unlisted projects the key has access to.
+	if c.APIKey != "" && !isBinary {
 		req.Header.Set("Authorization", c.APIKey)
 	}
 
@@ -88,7 +107,7 @@ func (c *Client) makeRequest(method, path string, queryParams url.Values, target
 		// Try to read body for more error info, but don't fail if it's already closed or unreadable
 		bodyBytes, _ := io.ReadAll(resp.Body)
 		resp.Body.Close() // Close body even on error
-		return resp, fmt.Errorf("api request failed: status %d, body: %s", resp.StatusCode, string(bodyBytes))
+		return resp, &APIError{StatusCode: resp.StatusCode, Body: string(bodyBytes)}
 	}
 
 	// Don't try to decode JSON or close body for binary responses here
@@ -97,49 +116,125 @@ func (c *Client) makeRequest(method, path string, queryParams url.Values, target
 		if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
 			return resp, fmt.Errorf("failed to decode json response: %w", err)
 		}
+	} else if !isBinary {
+		resp.Body.Close() // e.g. 204 No Content from follow/unfollow
 	}
 
 	return resp, nil // For binary, return the response so the caller can handle the body
 }
 
 func (c *Client) GetFollowedProjects() ([]Project, error) {
+	user, err := c.GetCurrentUser()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetUserFollows(user.ID)
+}
 
+// GetCurrentUser retrieves the user owning the API key.
+func (c *Client) GetCurrentUser() (*User, error) {
 	var user User
-	_, err := c.makeRequest("GET", "/user", nil, &user, true, false)
-	if err != nil {
+	if _, err := c.makeRequest("GET", "/user", nil, &user, true, false); err != nil {
 		return nil, fmt.Errorf("failed to get current user: %w", err)
 	}
 	if user.ID == "" {
 		return nil, fmt.Errorf("could not determine user ID from API key")
 	}
+	return &user, nil
+}
 
+// GetUserFollows retrieves the projects a user follows.
+func (c *Client) GetUserFollows(userID string) ([]Project, error) {
 	var projects []Project
-	_, err = c.makeRequest("GET", fmt.Sprintf("/user/%s/follows", user.ID), nil, &projects, true, false)
-	if err != nil {
+	if _, err := c.makeRequest("GET", fmt.Sprintf("/user/%s/follows", userID), nil, &projects, true, false); err != nil {
 		return nil, fmt.Errorf("failed to get followed projects: %w", err)
 	}
 	return projects, nil
 }
 
-// GetProjectVersions retrieves versions for a specific project, filtered by game version and loader.
-func (c *Client) GetProjectVersions(slug, projectType, gameVersion, loader string) ([]Version, error) {
+// GetProjectVersions retrieves versions for a specific project, filtered by game versions and loaders.
+// A version matches when it supports any of the given game versions and any of the given loaders.
+// The loaders filter is omitted when no loaders are given.
+func (c *Client) GetProjectVersions(slug string, gameVersions, loaders []string) ([]Version, error) {
 	params := url.Values{}
-	// Construct JSON array strings manually to avoid Sprintf issues
-	params.Add("game_versions", "[\""+gameVersion+"\"]")
+	gameVersionsJSON, err := json.Marshal(gameVersions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode game versions: %w", err)
+	}
+	params.Add("game_versions", string(gameVersionsJSON))
 
-	// Only add loaders parameter if the project type is "mod"
-	if projectType == "mod" {
-		params.Add("loaders", "[\""+loader+"\"]")
+	if len(loaders) > 0 {
+		loadersJSON, err := json.Marshal(loaders)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode loaders: %w", err)
+		}
+		params.Add("loaders", string(loadersJSON))
 	}
 
 	var versions []Version
-	_, err := c.makeRequest("GET", fmt.Sprintf("/project/%s/version", slug), params, &versions, true, false) // Assuming auth might be needed based on Python client
+	_, err = c.makeRequest("GET", fmt.Sprintf("/project/%s/version", slug), params, &versions, false, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get project versions for '%s': %w", slug, err)
 	}
 	return versions, nil
 }
 
+// GetGameVersions retrieves the list of Minecraft versions known to Modrinth, newest first.
+func (c *Client) GetGameVersions() ([]GameVersion, error) {
+	var gameVersions []GameVersion
+	_, err := c.makeRequest("GET", "/tag/game_version", nil, &gameVersions, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game versions: %w", err)
+	}
+	return gameVersions, nil
+}
+
+// GetLoaders retrieves the loaders known to Modrinth.
+func (c *Client) GetLoaders() ([]LoaderTag, error) {
+	var loaders []LoaderTag
+	if _, err := c.makeRequest("GET", "/tag/loader", nil, &loaders, false, false); err != nil {
+		return nil, fmt.Errorf("failed to get loaders: %w", err)
+	}
+	return loaders, nil
+}
+
+// GetVersion retrieves a single version by its ID.
+func (c *Client) GetVersion(id string) (*Version, error) {
+	var version Version
+	_, err := c.makeRequest("GET", fmt.Sprintf("/version/%s", id), nil, &version, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version '%s': %w", id, err)
+	}
+	return &version, nil
+}
+
+// GetProjectVersion retrieves a version of a project by version ID or version number.
+func (c *Client) GetProjectVersion(project, idOrNumber string) (*Version, error) {
+	var version Version
+	path := fmt.Sprintf("/project/%s/version/%s", url.PathEscape(project), url.PathEscape(idOrNumber))
+	if _, err := c.makeRequest("GET", path, nil, &version, false, false); err != nil {
+		return nil, fmt.Errorf("failed to get version '%s' of project '%s': %w", idOrNumber, project, err)
+	}
+	return &version, nil
+}
+
+// GetVersions retrieves multiple versions by their IDs in a single request.
+func (c *Client) GetVersions(ids []string) ([]Version, error) {
+	idsJSON, err := json.Marshal(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode version ids: %w", err)
+	}
+	params := url.Values{}
+	params.Add("ids", string(idsJSON))
+
+	var versions []Version
+	_, err = c.makeRequest("GET", "/versions", params, &versions, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get versions: %w", err)
+	}
+	return versions, nil
+}
+
 // GetVersionByHash retrieves version information using the file's SHA1 hash.
 func (c *Client) GetVersionByHash(hash string) (*Version, error) {
 	var version Version
@@ -150,16 +245,65 @@ func (c *Client) GetVersionByHash(hash string) (*Version, error) {
 	return &version, nil
 }
 
+// GetVersionsByHashes looks up the versions of many files at once. The result maps each known hash to
+// its version; unknown hashes are left out. Algorithm is "sha1" or "sha512".
+func (c *Client) GetVersionsByHashes(hashes []string, algorithm string) (map[string]Version, error) {
+	if len(hashes) == 0 {
+		return map[string]Version{}, nil
+	}
+	body := map[string]interface{}{"hashes": hashes, "algorithm": algorithm}
+	versions := make(map[string]Version)
+	if _, err := c.makeRequestWithBody("POST", "/version_files", nil, body, &versions, false, false); err != nil {
+		return nil, fmt.Errorf("failed to get versions by hashes: %w", err)
+	}
+	return versions, nil
+}
+
 // GetProject retrieves details for a specific project.
 func (c *Client) GetProject(slug string) (*Project, error) {
 	var project Project
-	_, err := c.makeRequest("GET", fmt.Sprintf("/project/%s", slug), nil, &project, true, false) // Assuming auth might be needed
+	_, err := c.makeRequest("GET", fmt.Sprintf("/project/%s", slug), nil, &project, false, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get project '%s': %w", slug, err)
 	}
 	return &project, nil
 }
 
+// GetProjects retrieves details for multiple projects by ID or slug.
+func (c *Client) GetProjects(ids []string) ([]Project, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	idsJSON, err := json.Marshal(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal project ids: %w", err)
+	}
+	params := url.Values{}
+	params.Add("ids", string(idsJSON))
+
+	var projects []Project
+	if _, err := c.makeRequest("GET", "/projects", params, &projects, false, false); err != nil {
+		return nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+	return projects, nil
+}
+
+// FollowProject follows a project as the user owning the API key.
+func (c *Client) FollowProject(id string) error {
+	if _, err := c.makeRequest("POST", fmt.Sprintf("/project/%s/follow", id), nil, nil, true, false); err != nil {
+		return fmt.Errorf("failed to follow project '%s': %w", id, err)
+	}
+	return nil
+}
+
+// UnfollowProject unfollows a project as the user owning the API key.
+func (c *Client) UnfollowProject(id string) error {
+	if _, err := c.makeRequest("DELETE", fmt.Sprintf("/project/%s/follow", id), nil, nil, true, false); err != nil {
+		return fmt.Errorf("failed to unfollow project '%s': %w", id, err)
+	}
+	return nil
+}
+
 // DownloadModFile downloads a mod file from the given URL and saves it to the specified destination path.
 func (c *Client) DownloadModFile(log *zap.SugaredLogger, destinationPath, downloadURL string) error {
 	// Ensure the directory exists (it should have been created by LoadConfig or runUpdate)
@@ -196,6 +340,16 @@ func (c *Client) DownloadModFile(log *zap.SugaredLogger, destinationPath, downlo
 	return nil
 }
 
+// APIError is returned for API responses with a status outside 2xx.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api request failed: status %d, body: %s", e.StatusCode, e.Body)
+}
+
 // --- Structs for API Responses (Basic Definitions) ---
 // These should be expanded based on actual API response structure.
 
@@ -208,26 +362,45 @@ type User struct {
 
 // Project represents a Modrinth project
 type Project struct {
-	Slug        string `json:"slug"`
-	ID          string `json:"id"` // Add Modrinth Project ID
-	Title       string `json:"title"`
-	IconURL     string `json:"icon_url"`     // Add Icon URL
-	Color       int    `json:"color"`        // Add Color (integer representation)
-	Updated     string `json:"updated"`      // Add Last Updated Timestamp (string for simplicity)
-	ProjectType string `json:"project_type"` // e.g., "mod"
-	ClientSide  string `json:"client_side"`  // Added: required, optional, unsupported, unknown
-	ServerSide  string `json:"server_side"`  // Added: required, optional, unsupported, unknown
+	Slug        string   `json:"slug"`
+	ID          string   `json:"id"` // Add Modrinth Project ID
+	Title       string   `json:"title"`
+	IconURL     string   `json:"icon_url"`     // Add Icon URL
+	Color       int      `json:"color"`        // Add Color (integer representation)
+	Updated     string   `json:"updated"`      // Add Last Updated Timestamp (string for simplicity)
+	ProjectType string   `json:"project_type"` // e.g., "mod"
+	ClientSide  string   `json:"client_side"`  // Added: required, optional, unsupported, unknown
+	ServerSide  string   `json:"server_side"`  // Added: required, optional, unsupported, unknown
+	Loaders     []string `json:"loaders"`      // Loaders supported by any version, e.g. "fabric", "datapack"
+	Description string   `json:"description"`
+	Downloads   int      `json:"downloads"`
+	Followers   int      `json:"followers"`
+	SourceURL   string   `json:"source_url"`
+	IssuesURL   string   `json:"issues_url"`
 	// Add other fields as needed (description, etc.)
 }
 
 // Version represents a Modrinth project version (simplified).
 type Version struct {
-	ID            string `json:"id"`
-	ProjectID     string `json:"project_id"`
-	Name          string `json:"name"`
-	VersionNumber string `json:"version_number"`
-	Files         []File `json:"files"`
-	// Add other fields (changelog, dependencies, game_versions, loaders, etc.)
+	ID            string       `json:"id"`
+	ProjectID     string       `json:"project_id"`
+	Name          string       `json:"name"`
+	VersionNumber string       `json:"version_number"`
+	VersionType   string       `json:"version_type"` // release, beta or alpha
+	DatePublished string       `json:"date_published"`
+	GameVersions  []string     `json:"game_versions"`
+	Loaders       []string     `json:"loaders"`
+	Files         []File       `json:"files"`
+	Dependencies  []Dependency `json:"dependencies"`
+	// Add other fields (changelog, etc.)
+}
+
+// Dependency is a dependency of a version on another project or version.
+type Dependency struct {
+	VersionID      string `json:"version_id"`
+	ProjectID      string `json:"project_id"`
+	FileName       string `json:"file_name"`
+	DependencyType string `json:"dependency_type"` // required, optional, incompatible or embedded
 }
 
 // File represents a file within a Modrinth version (simplified).
@@ -239,3 +412,17 @@ type File struct {
 	Hashes   map[string]string `json:"hashes"` // e.g., {"sha512": "...", "sha1": "..."}
 	// Add other fields
 }
+
+// GameVersion represents an entry of Modrinth's game version tag list.
+type GameVersion struct {
+	Version     string `json:"version"`
+	VersionType string `json:"version_type"` // release, snapshot, alpha, beta
+	Date        string `json:"date"`
+	Major       bool   `json:"major"`
+}
+
+// LoaderTag represents an entry of Modrinth's loader tag list.
+type LoaderTag struct {
+	Name                  string   `json:"name"`
+	SupportedProjectTypes []string `json:"supported_project_types"` // e.g. "mod", "modpack", "plugin"
+}
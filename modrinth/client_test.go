@@ -0,0 +1,87 @@
+package modrinth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetVersionsByHashes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/version_files" {
+			t.Errorf("request = %s %s, want POST /version_files", r.Method, r.URL.Path)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		var body struct {
+			Hashes    []string `json:"hashes"`
+			Algorithm string   `json:"algorithm"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		if len(body.Hashes) != 2 || body.Algorithm != "sha1" {
+			t.Errorf("body = %+v", body)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]Version{"aaa": {ID: "v1", ProjectID: "AANobbMI"}})
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, UserAgent: "test", HTTPClient: server.Client()}
+	versions, err := client.GetVersionsByHashes([]string{"aaa", "bbb"}, "sha1")
+	if err != nil {
+		t.Fatalf("GetVersionsByHashes() error = %v", err)
+	}
+	if len(versions) != 1 || versions["aaa"].ProjectID != "AANobbMI" {
+		t.Errorf("GetVersionsByHashes() = %+v", versions)
+	}
+
+	if versions, err := client.GetVersionsByHashes(nil, "sha1"); err != nil || len(versions) != 0 {
+		t.Errorf("GetVersionsByHashes(nil) = %v, %v, want empty", versions, err)
+	}
+}
+
+func TestGetCurrentUserError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "bad-key" {
+			t.Errorf("Authorization = %q, want bad-key", r.Header.Get("Authorization"))
+		}
+		http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, APIKey: "bad-key", UserAgent: "test", HTTPClient: server.Client()}
+	_, err := client.GetCurrentUser()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("GetCurrentUser() error = %v, want APIError with status 401", err)
+	}
+}
+
+func TestGetProjectVersionsWithoutAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/project/sodium/version" {
+			t.Errorf("path = %s, want /project/sodium/version", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("loaders"); got != `["fabric"]` {
+			t.Errorf("loaders = %q, want [\"fabric\"]", got)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Authorization = %q, want none", auth)
+		}
+		_ = json.NewEncoder(w).Encode([]Version{{ID: "v1"}})
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, UserAgent: "test", HTTPClient: server.Client()}
+	versions, err := client.GetProjectVersions("sodium", []string{"1.21.4"}, []string{"fabric"})
+	if err != nil {
+		t.Fatalf("GetProjectVersions() error = %v", err)
+	}
+	if len(versions) != 1 || versions[0].ID != "v1" {
+		t.Errorf("GetProjectVersions() = %+v", versions)
+	}
+}
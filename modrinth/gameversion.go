@@ -0,0 +1,104 @@
+package modrinth
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResolveGameVersions expands a game version expression into the concrete versions known to Modrinth.
+// The expression is a comma-separated list of terms, where each term is one of:
+//   - an exact version ("1.21.4")
+//   - a wildcard ("1.21.x" or "1.21.*"), matching "1.21" and every "1.21.N" release
+//   - an inclusive range ("1.21.2-1.21.4"), matching every release between the two versions
+//
+// known must be ordered newest first, as returned by GetGameVersions. The result keeps that order.
+func ResolveGameVersions(expr string, known []GameVersion) ([]string, error) {
+	index := make(map[string]int, len(known))
+	for i, gv := range known {
+		index[gv.Version] = i
+	}
+
+	selected := make(map[string]bool)
+	for _, term := range strings.Split(expr, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		matches, err := resolveTerm(term, known, index)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range matches {
+			selected[v] = true
+		}
+	}
+
+	var resolved []string
+	for _, gv := range known {
+		if selected[gv.Version] {
+			resolved = append(resolved, gv.Version)
+		}
+	}
+	return resolved, nil
+}
+
+func resolveTerm(term string, known []GameVersion, index map[string]int) ([]string, error) {
+	// Exact versions win over range parsing, since versions such as "1.21.5-pre1" contain dashes.
+	if _, ok := index[term]; ok {
+		return []string{term}, nil
+	}
+
+	if prefix, ok := wildcardPrefix(term); ok {
+		var matches []string
+		for _, gv := range known {
+			if gv.VersionType == "release" && (gv.Version == prefix || strings.HasPrefix(gv.Version, prefix+".")) {
+				matches = append(matches, gv.Version)
+			}
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("game version pattern %q matches no known release", term)
+		}
+		return matches, nil
+	}
+
+	// Try every dash as the range separator until both sides are known versions.
+	for i := strings.Index(term, "-"); i >= 0; {
+		from, to := term[:i], term[i+1:]
+		fromIdx, okFrom := index[from]
+		toIdx, okTo := index[to]
+		if okFrom && okTo {
+			return versionsBetween(known, fromIdx, toIdx), nil
+		}
+		next := strings.Index(term[i+1:], "-")
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+
+	return nil, fmt.Errorf("unknown game version %q", term)
+}
+
+func wildcardPrefix(term string) (string, bool) {
+	for _, suffix := range []string{".x", ".X", ".*"} {
+		if strings.HasSuffix(term, suffix) {
+			return strings.TrimSuffix(term, suffix), true
+		}
+	}
+	return "", false
+}
+
+// versionsBetween returns the endpoints and every release between them.
+// Snapshots and pre-releases are only included when used as an endpoint.
+func versionsBetween(known []GameVersion, a, b int) []string {
+	if a > b {
+		a, b = b, a
+	}
+	var matches []string
+	for i := a; i <= b; i++ {
+		if i == a || i == b || known[i].VersionType == "release" {
+			matches = append(matches, known[i].Version)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,53 @@
+package modrinth
+
+import (
+	"reflect"
+	"testing"
+)
+
+var testGameVersions = []GameVersion{
+	{Version: "1.21.5", VersionType: "release"},
+	{Version: "1.21.5-pre1", VersionType: "beta"},
+	{Version: "25w02a", VersionType: "snapshot"},
+	{Version: "1.21.4", VersionType: "release"},
+	{Version: "1.21.3", VersionType: "release"},
+	{Version: "1.21", VersionType: "release"},
+	{Version: "1.20.6", VersionType: "release"},
+}
+
+func TestResolveGameVersions(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		expected []string
+	}{
+		{"exact", "1.21.4", []string{"1.21.4"}},
+		{"list keeps newest first", "1.20.6, 1.21.4", []string{"1.21.4", "1.20.6"}},
+		{"range skips snapshots", "1.21.4-1.21.5", []string{"1.21.5", "1.21.4"}},
+		{"reversed range", "1.21.5-1.21.3", []string{"1.21.5", "1.21.4", "1.21.3"}},
+		{"range with pre-release endpoint", "1.21.4-1.21.5-pre1", []string{"1.21.5-pre1", "1.21.4"}},
+		{"exact pre-release", "1.21.5-pre1", []string{"1.21.5-pre1"}},
+		{"wildcard", "1.21.x", []string{"1.21.5", "1.21.4", "1.21.3", "1.21"}},
+		{"star wildcard", "1.20.*", []string{"1.20.6"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ResolveGameVersions(tt.expr, testGameVersions)
+			if err != nil {
+				t.Fatalf("ResolveGameVersions(%q) returned error: %v", tt.expr, err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ResolveGameVersions(%q) = %v, want %v", tt.expr, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveGameVersionsUnknown(t *testing.T) {
+	for _, expr := range []string{"1.22", "1.19.x", "1.21.4-1.22"} {
+		if _, err := ResolveGameVersions(expr, testGameVersions); err == nil {
+			t.Errorf("ResolveGameVersions(%q) expected an error", expr)
+		}
+	}
+}
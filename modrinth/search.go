@@ -0,0 +1,107 @@
+package modrinth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// SearchOptions holds the query and filters of a project search.
+// Values within a filter are alternatives; different filters must all match.
+type SearchOptions struct {
+	Query        string
+	Categories   []string // Every category must match
+	GameVersions []string
+	Loaders      []string
+	ProjectType  string   // mod, modpack, resourcepack, shader, datapack or plugin
+	ClientSide   []string // Accepted client_side values, e.g. required and optional
+	ServerSide   []string // Accepted server_side values
+	Index        string   // Sort order: relevance, downloads, follows, newest or updated
+	Offset       int
+	Limit        int
+}
+
+// SearchHit is a single project returned by the search API.
+type SearchHit struct {
+	ProjectID     string   `json:"project_id"`
+	Slug          string   `json:"slug"`
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
+	ProjectType   string   `json:"project_type"`
+	Author        string   `json:"author"`
+	Categories    []string `json:"categories"`
+	Versions      []string `json:"versions"`
+	Downloads     int      `json:"downloads"`
+	Follows       int      `json:"follows"`
+	ClientSide    string   `json:"client_side"`
+	ServerSide    string   `json:"server_side"`
+	IconURL       string   `json:"icon_url"`
+	DateModified  string   `json:"date_modified"`
+	LatestVersion string   `json:"latest_version"`
+}
+
+// SearchResult is a page of search hits.
+type SearchResult struct {
+	Hits      []SearchHit `json:"hits"`
+	Offset    int         `json:"offset"`
+	Limit     int         `json:"limit"`
+	TotalHits int         `json:"total_hits"`
+}
+
+// Facets converts the filters into Modrinth's facet syntax: a list of AND-ed groups of OR-ed conditions.
+func (o SearchOptions) Facets() [][]string {
+	var facets [][]string
+	for _, category := range o.Categories {
+		facets = append(facets, []string{"categories:" + category})
+	}
+	facets = appendFacetGroup(facets, "versions", o.GameVersions)
+	// Loaders are stored as categories in the search index.
+	facets = appendFacetGroup(facets, "categories", o.Loaders)
+	if o.ProjectType != "" {
+		facets = append(facets, []string{"project_type:" + o.ProjectType})
+	}
+	facets = appendFacetGroup(facets, "client_side", o.ClientSide)
+	return appendFacetGroup(facets, "server_side", o.ServerSide)
+}
+
+func appendFacetGroup(facets [][]string, field string, values []string) [][]string {
+	if len(values) == 0 {
+		return facets
+	}
+	group := make([]string, 0, len(values))
+	for _, v := range values {
+		group = append(group, field+":"+v)
+	}
+	return append(facets, group)
+}
+
+// Search queries Modrinth's project search.
+func (c *Client) Search(opts SearchOptions) (*SearchResult, error) {
+	params := url.Values{}
+	if opts.Query != "" {
+		params.Add("query", opts.Query)
+	}
+	if facets := opts.Facets(); len(facets) > 0 {
+		facetsJSON, err := json.Marshal(facets)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal facets: %w", err)
+		}
+		params.Add("facets", string(facetsJSON))
+	}
+	if opts.Index != "" {
+		params.Add("index", opts.Index)
+	}
+	if opts.Offset > 0 {
+		params.Add("offset", strconv.Itoa(opts.Offset))
+	}
+	if opts.Limit > 0 {
+		params.Add("limit", strconv.Itoa(opts.Limit))
+	}
+
+	var result SearchResult
+	if _, err := c.makeRequest("GET", "/search", params, &result, false, false); err != nil {
+		return nil, fmt.Errorf("failed to search projects: %w", err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,60 @@
+package modrinth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestSearchOptionsFacets(t *testing.T) {
+	opts := SearchOptions{
+		Categories:   []string{"optimization", "utility"},
+		GameVersions: []string{"1.21.1", "1.21"},
+		Loaders:      []string{"fabric"},
+		ProjectType:  "mod",
+		ServerSide:   []string{"required", "optional"},
+	}
+	want := [][]string{
+		{"categories:optimization"},
+		{"categories:utility"},
+		{"versions:1.21.1", "versions:1.21"},
+		{"categories:fabric"},
+		{"project_type:mod"},
+		{"server_side:required", "server_side:optional"},
+	}
+
+	if got := opts.Facets(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Facets() = %v, want %v", got, want)
+	}
+	if got := (SearchOptions{Query: "sodium"}).Facets(); got != nil {
+		t.Errorf("Facets() without filters = %v, want nil", got)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" {
+			t.Errorf("path = %s, want /search", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("query") != "sodium" || q.Get("limit") != "5" || q.Get("offset") != "10" || q.Get("index") != "downloads" {
+			t.Errorf("query = %v", q)
+		}
+		if q.Get("facets") != `[["project_type:mod"]]` {
+			t.Errorf("facets = %s", q.Get("facets"))
+		}
+		_ = json.NewEncoder(w).Encode(SearchResult{Hits: []SearchHit{{Slug: "sodium", Title: "Sodium"}}, Offset: 10, Limit: 5, TotalHits: 11})
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, UserAgent: "test", HTTPClient: server.Client()}
+	result, err := client.Search(SearchOptions{Query: "sodium", ProjectType: "mod", Index: "downloads", Offset: 10, Limit: 5})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if result.TotalHits != 11 || len(result.Hits) != 1 || result.Hits[0].Slug != "sodium" {
+		t.Errorf("Search() = %+v", result)
+	}
+}
@@ -0,0 +1,223 @@
+// Package mrpack reads and writes Modrinth modpack (.mrpack) archives.
+// See https://support.modrinth.com/en/articles/8802351-modrinth-modpack-format-mrpack for the format.
+package mrpack
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IndexFileName is the name of the manifest at the root of every .mrpack archive.
+const IndexFileName = "modrinth.index.json"
+
+// Override directories applied on top of the downloaded files, in order.
+const (
+	OverridesDir       = "overrides"
+	ClientOverridesDir = "client-overrides"
+	ServerOverridesDir = "server-overrides"
+)
+
+// Index represents modrinth.index.json.
+type Index struct {
+	FormatVersion int               `json:"formatVersion"`
+	Game          string            `json:"game"`
+	VersionID     string            `json:"versionId"`
+	Name          string            `json:"name"`
+	Summary       string            `json:"summary,omitempty"`
+	Files         []File            `json:"files"`
+	Dependencies  map[string]string `json:"dependencies"`
+}
+
+// File is a single downloadable file of a modpack.
+type File struct {
+	Path      string            `json:"path"`
+	Hashes    map[string]string `json:"hashes"` // sha1 and sha512 are required by the format
+	Env       *Env              `json:"env,omitempty"`
+	Downloads []string          `json:"downloads"`
+	FileSize  int64             `json:"fileSize"`
+}
+
+// Env describes whether a file is required, optional or unsupported on each side.
+type Env struct {
+	Client string `json:"client"`
+	Server string `json:"server"`
+}
+
+// SupportedOn reports whether the file should be installed for the given installation type
+// ("client", "server" or "both"). Files without env information are installed everywhere.
+func (f File) SupportedOn(installationType string) bool {
+	if f.Env == nil {
+		return true
+	}
+	switch strings.ToLower(installationType) {
+	case "client":
+		return f.Env.Client != "unsupported"
+	case "server":
+		return f.Env.Server != "unsupported"
+	default:
+		return f.Env.Client != "unsupported" || f.Env.Server != "unsupported"
+	}
+}
+
+// Optional reports whether the file is optional for the given installation type.
+func (f File) Optional(installationType string) bool {
+	if f.Env == nil {
+		return false
+	}
+	switch strings.ToLower(installationType) {
+	case "client":
+		return f.Env.Client == "optional"
+	case "server":
+		return f.Env.Server == "optional"
+	default:
+		return f.Env.Client == "optional" && f.Env.Server == "optional"
+	}
+}
+
+// loaderDependencies maps loader names to their dependency keys in modrinth.index.json.
+var loaderDependencies = map[string]string{
+	"fabric":   "fabric-loader",
+	"quilt":    "quilt-loader",
+	"forge":    "forge",
+	"neoforge": "neoforge",
+}
+
+// LoaderDependency returns the dependency key used for a loader in modrinth.index.json,
+// or an empty string when the format has no key for it.
+func LoaderDependency(loader string) string {
+	return loaderDependencies[strings.ToLower(loader)]
+}
+
+// Loader returns the loader the pack depends on, or an empty string for vanilla packs.
+func (i Index) Loader() string {
+	for loader, key := range loaderDependencies {
+		if _, ok := i.Dependencies[key]; ok {
+			return loader
+		}
+	}
+	return ""
+}
+
+// ValidatePath rejects paths that would escape the instance directory.
+func ValidatePath(p string) error {
+	if p == "" {
+		return fmt.Errorf("empty path")
+	}
+	if strings.Contains(p, "\\") || path.IsAbs(p) || filepath.IsAbs(p) || filepath.VolumeName(p) != "" {
+		return fmt.Errorf("path %q must be relative and use forward slashes", p)
+	}
+	for _, part := range strings.Split(p, "/") {
+		if part == ".." {
+			return fmt.Errorf("path %q escapes the instance directory", p)
+		}
+	}
+	return nil
+}
+
+// Pack is an opened .mrpack archive.
+type Pack struct {
+	Index  Index
+	reader *zip.ReadCloser
+}
+
+// Open reads the archive at the given path and parses its index.
+func Open(filePath string) (*Pack, error) {
+	r, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open modpack: %w", err)
+	}
+
+	f, err := r.Open(IndexFileName)
+	if err != nil {
+		r.Close()
+		return nil, fmt.Errorf("modpack has no %s: %w", IndexFileName, err)
+	}
+	defer f.Close()
+
+	var index Index
+	if err := json.NewDecoder(f).Decode(&index); err != nil {
+		r.Close()
+		return nil, fmt.Errorf("failed to parse %s: %w", IndexFileName, err)
+	}
+	if index.Game != "minecraft" {
+		r.Close()
+		return nil, fmt.Errorf("unsupported modpack game %q", index.Game)
+	}
+	for _, file := range index.Files {
+		if err := ValidatePath(file.Path); err != nil {
+			r.Close()
+			return nil, err
+		}
+	}
+
+	return &Pack{Index: index, reader: r}, nil
+}
+
+// Close releases the underlying archive.
+func (p *Pack) Close() error {
+	return p.reader.Close()
+}
+
+// OverrideDirs returns the override directories to apply for an installation type, in order.
+func OverrideDirs(installationType string) []string {
+	switch strings.ToLower(installationType) {
+	case "client":
+		return []string{OverridesDir, ClientOverridesDir}
+	case "server":
+		return []string{OverridesDir, ServerOverridesDir}
+	default:
+		return []string{OverridesDir, ClientOverridesDir, ServerOverridesDir}
+	}
+}
+
+// ExtractOverrides copies the override directories for the installation type into dest.
+// It returns the destination-relative paths of the extracted files.
+func (p *Pack) ExtractOverrides(dest, installationType string) ([]string, error) {
+	var extracted []string
+	for _, dir := range OverrideDirs(installationType) {
+		prefix := dir + "/"
+		for _, entry := range p.reader.File {
+			if !strings.HasPrefix(entry.Name, prefix) || entry.FileInfo().IsDir() {
+				continue
+			}
+			rel := strings.TrimPrefix(entry.Name, prefix)
+			if err := ValidatePath(rel); err != nil {
+				return extracted, err
+			}
+			if err := extractEntry(entry, filepath.Join(dest, filepath.FromSlash(rel))); err != nil {
+				return extracted, err
+			}
+			extracted = append(extracted, rel)
+		}
+	}
+	return extracted, nil
+}
+
+func extractEntry(entry *zip.File, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", target, err)
+	}
+
+	src, err := entry.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", entry.Name, err)
+	}
+	defer src.Close()
+
+	out, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", target, err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, src); err != nil {
+		return fmt.Errorf("failed to extract %s: %w", entry.Name, err)
+	}
+	return nil
+}
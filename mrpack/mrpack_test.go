@@ -0,0 +1,191 @@
+package mrpack
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writePack(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pack.mrpack")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create pack: %v", err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, content := range files {
+		entry, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		if _, err := entry.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close pack: %v", err)
+	}
+	return path
+}
+
+const testIndex = `{
+	"formatVersion": 1,
+	"game": "minecraft",
+	"versionId": "1.0.0",
+	"name": "Test Pack",
+	"files": [
+		{
+			"path": "mods/sodium.jar",
+			"hashes": {"sha1": "abc", "sha512": "def"},
+			"env": {"client": "required", "server": "unsupported"},
+			"downloads": ["https://cdn.modrinth.com/data/AANobbMI/versions/x/sodium.jar"],
+			"fileSize": 10
+		}
+	],
+	"dependencies": {"minecraft": "1.21.4", "fabric-loader": "0.16.9"}
+}`
+
+func TestOpen(t *testing.T) {
+	path := writePack(t, map[string]string{IndexFileName: testIndex})
+
+	pack, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+	defer pack.Close()
+
+	if pack.Index.Name != "Test Pack" || len(pack.Index.Files) != 1 {
+		t.Errorf("Unexpected index: %+v", pack.Index)
+	}
+	if loader := pack.Index.Loader(); loader != "fabric" {
+		t.Errorf("Loader() = %q, want fabric", loader)
+	}
+}
+
+func TestOpenRejectsInvalidPacks(t *testing.T) {
+	tests := map[string]map[string]string{
+		"missing index":  {"overrides/config/a.txt": "a"},
+		"other game":     {IndexFileName: `{"game": "terraria", "files": []}`},
+		"escaping path":  {IndexFileName: `{"game": "minecraft", "files": [{"path": "../evil.jar"}]}`},
+		"absolute path":  {IndexFileName: `{"game": "minecraft", "files": [{"path": "/etc/evil.jar"}]}`},
+		"malformed json": {IndexFileName: `{`},
+	}
+
+	for name, files := range tests {
+		t.Run(name, func(t *testing.T) {
+			if pack, err := Open(writePack(t, files)); err == nil {
+				pack.Close()
+				t.Error("Expected Open() to fail")
+			}
+		})
+	}
+}
+
+func TestSupportedOn(t *testing.T) {
+	clientOnly := File{Env: &Env{Client: "required", Server: "unsupported"}}
+	noEnv := File{}
+
+	if !clientOnly.SupportedOn("client") || clientOnly.SupportedOn("server") || !clientOnly.SupportedOn("both") {
+		t.Error("Client-only file has wrong support")
+	}
+	if !noEnv.SupportedOn("client") || !noEnv.SupportedOn("server") {
+		t.Error("Files without env should be supported everywhere")
+	}
+
+	optional := File{Env: &Env{Client: "optional", Server: "required"}}
+	if !optional.Optional("client") || optional.Optional("server") {
+		t.Error("Optional() returned wrong result")
+	}
+}
+
+func TestExtractOverrides(t *testing.T) {
+	path := writePack(t, map[string]string{
+		IndexFileName:                      testIndex,
+		"overrides/config/common.txt":      "common",
+		"client-overrides/options.txt":     "client",
+		"server-overrides/server.txt":      "server",
+		"overrides/config/shared/nested.t": "nested",
+	})
+	pack, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+	defer pack.Close()
+
+	dest := t.TempDir()
+	extracted, err := pack.ExtractOverrides(dest, "client")
+	if err != nil {
+		t.Fatalf("ExtractOverrides() returned error: %v", err)
+	}
+	if len(extracted) != 3 {
+		t.Errorf("Expected 3 extracted files, got %v", extracted)
+	}
+
+	for _, rel := range []string{"config/common.txt", "options.txt", "config/shared/nested.t"} {
+		if _, err := os.Stat(filepath.Join(dest, rel)); err != nil {
+			t.Errorf("Expected %s to be extracted: %v", rel, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dest, "server.txt")); !os.IsNotExist(err) {
+		t.Error("Server overrides should not be applied to clients")
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	instance := t.TempDir()
+	configDir := filepath.Join(instance, "config", "sodium")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "options.json"), []byte("{}"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	index := Index{
+		FormatVersion: 1,
+		Game:          "minecraft",
+		VersionID:     "2.0.0",
+		Name:          "Exported",
+		Files: []File{{
+			Path:      "mods/sodium.jar",
+			Hashes:    map[string]string{"sha1": "abc", "sha512": "def"},
+			Env:       &Env{Client: "required", Server: "unsupported"},
+			Downloads: []string{"https://cdn.modrinth.com/sodium.jar"},
+			FileSize:  10,
+		}},
+		Dependencies: map[string]string{"minecraft": "1.21.4"},
+	}
+
+	packPath := filepath.Join(t.TempDir(), "out.mrpack")
+	out, err := os.Create(packPath)
+	if err != nil {
+		t.Fatalf("Failed to create pack: %v", err)
+	}
+	overrides := []Override{{Source: filepath.Join(instance, "config"), Target: "config", Dir: ClientOverridesDir}}
+	if err := Write(out, index, overrides); err != nil {
+		t.Fatalf("Write() returned error: %v", err)
+	}
+	out.Close()
+
+	pack, err := Open(packPath)
+	if err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+	defer pack.Close()
+
+	if pack.Index.Name != "Exported" || len(pack.Index.Files) != 1 || pack.Index.Files[0].Env.Server != "unsupported" {
+		t.Errorf("Index did not round-trip: %+v", pack.Index)
+	}
+
+	dest := t.TempDir()
+	if _, err := pack.ExtractOverrides(dest, "client"); err != nil {
+		t.Fatalf("ExtractOverrides() returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "config", "sodium", "options.json")); err != nil {
+		t.Errorf("Override was not bundled: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package mrpack
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Override is a directory of the instance bundled into one of the override directories of a pack.
+type Override struct {
+	Source string // Directory on disk
+	Target string // Path relative to the instance root, e.g. "config"
+	Dir    string // OverridesDir, ClientOverridesDir or ServerOverridesDir
+}
+
+// Write creates a .mrpack archive with the given index and override directories.
+func Write(w io.Writer, index Index, overrides []Override) error {
+	zw := zip.NewWriter(w)
+
+	entry, err := zw.Create(IndexFileName)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", IndexFileName, err)
+	}
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(index); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", IndexFileName, err)
+	}
+
+	for _, override := range overrides {
+		if err := ValidatePath(override.Target); err != nil {
+			return err
+		}
+		if err := addOverride(zw, override); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func addOverride(zw *zip.Writer, override Override) error {
+	dir := override.Dir
+	if dir == "" {
+		dir = OverridesDir
+	}
+
+	return filepath.WalkDir(override.Source, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(override.Source, p)
+		if err != nil {
+			return err
+		}
+
+		entry, err := zw.Create(path.Join(dir, override.Target, filepath.ToSlash(rel)))
+		if err != nil {
+			return fmt.Errorf("failed to add override %s: %w", p, err)
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return fmt.Errorf("failed to open override %s: %w", p, err)
+		}
+		defer f.Close()
+
+		_, err = io.Copy(entry, f)
+		return err
+	})
+}
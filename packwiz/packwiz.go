@@ -0,0 +1,280 @@
+// Package packwiz reads and writes packwiz pack trees (pack.toml, index.toml and .pw.toml metafiles).
+// See https://packwiz.infra.link/reference/pack-format/ for the format.
+package packwiz
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+const (
+	// PackFileName is the name of the root pack file.
+	PackFileName = "pack.toml"
+	// IndexFileName is the default name of the index file.
+	IndexFileName = "index.toml"
+	// PackFormat is the pack format version written by this package.
+	PackFormat = "packwiz:1.1.0"
+	// MetafileExt is the extension of per-mod metadata files.
+	MetafileExt = ".pw.toml"
+)
+
+// Pack represents pack.toml.
+type Pack struct {
+	Name       string            `toml:"name"`
+	Author     string            `toml:"author,omitempty"`
+	Version    string            `toml:"version,omitempty"`
+	PackFormat string            `toml:"pack-format"`
+	Index      IndexRef          `toml:"index"`
+	Versions   map[string]string `toml:"versions"`
+}
+
+// IndexRef points pack.toml at the index file.
+type IndexRef struct {
+	File       string `toml:"file"`
+	HashFormat string `toml:"hash-format"`
+	Hash       string `toml:"hash"`
+}
+
+// Index represents index.toml.
+type Index struct {
+	HashFormat string      `toml:"hash-format"`
+	Files      []IndexFile `toml:"files"`
+}
+
+// IndexFile is a single entry of index.toml. Paths are relative to the index file.
+type IndexFile struct {
+	File       string `toml:"file"`
+	Hash       string `toml:"hash"`
+	HashFormat string `toml:"hash-format,omitempty"`
+	Alias      string `toml:"alias,omitempty"`
+	Metafile   bool   `toml:"metafile,omitempty"`
+	Preserve   bool   `toml:"preserve,omitempty"`
+}
+
+// Mod represents a .pw.toml metafile.
+type Mod struct {
+	Name     string   `toml:"name"`
+	FileName string   `toml:"filename"`
+	Side     string   `toml:"side,omitempty"` // both, client or server
+	Download Download `toml:"download"`
+	Update   *Update  `toml:"update,omitempty"`
+}
+
+// Download describes where a mod file is fetched from.
+type Download struct {
+	URL        string `toml:"url,omitempty"`
+	HashFormat string `toml:"hash-format"`
+	Hash       string `toml:"hash"`
+	Mode       string `toml:"mode,omitempty"`
+}
+
+// Update holds the update sources of a mod.
+type Update struct {
+	Modrinth *ModrinthUpdate `toml:"modrinth,omitempty"`
+}
+
+// ModrinthUpdate identifies the Modrinth project and version of a mod.
+type ModrinthUpdate struct {
+	ModID   string `toml:"mod-id"`
+	Version string `toml:"version"`
+}
+
+// SupportedOn reports whether the mod should be installed for an installation type ("client", "server" or "both").
+func (m Mod) SupportedOn(installationType string) bool {
+	switch strings.ToLower(installationType) {
+	case "client", "server":
+		return m.Side == "" || m.Side == "both" || m.Side == strings.ToLower(installationType)
+	default:
+		return true
+	}
+}
+
+// SideFromSupport converts Modrinth's client_side/server_side values into a packwiz side.
+func SideFromSupport(clientSide, serverSide string) string {
+	switch {
+	case clientSide == "unsupported" && serverSide != "unsupported":
+		return "server"
+	case serverSide == "unsupported" && clientSide != "unsupported":
+		return "client"
+	default:
+		return "both"
+	}
+}
+
+// ReadPack parses pack.toml in the given directory, rejecting index paths outside of it.
+func ReadPack(dir string) (*Pack, error) {
+	var pack Pack
+	if err := readTOML(filepath.Join(dir, PackFileName), &pack); err != nil {
+		return nil, err
+	}
+	if pack.Index.File == "" {
+		return nil, fmt.Errorf("%s does not reference an index file", PackFileName)
+	}
+	if err := validatePath(pack.Index.File); err != nil {
+		return nil, err
+	}
+	return &pack, nil
+}
+
+// ReadIndex parses the index referenced by pack, verifying its hash.
+func ReadIndex(dir string, pack *Pack) (*Index, error) {
+	indexPath := filepath.Join(dir, filepath.FromSlash(pack.Index.File))
+	if pack.Index.Hash != "" {
+		if err := VerifyFile(indexPath, pack.Index.HashFormat, pack.Index.Hash); err != nil {
+			return nil, err
+		}
+	}
+
+	var index Index
+	if err := readTOML(indexPath, &index); err != nil {
+		return nil, err
+	}
+	for _, f := range index.Files {
+		if err := validatePath(f.File); err != nil {
+			return nil, err
+		}
+	}
+	return &index, nil
+}
+
+// ReadMod parses a .pw.toml metafile.
+func ReadMod(filePath string) (*Mod, error) {
+	var mod Mod
+	if err := readTOML(filePath, &mod); err != nil {
+		return nil, err
+	}
+	if mod.FileName == "" || strings.ContainsAny(mod.FileName, `/\`) {
+		return nil, fmt.Errorf("%s has an invalid filename %q", filePath, mod.FileName)
+	}
+	return &mod, nil
+}
+
+// Write creates a pack tree in dir. mods maps slash-separated metafile paths (e.g. "mods/sodium.pw.toml")
+// to their contents. The index and pack hashes are computed from the written files.
+func Write(dir string, pack Pack, mods map[string]Mod) error {
+	paths := make([]string, 0, len(mods))
+	for p := range mods {
+		if err := validatePath(p); err != nil {
+			return err
+		}
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	index := Index{HashFormat: "sha256"}
+	for _, p := range paths {
+		target := filepath.Join(dir, filepath.FromSlash(p))
+		if err := writeTOML(target, mods[p]); err != nil {
+			return err
+		}
+		sum, err := HashFile(target, index.HashFormat)
+		if err != nil {
+			return err
+		}
+		index.Files = append(index.Files, IndexFile{File: p, Hash: sum, Metafile: true})
+	}
+
+	indexPath := filepath.Join(dir, IndexFileName)
+	if err := writeTOML(indexPath, index); err != nil {
+		return err
+	}
+	indexHash, err := HashFile(indexPath, "sha256")
+	if err != nil {
+		return err
+	}
+
+	pack.PackFormat = PackFormat
+	pack.Index = IndexRef{File: IndexFileName, HashFormat: "sha256", Hash: indexHash}
+	return writeTOML(filepath.Join(dir, PackFileName), pack)
+}
+
+// HashFile hashes a file using a packwiz hash format (sha1, sha256, sha512 or md5).
+func HashFile(filePath, format string) (string, error) {
+	var h hash.Hash
+	switch strings.ToLower(format) {
+	case "sha1":
+		h = sha1.New()
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	case "md5":
+		h = md5.New()
+	default:
+		return "", fmt.Errorf("unsupported hash format %q", format)
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifyFile checks a file against the expected hash.
+func VerifyFile(filePath, format, expected string) error {
+	actual, err := HashFile(filePath, format)
+	if err != nil {
+		return fmt.Errorf("failed to hash %s: %w", filePath, err)
+	}
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("%s mismatch for %s: expected %s, got %s", format, filepath.Base(filePath), expected, actual)
+	}
+	return nil
+}
+
+// validatePath rejects index paths that would escape the pack directory.
+func validatePath(p string) error {
+	if p == "" || path.IsAbs(p) || strings.Contains(p, `\`) {
+		return fmt.Errorf("invalid pack path %q", p)
+	}
+	for _, part := range strings.Split(p, "/") {
+		if part == ".." {
+			return fmt.Errorf("pack path %q escapes the pack directory", p)
+		}
+	}
+	return nil
+}
+
+func readTOML(filePath string, v interface{}) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	if err := toml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+	return nil
+}
+
+func writeTOML(filePath string, v interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", filePath, err)
+	}
+	data, err := toml.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filePath, err)
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package packwiz
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteAndRead(t *testing.T) {
+	dir := t.TempDir()
+	mods := map[string]Mod{
+		"mods/sodium.pw.toml": {
+			Name:     "Sodium",
+			FileName: "sodium-fabric-0.6.jar",
+			Side:     "client",
+			Download: Download{URL: "https://cdn.modrinth.com/sodium.jar", HashFormat: "sha512", Hash: "abc"},
+			Update:   &Update{Modrinth: &ModrinthUpdate{ModID: "AANobbMI", Version: "v1"}},
+		},
+	}
+	pack := Pack{Name: "Test", Version: "1.0.0", Versions: map[string]string{"minecraft": "1.21.4", "fabric": "0.16.9"}}
+
+	if err := Write(dir, pack, mods); err != nil {
+		t.Fatalf("Write() returned error: %v", err)
+	}
+
+	readPack, err := ReadPack(dir)
+	if err != nil {
+		t.Fatalf("ReadPack() returned error: %v", err)
+	}
+	if readPack.PackFormat != PackFormat || readPack.Versions["fabric"] != "0.16.9" {
+		t.Errorf("Unexpected pack: %+v", readPack)
+	}
+
+	index, err := ReadIndex(dir, readPack)
+	if err != nil {
+		t.Fatalf("ReadIndex() returned error: %v", err)
+	}
+	if len(index.Files) != 1 || !index.Files[0].Metafile || index.Files[0].File != "mods/sodium.pw.toml" {
+		t.Fatalf("Unexpected index: %+v", index)
+	}
+
+	mod, err := ReadMod(filepath.Join(dir, "mods", "sodium.pw.toml"))
+	if err != nil {
+		t.Fatalf("ReadMod() returned error: %v", err)
+	}
+	if mod.FileName != "sodium-fabric-0.6.jar" || mod.Update.Modrinth.ModID != "AANobbMI" || mod.Download.Hash != "abc" {
+		t.Errorf("Unexpected mod: %+v", mod)
+	}
+}
+
+func TestReadIndexDetectsTampering(t *testing.T) {
+	dir := t.TempDir()
+	if err := Write(dir, Pack{Name: "Test"}, map[string]Mod{}); err != nil {
+		t.Fatalf("Write() returned error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, IndexFileName), []byte("hash-format = \"sha1\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to modify index: %v", err)
+	}
+
+	pack, err := ReadPack(dir)
+	if err != nil {
+		t.Fatalf("ReadPack() returned error: %v", err)
+	}
+	if _, err := ReadIndex(dir, pack); err == nil {
+		t.Error("Expected ReadIndex() to reject a modified index")
+	}
+}
+
+func TestWriteRejectsEscapingPaths(t *testing.T) {
+	if err := Write(t.TempDir(), Pack{}, map[string]Mod{"../evil.pw.toml": {}}); err == nil {
+		t.Error("Expected Write() to reject escaping paths")
+	}
+}
+
+func TestReadPackRejectsEscapingIndex(t *testing.T) {
+	dir := t.TempDir()
+	pack := "name = \"Test\"\npack-format = \"packwiz:1.1.0\"\n\n[index]\nfile = \"../../x/index.toml\"\nhash-format = \"sha256\"\nhash = \"\"\n"
+	if err := os.WriteFile(filepath.Join(dir, PackFileName), []byte(pack), 0644); err != nil {
+		t.Fatalf("Failed to write pack: %v", err)
+	}
+	if _, err := ReadPack(dir); err == nil {
+		t.Error("ReadPack() expected error for an index outside the pack directory")
+	}
+}
+
+func TestSideFromSupport(t *testing.T) {
+	tests := []struct {
+		client, server, expected string
+	}{
+		{"required", "unsupported", "client"},
+		{"unsupported", "required", "server"},
+		{"optional", "required", "both"},
+		{"", "", "both"},
+	}
+	for _, tt := range tests {
+		if result := SideFromSupport(tt.client, tt.server); result != tt.expected {
+			t.Errorf("SideFromSupport(%q, %q) = %q, want %q", tt.client, tt.server, result, tt.expected)
+		}
+	}
+}
+
+func TestModSupportedOn(t *testing.T) {
+	clientMod := Mod{Side: "client"}
+	if !clientMod.SupportedOn("client") || clientMod.SupportedOn("server") || !clientMod.SupportedOn("both") {
+		t.Error("Client mod has wrong support")
+	}
+	if !(Mod{}).SupportedOn("server") {
+		t.Error("Mods without a side should be supported everywhere")
+	}
+}
@@ -0,0 +1,290 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// CurseForgeName is the provider name of CurseForge.
+	CurseForgeName   = "curseforge"
+	curseForgeAPIURL = "https://api.curseforge.com"
+	// curseForgeMinecraftID is CurseForge's game ID for Minecraft.
+	curseForgeMinecraftID = 432
+)
+
+// ErrDistributionNotAllowed is returned when a CurseForge project does not permit downloads through third-party tools.
+var ErrDistributionNotAllowed = errors.New("project does not allow third-party distribution")
+
+// curseForgeLoaderTypes maps loader names to CurseForge's modLoaderType values.
+var curseForgeLoaderTypes = map[string]int{
+	"forge":      1,
+	"liteloader": 3,
+	"fabric":     4,
+	"quilt":      5,
+	"neoforge":   6,
+}
+
+// curseForgeClassTypes maps CurseForge class IDs to project types.
+var curseForgeClassTypes = map[int]string{
+	5:    "plugin",
+	6:    "mod",
+	12:   "resourcepack",
+	6552: "shader",
+	6945: "datapack",
+}
+
+// CurseForge installs files from CurseForge. Refs are numeric project (mod) IDs.
+type CurseForge struct {
+	BaseURL    string
+	APIKey     string
+	UserAgent  string
+	HTTPClient *http.Client
+}
+
+// NewCurseForge creates a CurseForge provider. An empty baseURL selects the public CurseForge API.
+func NewCurseForge(baseURL, apiKey, userAgent string) *CurseForge {
+	if baseURL == "" {
+		baseURL = curseForgeAPIURL
+	}
+	return &CurseForge{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		APIKey:     apiKey,
+		UserAgent:  userAgent,
+		HTTPClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// curseForgeMod is the subset of a CurseForge mod used here.
+type curseForgeMod struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Slug    string `json:"slug"`
+	ClassID int    `json:"classId"`
+	Logo    *struct {
+		URL string `json:"url"`
+	} `json:"logo"`
+}
+
+// curseForgeFile is the subset of a CurseForge file used here.
+type curseForgeFile struct {
+	ID           int       `json:"id"`
+	ModID        int       `json:"modId"`
+	DisplayName  string    `json:"displayName"`
+	FileName     string    `json:"fileName"`
+	ReleaseType  int       `json:"releaseType"` // 1 release, 2 beta, 3 alpha
+	FileDate     time.Time `json:"fileDate"`
+	FileLength   int64     `json:"fileLength"`
+	DownloadURL  string    `json:"downloadUrl"`
+	GameVersions []string  `json:"gameVersions"`
+	Hashes       []struct {
+		Value string `json:"value"`
+		Algo  int    `json:"algo"` // 1 sha1, 2 md5
+	} `json:"hashes"`
+}
+
+// Name implements Provider.
+func (c *CurseForge) Name() string {
+	return CurseForgeName
+}
+
+// ListVersions implements Provider. CurseForge filters by a single game version, so each
+// filtered game version is tried in order until one has files.
+func (c *CurseForge) ListVersions(ref string, filter Filter) ([]Version, error) {
+	gameVersions := filter.GameVersions
+	if len(gameVersions) == 0 {
+		gameVersions = []string{""}
+	}
+
+	for _, gameVersion := range gameVersions {
+		query := url.Values{}
+		if gameVersion != "" {
+			query.Set("gameVersion", gameVersion)
+		}
+		for _, loader := range filter.Loaders {
+			if loaderType, ok := curseForgeLoaderTypes[strings.ToLower(loader)]; ok {
+				query.Set("modLoaderType", strconv.Itoa(loaderType))
+				break
+			}
+		}
+
+		var resp struct {
+			Data []curseForgeFile `json:"data"`
+		}
+		if err := c.get(fmt.Sprintf("/v1/mods/%s/files?%s", url.PathEscape(ref), query.Encode()), &resp); err != nil {
+			return nil, fmt.Errorf("failed to list files of CurseForge project %s: %w", ref, err)
+		}
+		if len(resp.Data) == 0 {
+			continue
+		}
+
+		sort.SliceStable(resp.Data, func(i, j int) bool { return resp.Data[i].FileDate.After(resp.Data[j].FileDate) })
+		versions := make([]Version, 0, len(resp.Data))
+		for _, f := range resp.Data {
+			versions = append(versions, f.version())
+		}
+		return versions, nil
+	}
+	return nil, nil
+}
+
+// ResolveFile implements Provider. Files of projects that disallow third-party distribution
+// have no download URL and yield ErrDistributionNotAllowed.
+func (c *CurseForge) ResolveFile(v Version) (*File, error) {
+	f, err := PrimaryFile(v)
+	if err != nil {
+		return nil, err
+	}
+	if f.URL == "" {
+		return nil, fmt.Errorf("%s: %w", f.Filename, ErrDistributionNotAllowed)
+	}
+	return f, nil
+}
+
+// Download implements Provider.
+func (c *CurseForge) Download(_ *zap.SugaredLogger, destinationPath string, f File) error {
+	if f.URL == "" {
+		return fmt.Errorf("%s: %w", f.Filename, ErrDistributionNotAllowed)
+	}
+	return downloadTo(c.HTTPClient, f.URL, destinationPath, map[string]string{"User-Agent": c.UserAgent})
+}
+
+// IdentifyFile implements Provider by matching the file's CurseForge fingerprint.
+func (c *CurseForge) IdentifyFile(path string) (*Match, error) {
+	fingerprint, err := CurseForgeFingerprint(path)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(map[string][]uint32{"fingerprints": {fingerprint}})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data struct {
+			ExactMatches []struct {
+				ID   int            `json:"id"`
+				File curseForgeFile `json:"file"`
+			} `json:"exactMatches"`
+		} `json:"data"`
+	}
+	if err := c.do(http.MethodPost, fmt.Sprintf("/v1/fingerprints/%d", curseForgeMinecraftID), body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to match fingerprint: %w", err)
+	}
+	if len(resp.Data.ExactMatches) == 0 {
+		return nil, fmt.Errorf("fingerprint %d: %w", fingerprint, ErrNotFound)
+	}
+	match := resp.Data.ExactMatches[0]
+
+	project, err := c.project(match.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &Match{Project: project, Version: match.File.version()}, nil
+}
+
+// project fetches the display metadata of a CurseForge project.
+func (c *CurseForge) project(id int) (Project, error) {
+	var resp struct {
+		Data curseForgeMod `json:"data"`
+	}
+	if err := c.get(fmt.Sprintf("/v1/mods/%d", id), &resp); err != nil {
+		return Project{}, fmt.Errorf("failed to get CurseForge project %d: %w", id, err)
+	}
+	mod := resp.Data
+	project := Project{
+		Ref:         strconv.Itoa(mod.ID),
+		ID:          strconv.Itoa(mod.ID),
+		Slug:        mod.Slug,
+		Title:       mod.Name,
+		ProjectType: curseForgeClassTypes[mod.ClassID],
+	}
+	if mod.Logo != nil {
+		project.IconURL = mod.Logo.URL
+	}
+	return project, nil
+}
+
+func (c *CurseForge) get(path string, target interface{}) error {
+	return c.do(http.MethodGet, path, nil, target)
+}
+
+func (c *CurseForge) do(method, path string, body []byte, target interface{}) error {
+	if c.APIKey == "" {
+		return fmt.Errorf("authentication required, but CURSEFORGE_API_KEY is not set")
+	}
+	req, err := http.NewRequest(method, c.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.UserAgent)
+	req.Header.Set("x-api-key", c.APIKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s: %w", path, ErrNotFound)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("api request failed: status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("failed to decode json response: %w", err)
+	}
+	return nil
+}
+
+// version converts a CurseForge file; each file is its own version.
+func (f curseForgeFile) version() Version {
+	versionType := "release"
+	switch f.ReleaseType {
+	case 2:
+		versionType = "beta"
+	case 3:
+		versionType = "alpha"
+	}
+
+	file := File{Filename: f.FileName, URL: f.DownloadURL, Primary: true, Size: f.FileLength, Hashes: map[string]string{}}
+	for _, h := range f.Hashes {
+		if h.Algo == 1 {
+			file.Hashes["sha1"] = h.Value
+		}
+	}
+
+	var gameVersions, loaders []string
+	for _, v := range f.GameVersions {
+		if _, ok := curseForgeLoaderTypes[strings.ToLower(v)]; ok {
+			loaders = append(loaders, strings.ToLower(v))
+		} else if v != "" && v[0] >= '0' && v[0] <= '9' {
+			gameVersions = append(gameVersions, v)
+		}
+	}
+
+	return Version{
+		ID:            strconv.Itoa(f.ID),
+		ProjectID:     strconv.Itoa(f.ModID),
+		VersionNumber: strings.TrimSuffix(f.DisplayName, ".jar"),
+		VersionType:   versionType,
+		GameVersions:  gameVersions,
+		Loaders:       loaders,
+		Published:     f.FileDate,
+		Files:         []File{file},
+	}
+}
@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// GitHubName is the provider name of GitHub Releases.
+	GitHubName   = "github"
+	gitHubAPIURL = "https://api.github.com"
+)
+
+// GitHub installs release assets from GitHub repositories. Refs have the form "owner/repo".
+type GitHub struct {
+	BaseURL    string
+	Token      string
+	UserAgent  string
+	HTTPClient *http.Client
+}
+
+// NewGitHub creates a GitHub Releases provider. An empty baseURL selects the public GitHub API;
+// the token is optional and only raises the rate limit or grants access to private repositories.
+func NewGitHub(baseURL, token, userAgent string) *GitHub {
+	if baseURL == "" {
+		baseURL = gitHubAPIURL
+	}
+	return &GitHub{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Token:      token,
+		UserAgent:  userAgent,
+		HTTPClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// gitHubRelease is an entry of GET /repos/{owner}/{repo}/releases.
+type gitHubRelease struct {
+	ID          int64         `json:"id"`
+	TagName     string        `json:"tag_name"`
+	Name        string        `json:"name"`
+	Body        string        `json:"body"`
+	Draft       bool          `json:"draft"`
+	Prerelease  bool          `json:"prerelease"`
+	PublishedAt time.Time     `json:"published_at"`
+	Assets      []gitHubAsset `json:"assets"`
+}
+
+type gitHubAsset struct {
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// Name implements Provider.
+func (g *GitHub) Name() string {
+	return GitHubName
+}
+
+// ListVersions implements Provider. Releases carry no structured compatibility data, so assets whose
+// name mentions another loader than the filtered ones are left out, and releases whose name, tag, notes
+// or asset names mention a filtered game version or loader are preferred.
+func (g *GitHub) ListVersions(ref string, filter Filter) ([]Version, error) {
+	if strings.Count(ref, "/") != 1 {
+		return nil, fmt.Errorf("invalid GitHub reference %q, expected owner/repo", ref)
+	}
+
+	resp, err := httpGet(g.HTTPClient, fmt.Sprintf("%s/repos/%s/releases", g.BaseURL, ref), g.headers("application/vnd.github+json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases of %s: %w", ref, err)
+	}
+	defer resp.Body.Close()
+
+	var releases []gitHubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to decode releases of %s: %w", ref, err)
+	}
+
+	var versions []Version
+	for _, r := range releases {
+		if r.Draft {
+			continue
+		}
+		v := Version{
+			ID:            fmt.Sprintf("%d", r.ID),
+			ProjectID:     ref,
+			VersionNumber: strings.TrimPrefix(r.TagName, "v"),
+			VersionType:   "release",
+			Published:     r.PublishedAt,
+		}
+		if r.Prerelease {
+			v.VersionType = "beta"
+		}
+		for _, a := range r.Assets {
+			if isModAsset(a.Name) && namesLoader(a.Name, filter.Loaders) {
+				v.Files = append(v.Files, File{Filename: a.Name, URL: a.BrowserDownloadURL, Size: a.Size})
+			}
+		}
+		if len(v.Files) > 0 {
+			versions = append(versions, v)
+		}
+	}
+
+	describe := func(v Version) string {
+		for _, r := range releases {
+			if fmt.Sprintf("%d", r.ID) == v.ID {
+				return strings.Join([]string{r.TagName, r.Name, r.Body, assetNames(r.Assets)}, " ")
+			}
+		}
+		return ""
+	}
+	return filterMentioned(versions, filter, describe), nil
+}
+
+// ResolveFile implements Provider. Release assets are never marked primary, so the first jar left by the
+// loader filter of ListVersions is used.
+func (g *GitHub) ResolveFile(v Version) (*File, error) {
+	return PrimaryFile(v)
+}
+
+// Download implements Provider.
+func (g *GitHub) Download(_ *zap.SugaredLogger, destinationPath string, f File) error {
+	return downloadTo(g.HTTPClient, f.URL, destinationPath, g.headers("application/octet-stream"))
+}
+
+// IdentifyFile implements Provider. GitHub has no hash lookup.
+func (g *GitHub) IdentifyFile(string) (*Match, error) {
+	return nil, ErrNotSupported
+}
+
+func (g *GitHub) headers(accept string) map[string]string {
+	headers := map[string]string{"Accept": accept, "User-Agent": g.UserAgent}
+	if g.Token != "" {
+		headers["Authorization"] = "Bearer " + g.Token
+	}
+	return headers
+}
+
+// isModAsset reports whether a release asset is an installable jar rather than sources or docs.
+func isModAsset(name string) bool {
+	lower := strings.ToLower(name)
+	if !strings.HasSuffix(lower, ".jar") {
+		return false
+	}
+	for _, suffix := range []string{"-sources.jar", "-dev.jar", "-javadoc.jar", "-api.jar"} {
+		if strings.HasSuffix(lower, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+func assetNames(assets []gitHubAsset) string {
+	names := make([]string, 0, len(assets))
+	for _, a := range assets {
+		names = append(names, a.Name)
+	}
+	return strings.Join(names, " ")
+}
@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// MavenName is the provider name of plain Maven repositories.
+const MavenName = "maven"
+
+// Maven installs artifacts from Maven repositories. Refs have the form
+// "<repository-url>/<group>:<artifact>", e.g. "https://maven.example.com/releases/com.example:mymod".
+type Maven struct {
+	UserAgent  string
+	HTTPClient *http.Client
+}
+
+// NewMaven creates a Maven provider.
+func NewMaven(userAgent string) *Maven {
+	return &Maven{UserAgent: userAgent, HTTPClient: &http.Client{Timeout: defaultTimeout}}
+}
+
+// mavenMetadata is the artifact-level maven-metadata.xml.
+type mavenMetadata struct {
+	Versioning struct {
+		Release  string   `xml:"release"`
+		Versions []string `xml:"versions>version"`
+	} `xml:"versioning"`
+}
+
+// mavenRef is a parsed Maven reference.
+type mavenRef struct {
+	Repository string
+	Group      string
+	Artifact   string
+}
+
+// ParseMavenRef splits a Maven reference into repository URL, group and artifact.
+func ParseMavenRef(ref string) (repository, group, artifact string, err error) {
+	r, err := parseMavenRef(ref)
+	if err != nil {
+		return "", "", "", err
+	}
+	return r.Repository, r.Group, r.Artifact, nil
+}
+
+func parseMavenRef(ref string) (mavenRef, error) {
+	slash := strings.LastIndex(ref, "/")
+	if slash <= 0 {
+		return mavenRef{}, fmt.Errorf("invalid Maven reference %q, expected <repository-url>/<group>:<artifact>", ref)
+	}
+	group, artifact, ok := strings.Cut(ref[slash+1:], ":")
+	if !ok || group == "" || artifact == "" {
+		return mavenRef{}, fmt.Errorf("invalid Maven coordinates in %q, expected <group>:<artifact>", ref)
+	}
+	return mavenRef{Repository: strings.TrimRight(ref[:slash], "/"), Group: group, Artifact: artifact}, nil
+}
+
+// artifactURL returns the directory URL of the artifact.
+func (r mavenRef) artifactURL() string {
+	return fmt.Sprintf("%s/%s/%s", r.Repository, strings.ReplaceAll(r.Group, ".", "/"), r.Artifact)
+}
+
+// Name implements Provider.
+func (m *Maven) Name() string {
+	return MavenName
+}
+
+// ListVersions implements Provider. Versions are read from maven-metadata.xml, newest first. Versions
+// whose number mentions another loader than the filtered ones are left out, and versions whose number
+// mentions a filtered game version or loader are preferred.
+func (m *Maven) ListVersions(ref string, filter Filter) ([]Version, error) {
+	r, err := parseMavenRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpGet(m.HTTPClient, r.artifactURL()+"/maven-metadata.xml", m.headers())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Maven metadata of %s: %w", ref, err)
+	}
+	defer resp.Body.Close()
+
+	var metadata mavenMetadata
+	if err := xml.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("failed to decode Maven metadata of %s: %w", ref, err)
+	}
+
+	// maven-metadata.xml lists versions oldest first.
+	numbers := slices.Clone(metadata.Versioning.Versions)
+	slices.Reverse(numbers)
+
+	versions := make([]Version, 0, len(numbers))
+	for _, number := range numbers {
+		if !namesLoader(number, filter.Loaders) {
+			continue
+		}
+		fileName := fmt.Sprintf("%s-%s.jar", r.Artifact, number)
+		versionType := "release"
+		if strings.Contains(strings.ToUpper(number), "SNAPSHOT") {
+			versionType = "alpha"
+		}
+		versions = append(versions, Version{
+			ID:            number,
+			ProjectID:     ref,
+			VersionNumber: number,
+			VersionType:   versionType,
+			Files: []File{{
+				Filename: fileName,
+				URL:      fmt.Sprintf("%s/%s/%s", r.artifactURL(), number, fileName),
+				Primary:  true,
+			}},
+		})
+	}
+
+	return filterMentioned(versions, filter, func(v Version) string { return v.VersionNumber }), nil
+}
+
+// ResolveFile implements Provider. The artifact's published .sha1 checksum is attached when available.
+func (m *Maven) ResolveFile(v Version) (*File, error) {
+	f, err := PrimaryFile(v)
+	if err != nil {
+		return nil, err
+	}
+	resolved := *f
+	if resp, err := httpGet(m.HTTPClient, f.URL+".sha1", m.headers()); err == nil {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		// Checksum files may contain "<hash>  <filename>".
+		if fields := strings.Fields(string(body)); len(fields) > 0 {
+			resolved.Hashes = map[string]string{"sha1": strings.ToLower(fields[0])}
+		}
+	}
+	return &resolved, nil
+}
+
+// Download implements Provider.
+func (m *Maven) Download(_ *zap.SugaredLogger, destinationPath string, f File) error {
+	return downloadTo(m.HTTPClient, f.URL, destinationPath, m.headers())
+}
+
+// IdentifyFile implements Provider. Maven repositories have no hash lookup.
+func (m *Maven) IdentifyFile(string) (*Match, error) {
+	return nil, ErrNotSupported
+}
+
+func (m *Maven) headers() map[string]string {
+	return map[string]string{"User-Agent": m.UserAgent}
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"modrinth-mod-updater/modrinth"
+
+	"go.uber.org/zap"
+)
+
+// ModrinthName is the provider name of Modrinth, the default source of db.Mod rows.
+const ModrinthName = "modrinth"
+
+// Modrinth adapts the Modrinth API client to the Provider interface. Refs are project slugs or IDs.
+type Modrinth struct {
+	Client *modrinth.Client
+}
+
+// NewModrinth creates a Modrinth provider backed by client.
+func NewModrinth(client *modrinth.Client) *Modrinth {
+	return &Modrinth{Client: client}
+}
+
+// Name implements Provider.
+func (m *Modrinth) Name() string {
+	return ModrinthName
+}
+
+// ListVersions implements Provider.
+func (m *Modrinth) ListVersions(ref string, filter Filter) ([]Version, error) {
+	versions, err := m.Client.GetProjectVersions(ref, filter.GameVersions, filter.Loaders)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Version, 0, len(versions))
+	for _, v := range versions {
+		result = append(result, FromModrinthVersion(v))
+	}
+	return result, nil
+}
+
+// ResolveFile implements Provider.
+func (m *Modrinth) ResolveFile(v Version) (*File, error) {
+	return PrimaryFile(v)
+}
+
+// Download implements Provider.
+func (m *Modrinth) Download(log *zap.SugaredLogger, destinationPath string, f File) error {
+	return m.Client.DownloadModFile(log, destinationPath, f.URL)
+}
+
+// IdentifyFile implements Provider by looking up the file's SHA1 hash.
+func (m *Modrinth) IdentifyFile(path string) (*Match, error) {
+	sum, err := sha1File(path)
+	if err != nil {
+		return nil, err
+	}
+	version, err := m.Client.GetVersionByHash(sum)
+	if err != nil {
+		return nil, errors.Join(ErrNotFound, err)
+	}
+	project, err := m.Client.GetProject(version.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project details: %w", err)
+	}
+	return &Match{
+		Project: Project{
+			Ref:         project.Slug,
+			ID:          project.ID,
+			Slug:        project.Slug,
+			Title:       project.Title,
+			ProjectType: project.ProjectType,
+			IconURL:     project.IconURL,
+			Color:       project.Color,
+			ClientSide:  project.ClientSide,
+			ServerSide:  project.ServerSide,
+		},
+		Version: FromModrinthVersion(*version),
+	}, nil
+}
+
+// FromModrinthVersion converts a Modrinth API version.
+func FromModrinthVersion(v modrinth.Version) Version {
+	published, _ := time.Parse(time.RFC3339Nano, v.DatePublished)
+	files := make([]File, 0, len(v.Files))
+	for _, f := range v.Files {
+		files = append(files, File{Filename: f.Filename, URL: f.URL, Primary: f.Primary, Size: int64(f.Size), Hashes: f.Hashes})
+	}
+	return Version{
+		ID:            v.ID,
+		ProjectID:     v.ProjectID,
+		VersionNumber: v.VersionNumber,
+		VersionType:   v.VersionType,
+		GameVersions:  v.GameVersions,
+		Loaders:       v.Loaders,
+		Published:     published,
+		Files:         files,
+	}
+}
+
+func sha1File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha1.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+)
+
+// CurseForgeFingerprint computes CurseForge's file fingerprint: MurmurHash2 with seed 1
+// over the file contents with whitespace bytes (tab, newline, carriage return, space) removed.
+func CurseForgeFingerprint(path string) (uint32, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return 0, err
+	}
+	return murmur2(normalizeFingerprintInput(data), 1), nil
+}
+
+func normalizeFingerprintInput(data []byte) []byte {
+	normalized := make([]byte, 0, len(data))
+	for _, b := range data {
+		if b != 9 && b != 10 && b != 13 && b != 32 {
+			normalized = append(normalized, b)
+		}
+	}
+	return normalized
+}
+
+// murmur2 is the 32-bit MurmurHash2 by Austin Appleby.
+func murmur2(data []byte, seed uint32) uint32 {
+	const (
+		m = 0x5bd1e995
+		r = 24
+	)
+
+	h := seed ^ uint32(len(data))
+	for len(data) >= 4 {
+		k := binary.LittleEndian.Uint32(data)
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+		data = data[4:]
+	}
+
+	switch len(data) {
+	case 3:
+		h ^= uint32(data[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(data[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(data[0])
+		h *= m
+	}
+
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return h
+}
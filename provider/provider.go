@@ -0,0 +1,217 @@
+// Package provider abstracts the sources mods can be installed from, such as Modrinth,
+// GitHub Releases and Maven repositories.
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.uber.org/zap"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// ErrNotSupported is returned when a provider cannot perform an operation, e.g. identifying files by hash.
+var ErrNotSupported = errors.New("operation not supported by provider")
+
+// ErrNotFound is returned when a provider does not know a project, version or file.
+var ErrNotFound = errors.New("not found")
+
+// Provider is a source of installable project files.
+type Provider interface {
+	// Name returns the identifier stored on db.Mod.Provider, e.g. "modrinth".
+	Name() string
+	// ListVersions returns the versions of a project matching the filter, newest first.
+	// ref is the provider-specific project reference stored on db.Mod.Source.
+	ListVersions(ref string, filter Filter) ([]Version, error)
+	// ResolveFile picks the file to install from a version.
+	ResolveFile(v Version) (*File, error)
+	// Download saves a file to the destination path.
+	Download(log *zap.SugaredLogger, destinationPath string, f File) error
+	// IdentifyFile identifies a local file by its hash, returning ErrNotSupported when the provider
+	// has no hash lookup and ErrNotFound when the file is unknown.
+	IdentifyFile(path string) (*Match, error)
+}
+
+// Filter narrows the versions returned by ListVersions.
+type Filter struct {
+	GameVersions []string
+	Loaders      []string
+}
+
+// Version is a release of a project.
+type Version struct {
+	ID            string
+	ProjectID     string
+	VersionNumber string
+	VersionType   string // release, beta or alpha
+	GameVersions  []string
+	Loaders       []string
+	Published     time.Time
+	Files         []File
+	// Unconfirmed is set on versions of sources without structured compatibility data when no version
+	// mentions a filtered game version, so their compatibility is a guess.
+	Unconfirmed bool
+}
+
+// File is a downloadable file of a version.
+type File struct {
+	Filename string
+	URL      string
+	Primary  bool
+	Size     int64
+	Hashes   map[string]string // e.g. "sha1", "sha512"
+}
+
+// Project holds the display metadata of an identified project.
+type Project struct {
+	Ref         string // Provider-specific reference, stored on db.Mod.Source
+	ID          string
+	Slug        string
+	Title       string
+	ProjectType string
+	IconURL     string
+	Color       int
+	ClientSide  string
+	ServerSide  string
+}
+
+// Match is the result of identifying a local file.
+type Match struct {
+	Project Project
+	Version Version
+}
+
+// PrimaryFile returns the file marked primary, or the first file when none is marked.
+func PrimaryFile(v Version) (*File, error) {
+	for i := range v.Files {
+		if v.Files[i].Primary {
+			return &v.Files[i], nil
+		}
+	}
+	if len(v.Files) > 0 {
+		return &v.Files[0], nil
+	}
+	return nil, fmt.Errorf("version %s has no files: %w", v.ID, ErrNotFound)
+}
+
+// knownLoaders are the loader names looked for in the file names and version numbers of sources
+// without structured compatibility data.
+var knownLoaders = []string{"fabric", "quilt", "forge", "neoforge", "paper", "purpur", "spigot", "bukkit", "velocity", "bungeecord", "waterfall"}
+
+// namesLoader reports whether a file name or version number is usable with one of the loaders: it names
+// one of them, or it names no known loader at all. Names are compared as whole words, so "forge" does not
+// match "neoforge".
+func namesLoader(name string, loaders []string) bool {
+	if len(loaders) == 0 {
+		return true
+	}
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	namesOther := false
+	for _, word := range words {
+		if slices.ContainsFunc(loaders, func(l string) bool { return strings.EqualFold(l, word) }) {
+			return true
+		}
+		namesOther = namesOther || slices.Contains(knownLoaders, word)
+	}
+	return !namesOther
+}
+
+// filterMentioned applies a filter to the versions of a source without structured compatibility data,
+// preferring versions whose text mentions a filtered game version or loader. When no version mentions a
+// game version, all of them are kept and marked Unconfirmed. Loaders are checked on the file names and
+// version numbers instead, see namesLoader.
+func filterMentioned(versions []Version, filter Filter, text func(Version) string) []Version {
+	versions, ok := preferMentioned(versions, filter.GameVersions, text)
+	if !ok {
+		for i := range versions {
+			versions[i].Unconfirmed = true
+		}
+	}
+	versions, _ = preferMentioned(versions, filter.Loaders, text)
+	return versions
+}
+
+// preferMentioned narrows versions to those whose text mentions any of the terms. When no version
+// mentions any term, the list is returned unchanged and ok is false.
+func preferMentioned(versions []Version, terms []string, text func(Version) string) (_ []Version, ok bool) {
+	if len(terms) == 0 {
+		return versions, true
+	}
+	var matched []Version
+	for _, v := range versions {
+		haystack := strings.ToLower(text(v))
+		if slices.ContainsFunc(terms, func(term string) bool {
+			return term != "" && strings.Contains(haystack, strings.ToLower(term))
+		}) {
+			matched = append(matched, v)
+		}
+	}
+	if len(matched) == 0 {
+		return versions, false
+	}
+	return matched, true
+}
+
+// httpGet performs a GET request, returning an error for non-2xx responses.
+func httpGet(client *http.Client, url string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, v := range headers {
+		if v != "" {
+			req.Header.Set(k, v)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %w", url, ErrNotFound)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("request to %s failed: status %d, body: %s", url, resp.StatusCode, string(body))
+	}
+	return resp, nil
+}
+
+// downloadTo streams a GET response into destinationPath, creating parent directories as needed.
+func downloadTo(client *http.Client, url, destinationPath string, headers map[string]string) error {
+	if err := os.MkdirAll(filepath.Dir(destinationPath), 0755); err != nil {
+		return fmt.Errorf("failed to create target directory: %w", err)
+	}
+
+	resp, err := httpGet(client, url, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	out, err := os.Create(destinationPath)
+	if err != nil {
+		return fmt.Errorf("failed to create file '%s': %w", destinationPath, err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		os.Remove(destinationPath)
+		return fmt.Errorf("failed to write downloaded content to '%s': %w", destinationPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,427 @@
+package provider
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"modrinth-mod-updater/modrinth"
+
+	"go.uber.org/zap"
+)
+
+func TestModrinthProvider(t *testing.T) {
+	content := []byte("modrinth jar")
+	sum := fmt.Sprintf("%x", sha1Sum(content))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/project/sodium/version", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("game_versions"); got != `["1.21.1"]` {
+			t.Errorf("game_versions = %q, want %q", got, `["1.21.1"]`)
+		}
+		writeJSON(w, []modrinth.Version{{
+			ID: "v2", ProjectID: "AANobbMI", VersionNumber: "0.6.0", DatePublished: "2024-08-01T10:00:00Z",
+			Files: []modrinth.File{{Filename: "sodium.jar", URL: "http://" + r.Host + "/sodium.jar", Primary: true}},
+		}})
+	})
+	mux.HandleFunc("/version_file/"+sum, func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, modrinth.Version{ID: "v1", ProjectID: "AANobbMI", VersionNumber: "0.5.0"})
+	})
+	mux.HandleFunc("/project/AANobbMI", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, modrinth.Project{ID: "AANobbMI", Slug: "sodium", Title: "Sodium", ProjectType: "mod"})
+	})
+	mux.HandleFunc("/sodium.jar", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(content)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := NewModrinth(&modrinth.Client{BaseURL: server.URL, APIKey: "key", UserAgent: "test", HTTPClient: server.Client()})
+
+	versions, err := p.ListVersions("sodium", Filter{GameVersions: []string{"1.21.1"}, Loaders: []string{"fabric"}})
+	if err != nil {
+		t.Fatalf("ListVersions() error = %v", err)
+	}
+	if len(versions) != 1 || versions[0].VersionNumber != "0.6.0" || versions[0].Published.IsZero() {
+		t.Fatalf("ListVersions() = %+v, want one parsed version", versions)
+	}
+	file, err := p.ResolveFile(versions[0])
+	if err != nil || file.Filename != "sodium.jar" {
+		t.Fatalf("ResolveFile() = %+v, %v", file, err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "mods", file.Filename)
+	if err := p.Download(zap.NewNop().Sugar(), dest, *file); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+
+	match, err := p.IdentifyFile(dest)
+	if err != nil {
+		t.Fatalf("IdentifyFile() error = %v", err)
+	}
+	if match.Project.Slug != "sodium" || match.Version.ID != "v1" {
+		t.Errorf("IdentifyFile() = %+v, want sodium v1", match)
+	}
+}
+
+func TestGitHubProvider(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/mod/releases", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("Authorization = %q, want %q", got, "Bearer token")
+		}
+		base := "http://" + r.Host
+		writeJSON(w, []map[string]any{
+			{"id": 3, "tag_name": "v3.0.0", "draft": true, "assets": []map[string]any{{"name": "mod-3.0.0.jar", "browser_download_url": base + "/mod-3.jar"}}},
+			{"id": 2, "tag_name": "v2.0.0+1.21.1", "published_at": "2024-08-01T10:00:00Z", "assets": []map[string]any{
+				{"name": "mod-2.0.0-sources.jar", "browser_download_url": base + "/sources.jar"},
+				{"name": "mod-2.0.0.jar", "size": 4, "browser_download_url": base + "/mod-2.jar"},
+			}},
+			{"id": 1, "tag_name": "v1.0.0+1.20.1", "assets": []map[string]any{{"name": "mod-1.0.0.jar", "browser_download_url": base + "/mod-1.jar"}}},
+		})
+	})
+	mux.HandleFunc("/repos/owner/multi/releases", func(w http.ResponseWriter, r *http.Request) {
+		base := "http://" + r.Host
+		writeJSON(w, []map[string]any{
+			{"id": 5, "tag_name": "mc1.21.1-0.6.0", "assets": []map[string]any{
+				{"name": "multi-neoforge-0.6.0.jar", "browser_download_url": base + "/multi-neoforge.jar"},
+				{"name": "multi-fabric-0.6.0.jar", "browser_download_url": base + "/multi-fabric.jar"},
+			}},
+			{"id": 4, "tag_name": "mc1.21.1-0.5.0", "assets": []map[string]any{
+				{"name": "multi-neoforge-0.5.0.jar", "browser_download_url": base + "/multi-neoforge-old.jar"},
+			}},
+		})
+	})
+	mux.HandleFunc("/mod-2.jar", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("jar2"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := NewGitHub(server.URL, "token", "test")
+
+	all, err := p.ListVersions("owner/mod", Filter{})
+	if err != nil {
+		t.Fatalf("ListVersions() error = %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("ListVersions() returned %d versions, want 2 (drafts skipped)", len(all))
+	}
+
+	versions, err := p.ListVersions("owner/mod", Filter{GameVersions: []string{"1.21.1"}})
+	if err != nil {
+		t.Fatalf("ListVersions() error = %v", err)
+	}
+	if len(versions) != 1 || versions[0].VersionNumber != "2.0.0+1.21.1" {
+		t.Fatalf("ListVersions() = %+v, want only 2.0.0+1.21.1", versions)
+	}
+	file, err := p.ResolveFile(versions[0])
+	if err != nil || file.Filename != "mod-2.0.0.jar" {
+		t.Fatalf("ResolveFile() = %+v, %v, want mod-2.0.0.jar", file, err)
+	}
+
+	dest := filepath.Join(t.TempDir(), file.Filename)
+	if err := p.Download(zap.NewNop().Sugar(), dest, *file); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "jar2" {
+		t.Errorf("downloaded content = %q, want %q", data, "jar2")
+	}
+
+	if _, err := p.IdentifyFile(dest); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("IdentifyFile() error = %v, want ErrNotSupported", err)
+	}
+
+	versions, err = p.ListVersions("owner/multi", Filter{GameVersions: []string{"1.21.1"}, Loaders: []string{"fabric"}})
+	if err != nil {
+		t.Fatalf("ListVersions(multi) error = %v", err)
+	}
+	if len(versions) != 1 || len(versions[0].Files) != 1 || versions[0].Files[0].Filename != "multi-fabric-0.6.0.jar" {
+		t.Fatalf("ListVersions(multi) = %+v, want only the fabric jar of 0.6.0", versions)
+	}
+	if _, err := p.ListVersions("missing/repo", Filter{}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ListVersions(missing) error = %v, want ErrNotFound", err)
+	}
+	if _, err := p.ListVersions("not-a-repo", Filter{}); err == nil {
+		t.Error("ListVersions(not-a-repo) expected error")
+	}
+}
+
+func TestMavenProvider(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/releases/com/example/mymod/maven-metadata.xml", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`<metadata><versioning><release>1.1.0</release>
+<versions><version>1.0.0</version><version>1.1.0</version></versions></versioning></metadata>`))
+	})
+	mux.HandleFunc("/releases/com/example/mymod/1.1.0/mymod-1.1.0.jar", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("jar"))
+	})
+	mux.HandleFunc("/releases/com/example/mymod/1.1.0/mymod-1.1.0.jar.sha1", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ABCDEF  mymod-1.1.0.jar\n"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := NewMaven("test")
+	ref := server.URL + "/releases/com.example:mymod"
+
+	versions, err := p.ListVersions(ref, Filter{GameVersions: []string{"1.21.1"}})
+	if err != nil {
+		t.Fatalf("ListVersions() error = %v", err)
+	}
+	if len(versions) != 2 || versions[0].VersionNumber != "1.1.0" {
+		t.Fatalf("ListVersions() = %+v, want newest first", versions)
+	}
+
+	file, err := p.ResolveFile(versions[0])
+	if err != nil {
+		t.Fatalf("ResolveFile() error = %v", err)
+	}
+	if file.Filename != "mymod-1.1.0.jar" || file.Hashes["sha1"] != "abcdef" {
+		t.Errorf("ResolveFile() = %+v, want mymod-1.1.0.jar with sha1 abcdef", file)
+	}
+
+	dest := filepath.Join(t.TempDir(), file.Filename)
+	if err := p.Download(zap.NewNop().Sugar(), dest, *file); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if _, err := p.IdentifyFile(dest); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("IdentifyFile() error = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestParseMavenRef(t *testing.T) {
+	tests := []struct {
+		ref                         string
+		repository, group, artifact string
+		wantErr                     bool
+	}{
+		{"https://maven.example.com/releases/com.example:mymod", "https://maven.example.com/releases", "com.example", "mymod", false},
+		{"https://maven.example.com/com.example", "", "", "", true},
+		{"com.example:mymod", "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		repository, group, artifact, err := ParseMavenRef(tt.ref)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMavenRef(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			continue
+		}
+		if repository != tt.repository || group != tt.group || artifact != tt.artifact {
+			t.Errorf("ParseMavenRef(%q) = %q, %q, %q, want %q, %q, %q", tt.ref, repository, group, artifact, tt.repository, tt.group, tt.artifact)
+		}
+	}
+}
+
+func TestPreferMentioned(t *testing.T) {
+	versions := []Version{{VersionNumber: "2.0+1.21"}, {VersionNumber: "1.0+1.20"}}
+	number := func(v Version) string { return v.VersionNumber }
+
+	if got, ok := preferMentioned(versions, []string{"1.20"}, number); !ok || len(got) != 1 || got[0].VersionNumber != "1.0+1.20" {
+		t.Errorf("preferMentioned(1.20) = %+v, %v, want only 1.0+1.20", got, ok)
+	}
+	if got, ok := preferMentioned(versions, []string{"fabric"}, number); ok || len(got) != 2 {
+		t.Errorf("preferMentioned(fabric) = %+v, %v, want all versions and false when nothing matches", got, ok)
+	}
+}
+
+func TestFilterMentioned(t *testing.T) {
+	number := func(v Version) string { return v.VersionNumber }
+	versions := []Version{{VersionNumber: "2.0"}, {VersionNumber: "1.0"}}
+
+	got := filterMentioned(slices.Clone(versions), Filter{GameVersions: []string{"1.21.1"}, Loaders: []string{"fabric"}}, number)
+	if len(got) != 2 || !got[0].Unconfirmed || !got[1].Unconfirmed {
+		t.Errorf("filterMentioned() = %+v, want both versions marked unconfirmed", got)
+	}
+	versions = []Version{{VersionNumber: "2.0+1.21.1"}, {VersionNumber: "1.0+1.20.1"}}
+	got = filterMentioned(versions, Filter{GameVersions: []string{"1.21.1"}, Loaders: []string{"fabric"}}, number)
+	if len(got) != 1 || got[0].Unconfirmed {
+		t.Errorf("filterMentioned() = %+v, want only 2.0+1.21.1, confirmed", got)
+	}
+}
+
+func TestNamesLoader(t *testing.T) {
+	tests := []struct {
+		name    string
+		loaders []string
+		want    bool
+	}{
+		{"sodium-fabric-0.6.0.jar", []string{"fabric"}, true},
+		{"sodium-neoforge-0.6.0.jar", []string{"fabric"}, false},
+		{"sodium-neoforge-0.6.0.jar", []string{"forge"}, false},
+		{"jei-forge-19.0.0.jar", []string{"neoforge"}, false},
+		{"mod-1.0.0.jar", []string{"fabric"}, true},
+		{"Mod-Fabric-1.0.jar", []string{"quilt", "fabric"}, true},
+		{"mod-forge-1.0.jar", nil, true},
+	}
+	for _, tt := range tests {
+		if got := namesLoader(tt.name, tt.loaders); got != tt.want {
+			t.Errorf("namesLoader(%q, %v) = %v, want %v", tt.name, tt.loaders, got, tt.want)
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func sha1Sum(data []byte) []byte {
+	h := sha1.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func TestCurseForgeProvider(t *testing.T) {
+	content := []byte("curse jar\n")
+	dir := t.TempDir()
+	local := filepath.Join(dir, "jei.jar")
+	if err := os.WriteFile(local, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	fingerprint, err := CurseForgeFingerprint(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/fingerprints/432", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("x-api-key") != "key" {
+			t.Errorf("fingerprint request = %s with key %q", r.Method, r.Header.Get("x-api-key"))
+		}
+		var req struct {
+			Fingerprints []uint32 `json:"fingerprints"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		matches := []map[string]any{}
+		if len(req.Fingerprints) == 1 && req.Fingerprints[0] == fingerprint {
+			matches = append(matches, map[string]any{"id": 238222, "file": map[string]any{
+				"id": 100, "modId": 238222, "displayName": "jei-1.0.0", "fileName": "jei.jar", "gameVersions": []string{"1.21.1", "NeoForge"},
+			}})
+		}
+		writeJSON(w, map[string]any{"data": map[string]any{"exactMatches": matches}})
+	})
+	mux.HandleFunc("/v1/mods/238222", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]any{"data": map[string]any{"id": 238222, "name": "JEI", "slug": "jei", "classId": 6}})
+	})
+	mux.HandleFunc("/v1/mods/238222/files", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("modLoaderType"); got != "6" {
+			t.Errorf("modLoaderType = %q, want 6", got)
+		}
+		if r.URL.Query().Get("gameVersion") != "1.21.1" {
+			writeJSON(w, map[string]any{"data": []any{}})
+			return
+		}
+		writeJSON(w, map[string]any{"data": []map[string]any{
+			{"id": 100, "modId": 238222, "displayName": "jei-1.0.0", "fileName": "jei-1.0.0.jar", "fileDate": "2024-01-01T00:00:00Z",
+				"downloadUrl": "http://" + r.Host + "/files/jei-1.0.0.jar"},
+			{"id": 101, "modId": 238222, "displayName": "jei-1.1.0", "fileName": "jei-1.1.0.jar", "fileDate": "2024-02-01T00:00:00Z", "releaseType": 2,
+				"downloadUrl": "http://" + r.Host + "/files/jei-1.1.0.jar", "hashes": []map[string]any{{"value": fmt.Sprintf("%x", sha1Sum([]byte("new"))), "algo": 1}}},
+		}})
+	})
+	mux.HandleFunc("/v1/mods/999/files", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]any{"data": []map[string]any{{"id": 5, "modId": 999, "fileName": "locked.jar"}}})
+	})
+	mux.HandleFunc("/files/jei-1.1.0.jar", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("new"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := NewCurseForge(server.URL, "key", "test")
+
+	match, err := p.IdentifyFile(local)
+	if err != nil {
+		t.Fatalf("IdentifyFile() error = %v", err)
+	}
+	if match.Project.Ref != "238222" || match.Project.Slug != "jei" || match.Project.ProjectType != "mod" || match.Version.ID != "100" {
+		t.Errorf("IdentifyFile() = %+v, want jei file 100", match)
+	}
+	if len(match.Version.Loaders) != 1 || match.Version.Loaders[0] != "neoforge" {
+		t.Errorf("IdentifyFile() loaders = %v, want [neoforge]", match.Version.Loaders)
+	}
+
+	unknown := filepath.Join(dir, "unknown.jar")
+	_ = os.WriteFile(unknown, []byte("other"), 0644)
+	if _, err := p.IdentifyFile(unknown); !errors.Is(err, ErrNotFound) {
+		t.Errorf("IdentifyFile(unknown) error = %v, want ErrNotFound", err)
+	}
+
+	versions, err := p.ListVersions("238222", Filter{GameVersions: []string{"1.21.2", "1.21.1"}, Loaders: []string{"neoforge"}})
+	if err != nil {
+		t.Fatalf("ListVersions() error = %v", err)
+	}
+	if len(versions) != 2 || versions[0].ID != "101" || versions[0].VersionType != "beta" {
+		t.Fatalf("ListVersions() = %+v, want newest file 101 first", versions)
+	}
+	file, err := p.ResolveFile(versions[0])
+	if err != nil {
+		t.Fatalf("ResolveFile() error = %v", err)
+	}
+	dest := filepath.Join(dir, "mods", file.Filename)
+	if err := p.Download(zap.NewNop().Sugar(), dest, *file); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if sum, _ := sha1File(dest); sum != file.Hashes["sha1"] {
+		t.Errorf("downloaded sha1 = %s, want %s", sum, file.Hashes["sha1"])
+	}
+
+	locked, err := p.ListVersions("999", Filter{})
+	if err != nil || len(locked) != 1 {
+		t.Fatalf("ListVersions(999) = %+v, %v", locked, err)
+	}
+	if _, err := p.ResolveFile(locked[0]); !errors.Is(err, ErrDistributionNotAllowed) {
+		t.Errorf("ResolveFile(locked) error = %v, want ErrDistributionNotAllowed", err)
+	}
+
+	if _, err := NewCurseForge(server.URL, "", "test").ListVersions("238222", Filter{}); err == nil {
+		t.Error("ListVersions() without API key expected error")
+	}
+}
+
+func TestMurmur2(t *testing.T) {
+	tests := []struct {
+		input string
+		seed  uint32
+		want  uint32
+	}{
+		{"", 0, 0},
+		{"", 1, 1540447798},
+		{"abc", 1, 1621425345},
+		{"hello", 0, 3848350155},
+		{"hello world", 1, 2213174766},
+	}
+
+	for _, tt := range tests {
+		if got := murmur2([]byte(tt.input), tt.seed); got != tt.want {
+			t.Errorf("murmur2(%q, %d) = %d, want %d", tt.input, tt.seed, got, tt.want)
+		}
+	}
+}
+
+func TestCurseForgeFingerprintIgnoresWhitespace(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.jar")
+	b := filepath.Join(dir, "b.jar")
+	_ = os.WriteFile(a, []byte("hello world"), 0644)
+	_ = os.WriteFile(b, []byte("hello\r\n\tworld "), 0644)
+
+	fa, err := CurseForgeFingerprint(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fb, _ := CurseForgeFingerprint(b)
+	if fa != fb {
+		t.Errorf("fingerprints differ: %d != %d", fa, fb)
+	}
+	if want := murmur2([]byte("helloworld"), 1); fa != want {
+		t.Errorf("CurseForgeFingerprint() = %d, want %d", fa, want)
+	}
+}